@@ -0,0 +1,286 @@
+package attachments
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"jotterxpress/internal/adapters/fsutil"
+	"jotterxpress/internal/adapters/repository"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// DirName is the directory of the notes directory holding attachments
+const DirName = ".attachments"
+
+// Store keeps attachment content in a directory, one file per distinct
+// content, named after its SHA-256 and spread over subdirectories by the
+// first two hex digits
+type Store struct {
+	dir string
+
+	unlock     func() (*repository.Cipher, error)
+	unlockOnce sync.Once
+	cipher     *repository.Cipher
+	unlockErr  error
+}
+
+// NewStore creates a store of plaintext attachments in dir
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// NewEncryptedStore creates a store that seals attachments before they reach
+// dir. unlock is called once, the first time content has to be sealed or
+// opened. Content stored before encryption was enabled is read as it is.
+func NewEncryptedStore(dir string, unlock func() (*repository.Cipher, error)) *Store {
+	return &Store{dir: dir, unlock: unlock}
+}
+
+// getCipher unlocks the store on first use
+func (s *Store) getCipher() (*repository.Cipher, error) {
+	s.unlockOnce.Do(func() {
+		s.cipher, s.unlockErr = s.unlock()
+		if s.unlockErr != nil {
+			s.unlockErr = fmt.Errorf("failed to unlock attachments: %w", s.unlockErr)
+		}
+	})
+	return s.cipher, s.unlockErr
+}
+
+// path returns the file holding the content with the given SHA-256
+func (s *Store) path(hash string) (string, error) {
+	if len(hash) != sha256.Size*2 {
+		return "", fmt.Errorf("invalid attachment hash %q", hash)
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return "", fmt.Errorf("invalid attachment hash %q", hash)
+	}
+	return filepath.Join(s.dir, hash[:2], hash), nil
+}
+
+// Put stores content and returns its SHA-256 and size
+func (s *Store) Put(content io.Reader) (string, int64, error) {
+	if s.unlock != nil {
+		return s.putSealed(content)
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return "", 0, err
+	}
+
+	// Hash while copying, so large files are read once
+	tmp, err := os.CreateTemp(s.dir, ".put.tmp-*")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	sum := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, sum), content)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	hash := hex.EncodeToString(sum.Sum(nil))
+	target, _ := s.path(hash)
+	if _, err := os.Stat(target); err == nil {
+		return hash, size, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", 0, err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return "", 0, err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", 0, err
+	}
+
+	return hash, size, nil
+}
+
+// putSealed seals content with the notebook key and stores it under the
+// SHA-256 of the plaintext
+func (s *Store) putSealed(content io.Reader) (string, int64, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return "", 0, err
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	target, _ := s.path(hash)
+	if existing, err := os.ReadFile(target); err == nil && repository.IsSealed(existing) {
+		return hash, int64(len(data)), nil
+	}
+
+	if err := s.writeSealed(target, hash, data); err != nil {
+		return "", 0, err
+	}
+
+	return hash, int64(len(data)), nil
+}
+
+// writeSealed seals data, bound to its hash, and writes it to target
+func (s *Store) writeSealed(target, hash string, data []byte) error {
+	cipher, err := s.getCipher()
+	if err != nil {
+		return err
+	}
+
+	sealed, err := cipher.Seal(data, hash)
+	if err != nil {
+		return err
+	}
+
+	return fsutil.WriteFileAtomic(target, []byte(sealed))
+}
+
+// Open returns the content stored under a SHA-256
+func (s *Store) Open(hash string) (io.ReadCloser, error) {
+	path, err := s.path(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("content %s is missing from the attachment store", hash[:12])
+		}
+		return nil, err
+	}
+	if s.unlock == nil {
+		return file, nil
+	}
+
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		return nil, err
+	}
+	if !repository.IsSealed(data) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	cipher, err := s.getCipher()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := cipher.Open(string(data), hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt attachment %s: %w", hash[:12], err)
+	}
+
+	return io.NopCloser(bytes.NewReader(plaintext)), nil
+}
+
+// List returns the SHA-256 of every stored content, sorted
+func (s *Store) List() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "[0-9a-f][0-9a-f]", "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var hashes []string
+	for _, path := range paths {
+		hash := filepath.Base(path)
+		if want, err := s.path(hash); err != nil || want != path {
+			continue
+		}
+		hashes = append(hashes, hash)
+	}
+
+	sort.Strings(hashes)
+	return hashes, nil
+}
+
+// Remove deletes the content stored under a SHA-256
+func (s *Store) Remove(hash string) error {
+	path, err := s.path(hash)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// Leave no empty fan-out directories behind
+	os.Remove(filepath.Dir(path))
+	return nil
+}
+
+// SealAll seals the content stored before encryption was enabled and
+// returns how many files it sealed. It only applies to encrypted stores.
+func (s *Store) SealAll() (int, error) {
+	if s.unlock == nil {
+		return 0, fmt.Errorf("the attachment store is not encrypted")
+	}
+
+	hashes, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+
+	sealed := 0
+	for _, hash := range hashes {
+		path, _ := s.path(hash)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return sealed, err
+		}
+		if repository.IsSealed(data) {
+			continue
+		}
+
+		if err := s.writeSealed(path, hash, data); err != nil {
+			return sealed, fmt.Errorf("failed to seal attachment %s: %w", hash[:12], err)
+		}
+		sealed++
+	}
+
+	return sealed, nil
+}
+
+// Import copies the files of another store that this one lacks, as they are
+// stored, and returns how many it copied. Sealed content stays sealed, so
+// both stores must use the same key.
+func (s *Store) Import(from *Store) (int, error) {
+	hashes, err := from.List()
+	if err != nil {
+		return 0, err
+	}
+
+	copied := 0
+	for _, hash := range hashes {
+		target, _ := s.path(hash)
+		if _, err := os.Stat(target); err == nil {
+			continue
+		}
+
+		source, _ := from.path(hash)
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return copied, err
+		}
+		if err := fsutil.WriteFileAtomic(target, data); err != nil {
+			return copied, err
+		}
+		copied++
+	}
+
+	return copied, nil
+}
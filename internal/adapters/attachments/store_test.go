@@ -0,0 +1,82 @@
+package attachments
+
+import (
+	"bytes"
+	"io"
+	"jotterxpress/internal/adapters/repository"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptedStoreSealsContent(t *testing.T) {
+	dir := t.TempDir()
+	unlock := func() (*repository.Cipher, error) {
+		return repository.NewCipher(bytes.Repeat([]byte{7}, 32))
+	}
+
+	// Content stored before encryption is read as it is, then sealed
+	plainHash, _, err := NewStore(dir).Put(strings.NewReader("before"))
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	store := NewEncryptedStore(dir, unlock)
+	hash, size, err := store.Put(strings.NewReader("secret screenshot"))
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if size != int64(len("secret screenshot")) {
+		t.Errorf("size is %d, want %d", size, len("secret screenshot"))
+	}
+
+	sealed, err := store.SealAll()
+	if err != nil {
+		t.Fatalf("SealAll failed: %v", err)
+	}
+	if sealed != 1 {
+		t.Errorf("sealed %d files, want 1", sealed)
+	}
+
+	for hash, want := range map[string]string{hash: "secret screenshot", plainHash: "before"} {
+		data, err := os.ReadFile(filepath.Join(dir, hash[:2], hash))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !repository.IsSealed(data) || bytes.Contains(data, []byte(want)) {
+			t.Errorf("%s is stored in plaintext", want)
+		}
+
+		content, err := store.Open(hash)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		got, _ := io.ReadAll(content)
+		content.Close()
+		if string(got) != want {
+			t.Errorf("opened %q, want %q", got, want)
+		}
+	}
+
+	// A copy keeps the content sealed and readable with the same key
+	copyDir := t.TempDir()
+	if copied, err := NewEncryptedStore(copyDir, unlock).Import(store); err != nil || copied != 2 {
+		t.Fatalf("Import copied %d files: %v", copied, err)
+	}
+	if _, err := NewEncryptedStore(copyDir, unlock).Open(hash); err != nil {
+		t.Errorf("Open of imported content failed: %v", err)
+	}
+}
+
+func TestStoreRejectsInvalidHashes(t *testing.T) {
+	store := NewStore(t.TempDir())
+	for _, hash := range []string{"", "../../etc/passwd", strings.Repeat("z", 64)} {
+		if _, err := store.Open(hash); err == nil {
+			t.Errorf("Open accepted %q", hash)
+		}
+		if err := store.Remove(hash); err == nil {
+			t.Errorf("Remove accepted %q", hash)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"jotterxpress/internal/adapters/fsutil"
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/cobra"
+)
+
+// newAttachCommand creates the attach command
+func (cli *CLI) newAttachCommand() *cobra.Command {
+	attachCmd := &cobra.Command{
+		Use:   "attach <id> <file>",
+		Short: "Attach a file to a note by ID or unique ID prefix",
+		Long: "Copy a file into the attachment store of the notebook and record it on the note. " +
+			"Identical files are stored once, however many notes attach them.",
+		Args: cobra.ExactArgs(2),
+		Run:  cli.attachFile,
+	}
+	attachCmd.Flags().String("name", "", "Name to record instead of the file name")
+
+	return attachCmd
+}
+
+// newAttachmentsCommand creates the attachments command
+func (cli *CLI) newAttachmentsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "attachments <id>",
+		Short: "List the attachments of a note",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			note := cli.resolveNote(args[0])
+			if len(note.Metadata.Attachments) == 0 {
+				fmt.Println(infoStyle.Render(fmt.Sprintf("Note %s has no attachments.", note.ID)))
+				fmt.Println(infoStyle.Render(fmt.Sprintf("Add one with: jtx attach %s <file>", note.ID)))
+				return
+			}
+
+			fmt.Println(titleStyle.Render(fmt.Sprintf(" Attachments of %s ", note.ID)))
+			fmt.Println()
+			for i, attachment := range note.Metadata.Attachments {
+				fmt.Printf("  %d. %s  %s  %s  %s\n", i+1, attachment.Name, formatSize(attachment.Size),
+					attachment.AddedAt.Format("2006-01-02 15:04"), shortHash(attachment.SHA256))
+			}
+		},
+	}
+}
+
+// newOpenAttachmentCommand creates the open-attachment command
+func (cli *CLI) newOpenAttachmentCommand() *cobra.Command {
+	openCmd := &cobra.Command{
+		Use:   "open-attachment <id> <attachment>",
+		Short: "Open or extract an attachment of a note",
+		Long: "Open an attachment with the default application of the system. The attachment is " +
+			"given by its number in 'jtx attachments' or by its name. With --output, write it to a " +
+			"file instead, or to standard output with --output -.",
+		Args: cobra.ExactArgs(2),
+		Run:  cli.openAttachment,
+	}
+	openCmd.Flags().StringP("output", "o", "", "Write the attachment to this file, or - for standard output")
+
+	return openCmd
+}
+
+// attachFile attaches a file to a note
+func (cli *CLI) attachFile(cmd *cobra.Command, args []string) {
+	name, _ := cmd.Flags().GetString("name")
+	note := cli.resolveNote(args[0])
+
+	file, err := os.Open(args[1])
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error opening file: %v", err)))
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	if info, err := file.Stat(); err == nil && info.IsDir() {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %s is a directory", args[1])))
+		os.Exit(1)
+	}
+	if name == "" {
+		name = filepath.Base(args[1])
+	}
+
+	attachment, err := cli.attachmentService.Attach(note, name, file)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error attaching file: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Attached %s (%s) to note %s", attachment.Name, formatSize(attachment.Size), note.ID)))
+}
+
+// openAttachment opens an attachment with the system viewer or extracts it
+func (cli *CLI) openAttachment(cmd *cobra.Command, args []string) {
+	output, _ := cmd.Flags().GetString("output")
+	note := cli.resolveNote(args[0])
+
+	attachment, content, err := cli.attachmentService.OpenAttachment(note, args[1])
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+	defer content.Close()
+
+	if output == "-" {
+		if _, err := io.Copy(os.Stdout, content); err != nil {
+			fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Error writing attachment: %v", err)))
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Without --output, extract next to other opened attachments so the
+	// viewer gets a file with the original name
+	open := output == ""
+	if open {
+		output = filepath.Join(os.TempDir(), "jtx-attachments", shortHash(attachment.SHA256), attachment.Name)
+	}
+
+	data, err := io.ReadAll(content)
+	if err == nil {
+		err = fsutil.WriteFileAtomic(output, data)
+	}
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error extracting attachment: %v", err)))
+		os.Exit(1)
+	}
+
+	if !open {
+		fmt.Println(successStyle.Render(fmt.Sprintf("Extracted %s to %s", attachment.Name, output)))
+		return
+	}
+
+	if err := openWithSystem(output); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error opening attachment: %v", err)))
+		fmt.Println(infoStyle.Render("It was extracted to " + output))
+		os.Exit(1)
+	}
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Opened %s", output)))
+}
+
+// openWithSystem opens a file with the default application of the system
+func openWithSystem(path string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", path)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+	return cmd.Start()
+}
+
+// formatSize formats a size in bytes for display, e.g. "1.5 MB"
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// shortHash abbreviates a SHA-256 for display
+func shortHash(hash string) string {
+	return hash[:min(len(hash), 12)]
+}
+
+// attachmentLines describes the attachments of a note, one per line
+func attachmentLines(attachments []entities.Attachment) []string {
+	lines := make([]string, 0, len(attachments))
+	for i, attachment := range attachments {
+		lines = append(lines, fmt.Sprintf("%d. %s (%s)", i+1, attachment.Name, formatSize(attachment.Size)))
+	}
+	return lines
+}
@@ -0,0 +1,310 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"jotterxpress/internal/adapters/attachments"
+	"jotterxpress/internal/adapters/repository"
+	"jotterxpress/internal/adapters/search"
+	"jotterxpress/internal/domain/ports"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// snapshotExt is the file extension of backup snapshots
+const snapshotExt = ".tar.gz"
+
+// snapshotStamp is the time in snapshot names; the milliseconds keep
+// snapshots taken in quick succession apart
+const snapshotStamp = "20060102T150405.000"
+
+// newBackupCommand creates the backup command
+func (cli *CLI) newBackupCommand() *cobra.Command {
+	backupCmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Write a compressed snapshot of the current notebook",
+		Long: "Write a timestamped .tar.gz archive of the notes directory, with a manifest of the " +
+			"note count and a checksum of every file. Snapshots go to backups/<notebook> in the jtx " +
+			"home directory unless --dir is given.",
+		Args: cobra.NoArgs,
+		Run:  cli.backupNotebook,
+	}
+	backupCmd.Flags().Int("keep", 0, "Keep only this many snapshots of the notebook, removing the oldest (0 keeps all)")
+	backupCmd.Flags().String("dir", "", "Directory to write the snapshot to")
+
+	return backupCmd
+}
+
+// newRestoreCommand creates the restore command
+func (cli *CLI) newRestoreCommand() *cobra.Command {
+	restoreCmd := &cobra.Command{
+		Use:   "restore <archive>",
+		Short: "Restore the current notebook from a backup snapshot",
+		Long: "Verify a snapshot written by 'jtx backup' and restore it. By default the notebook is " +
+			"replaced by the snapshot, after taking a snapshot of its current state. With --merge, " +
+			"notes are combined by ID and the most recently updated version of each note wins.",
+		Args: cobra.ExactArgs(1),
+		Run:  cli.restoreNotebook,
+	}
+	restoreCmd.Flags().Bool("merge", false, "Merge the snapshot into the notebook instead of replacing it")
+
+	return restoreCmd
+}
+
+// backupNotebook writes a snapshot of the current notebook and rotates old ones
+func (cli *CLI) backupNotebook(cmd *cobra.Command, args []string) {
+	keep, _ := cmd.Flags().GetInt("keep")
+	dir, _ := cmd.Flags().GetString("dir")
+
+	if keep < 0 {
+		fmt.Println(errorStyle.Render("Error: --keep cannot be negative"))
+		os.Exit(1)
+	}
+	if dir == "" {
+		dir = cli.snapshotDir()
+	}
+
+	path, manifest, err := cli.createSnapshot(dir, "")
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error writing backup: %v", err)))
+		os.Exit(1)
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("Backed up %d notes and %d trashed notes to %s", manifest.Notes, manifest.Trashed, path)))
+
+	if keep == 0 {
+		return
+	}
+
+	removed, err := pruneSnapshots(dir, cli.notebook, keep)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error removing old snapshots: %v", err)))
+		os.Exit(1)
+	}
+	if removed > 0 {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Removed %d old snapshots, keeping the newest %d.", removed, keep)))
+	}
+}
+
+// restoreNotebook verifies a snapshot and replaces or merges it into the current notebook
+func (cli *CLI) restoreNotebook(cmd *cobra.Command, args []string) {
+	merge, _ := cmd.Flags().GetBool("merge")
+	archive := args[0]
+
+	// Extract next to the notes, so replacing them is a rename
+	stagingRoot := filepath.Join(cli.notesDir, repository.BackupDirName)
+	if err := os.MkdirAll(stagingRoot, 0755); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error creating staging directory: %v", err)))
+		os.Exit(1)
+	}
+	staging, err := os.MkdirTemp(stagingRoot, "restore-")
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error creating staging directory: %v", err)))
+		os.Exit(1)
+	}
+
+	err = cli.restoreSnapshot(archive, staging, merge)
+	os.RemoveAll(staging)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+
+	// Notes changed behind the search index; it is rebuilt on the next search
+	if err := search.RemoveIndex(filepath.Join(cli.notesDir, ".search-index.json")); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Warning: failed to remove search index: %v", err)))
+	}
+
+	if cli.gitRepo.Enabled() {
+		if err := cli.gitRepo.Commit("restore " + filepath.Base(archive)); err != nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Warning: failed to commit change: %v", err)))
+		}
+	}
+}
+
+// restoreSnapshot extracts and verifies a snapshot into staging, then merges
+// it into the notebook or replaces the notebook with it
+func (cli *CLI) restoreSnapshot(archive, staging string, merge bool) error {
+	manifest, err := repository.ExtractSnapshot(archive, staging)
+	if err != nil {
+		return err
+	}
+
+	source, err := openRepository(manifest.Storage, staging)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	closeSource := func() {
+		if closer, ok := source.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+	defer closeSource()
+
+	// The files are intact; check that they also hold the notes they should
+	notes, err := source.GetAllNotes()
+	if err != nil {
+		return fmt.Errorf("%s failed verification: %w", archive, err)
+	}
+	if len(notes) != manifest.Notes {
+		return fmt.Errorf("%s failed verification: it holds %d notes, the manifest lists %d", archive, len(notes), manifest.Notes)
+	}
+
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Verified %s: %d notes and %d trashed notes of notebook %s from %s.",
+		filepath.Base(archive), manifest.Notes, manifest.Trashed, manifest.Notebook, manifest.CreatedAt.Format("2006-01-02 15:04"))))
+
+	if merge {
+		return cli.mergeSnapshot(source, staging)
+	}
+
+	closeSource()
+	return cli.replaceWithSnapshot(staging, manifest.Storage)
+}
+
+// mergeSnapshot merges the notes of an extracted snapshot into the notebook
+func (cli *CLI) mergeSnapshot(source ports.NoteRepository, staging string) error {
+	// Notes are merged as stored, so sealed notes must open with the same key
+	if !repository.SameEncryption(staging, cli.notesDir) {
+		return fmt.Errorf("the backup and the notebook are not encrypted with the same passphrase; restore it without --merge")
+	}
+
+	if err := upgradeStorage(source); err != nil {
+		return fmt.Errorf("failed to upgrade backup storage: %w", err)
+	}
+
+	stats, err := repository.Merge(cli.repository, source)
+	if err != nil {
+		return fmt.Errorf("failed to merge backup: %w", err)
+	}
+
+	// Merged notes may refer to attachments only the backup has
+	if _, err := cli.attachmentStore.Import(attachments.NewStore(filepath.Join(staging, attachments.DirName))); err != nil {
+		return fmt.Errorf("failed to merge backup attachments: %w", err)
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Merged backup: %d notes added, %d updated, %d kept, %d trashed notes added.",
+		stats.Added, stats.Updated, stats.Kept, stats.Trashed)))
+	return nil
+}
+
+// replaceWithSnapshot replaces the notes directory with an extracted
+// snapshot, after taking a snapshot of the current notes. Git data and
+// schema backups stay in place.
+func (cli *CLI) replaceWithSnapshot(staging, storage string) error {
+	saved, _, err := cli.createSnapshot(cli.snapshotDir(), "-pre-restore")
+	if err != nil {
+		return fmt.Errorf("failed to back up the current notes, nothing was restored: %w", err)
+	}
+	fmt.Println(infoStyle.Render("Saved the current notes to " + saved))
+
+	if closer, ok := cli.repository.(io.Closer); ok {
+		closer.Close()
+	}
+
+	entries, err := os.ReadDir(cli.notesDir)
+	if err != nil {
+		return fmt.Errorf("failed to read notes directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.Name() == repository.BackupDirName || entry.Name() == ".git" {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(cli.notesDir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to remove %s (the previous notes are in %s): %w", entry.Name(), saved, err)
+		}
+	}
+
+	staged, err := os.ReadDir(staging)
+	if err != nil {
+		return fmt.Errorf("failed to read backup (the previous notes are in %s): %w", saved, err)
+	}
+	for _, entry := range staged {
+		if err := os.Rename(filepath.Join(staging, entry.Name()), filepath.Join(cli.notesDir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to restore %s (the previous notes are in %s): %w", entry.Name(), saved, err)
+		}
+	}
+
+	// The snapshot brings its own storage backend
+	if storage != cli.config.NotebookStorage(cli.notebook) {
+		if err := cli.config.SetNotebookStorage(cli.notebook, storage); err != nil {
+			return err
+		}
+		if err := cli.config.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Storage is now %s, as in the backup.", storage)))
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Restored notebook %s from backup.", cli.notebook)))
+	return nil
+}
+
+// snapshotDir returns the default directory for snapshots of the current notebook
+func (cli *CLI) snapshotDir() string {
+	return filepath.Join(cli.config.Home(), "backups", cli.notebook)
+}
+
+// createSnapshot writes a snapshot of the current notebook to dir, named
+// after the notebook and the current time, and returns its path
+func (cli *CLI) createSnapshot(dir, suffix string) (string, *repository.SnapshotManifest, error) {
+	// Names sort in the order snapshots were taken, so when one was just
+	// taken under this name wait for the next millisecond
+	var path string
+	for {
+		name := fmt.Sprintf("%s-%s%s%s", cli.notebook, time.Now().Format(snapshotStamp), suffix, snapshotExt)
+		path = filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	manifest, err := repository.CreateSnapshot(path, cli.notesDir, cli.repository, cli.notebook, cli.config.NotebookStorage(cli.notebook))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return path, manifest, nil
+}
+
+// pruneSnapshots removes all but the newest keep snapshots of a notebook from
+// dir and returns how many were removed. Other files are never touched.
+func pruneSnapshots(dir, notebook string, keep int) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var snapshots []string
+	for _, entry := range entries {
+		stamp, ok := strings.CutPrefix(entry.Name(), notebook+"-")
+		if !ok || !strings.HasSuffix(stamp, snapshotExt) || len(stamp) < len("20060102T150405") {
+			continue
+		}
+		// Requiring the timestamp keeps notebook "work" away from "work-old"
+		if _, err := time.Parse("20060102T150405", stamp[:len("20060102T150405")]); err != nil {
+			continue
+		}
+		snapshots = append(snapshots, entry.Name())
+	}
+
+	if len(snapshots) <= keep {
+		return 0, nil
+	}
+
+	// Timestamped names sort oldest first
+	sort.Strings(snapshots)
+	removed := 0
+	for _, name := range snapshots[:len(snapshots)-keep] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
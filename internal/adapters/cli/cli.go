@@ -1,15 +1,21 @@
 package cli
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"jotterxpress/internal/adapters/attachments"
+	"jotterxpress/internal/adapters/config"
+	"jotterxpress/internal/adapters/git"
+	"jotterxpress/internal/adapters/hooks"
 	"jotterxpress/internal/adapters/repository"
+	"jotterxpress/internal/adapters/search"
 	"jotterxpress/internal/application/services"
 	"jotterxpress/internal/domain/entities"
 	"jotterxpress/internal/domain/ports"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -40,27 +46,117 @@ var (
 
 // CLI represents the command line interface
 type CLI struct {
-	noteService ports.NoteService
-	repository  ports.NoteRepository
+	noteService       ports.NoteService
+	attachmentService ports.AttachmentService
+	repository        ports.NoteRepository
+	store             ports.NoteRepository // repository as the service sees it, decrypted if needed
+	attachmentStore   *attachments.Store
+	unlock            func() (*repository.Cipher, error) // nil unless the notebook is encrypted
+	searchIndex       *search.Index
+	gitRepo           *git.Repo
+	config            *config.Config
+	notebook          string
+	notesDir          string
 }
 
 // NewCLI creates a new CLI instance
 func NewCLI() *CLI {
-	// Get home directory for notes storage
-	homeDir, err := os.UserHomeDir()
+	cfg, err := config.Load(filepath.Join(config.HomeDir(), "config"))
 	if err != nil {
-		homeDir = "."
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error loading config: %v", err)))
+		os.Exit(1)
 	}
 
-	notesDir := filepath.Join(homeDir, ".jotterxpress", "notes")
+	return &CLI{config: cfg}
+}
+
+// openNotebook creates the repository and service for a notebook
+func (cli *CLI) openNotebook(name string) {
+	if !cli.config.HasNotebook(name) {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: notebook %q does not exist", name)))
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Create it with: jtx notebook create %s", name)))
+		os.Exit(1)
+	}
+
+	notesDir := cli.config.NotebookDir(name)
+	storage := cli.config.NotebookStorage(name)
 
 	// Create repository and service
-	noteRepo := repository.NewFileRepository(notesDir)
-	noteService := services.NewNoteService(noteRepo)
+	noteRepo, err := openRepository(storage, notesDir)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error opening %s storage: %v", storage, err)))
+		os.Exit(1)
+	}
+
+	cli.notebook = name
+	cli.notesDir = notesDir
+
+	// Encrypted notebooks are decrypted on the way out, and their search
+	// index is kept in memory so no note text reaches the disk
+	store := noteRepo
+	encrypted := repository.EncryptionEnabled(notesDir)
+	searchIndex := search.NewIndex(filepath.Join(notesDir, ".search-index.json"))
+	attachmentStore := attachments.NewStore(filepath.Join(notesDir, attachments.DirName))
+	var unlock func() (*repository.Cipher, error)
+	if encrypted {
+		// Notes and attachments share the key, asked for at most once
+		unlock = sync.OnceValues(cli.unlockNotebook)
+		store = repository.NewEncryptedRepository(noteRepo, unlock)
+		searchIndex = search.NewIndex("")
+		attachmentStore = attachments.NewEncryptedStore(filepath.Join(notesDir, attachments.DirName), unlock)
+	}
+
+	// Remove attachments nothing refers to after deletions, before the
+	// change is committed
+	listeners := []ports.NoteListener{
+		services.NewAttachmentCollector(store, attachmentStore, func(err error) {
+			fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Warning: failed to remove unused attachments: %v", err)))
+		}),
+	}
+
+	// Commit every change when versioning is enabled for the notebook
+	gitRepo := git.NewRepo(notesDir)
+	if gitRepo.Enabled() && storage == config.StorageFiles {
+		listeners = append(listeners, git.NewListener(gitRepo, !encrypted, func(err error) {
+			fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Warning: failed to commit change: %v", err)))
+		}))
+	}
+
+	// Run user hook scripts last, once the change is committed
+	listeners = append(listeners, hooks.NewRunner(filepath.Join(cli.config.Home(), hooks.DirName), name, os.Stderr, func(err error) {
+		fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Warning: %v", err)))
+	}))
+
+	// Keep the search index up to date with every change made through the service
+	cli.noteService = services.NewNoteService(search.NewIndexedRepository(store, searchIndex), listeners...)
+	cli.attachmentService = services.NewAttachmentService(cli.noteService, store, attachmentStore)
+	cli.repository = noteRepo
+	cli.store = store
+	cli.attachmentStore = attachmentStore
+	cli.unlock = unlock
+	cli.searchIndex = searchIndex
+	cli.gitRepo = gitRepo
+}
 
-	return &CLI{
-		noteService: noteService,
-		repository:  noteRepo,
+// notebookName returns the notebook selected with --notebook, or the current one
+func (cli *CLI) notebookName(cmd *cobra.Command) string {
+	if notebook, _ := cmd.Flags().GetString("notebook"); notebook != "" {
+		return notebook
+	}
+	return cli.config.Notebook
+}
+
+// openRepository creates the repository for the given storage backend
+func openRepository(storage, notesDir string) (ports.NoteRepository, error) {
+	switch storage {
+	case config.StorageSQLite:
+		return repository.NewSQLiteRepository(filepath.Join(notesDir, "notes.db"))
+	default:
+		repo := repository.NewFileRepository(notesDir)
+		repo.OnCorruptFile(func(err error) {
+			fmt.Fprintln(os.Stderr, errorStyle.Render(fmt.Sprintf("Warning: %v", err)))
+		})
+		return repo, nil
 	}
 }
 
@@ -72,10 +168,25 @@ func (cli *CLI) SetupCommands() *cobra.Command {
 		Long:  "A fast and simple CLI tool for taking notes.",
 		Args:  cobra.ArbitraryArgs,
 		Run:   cli.addNote,
-	}
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			cli.openNotebook(cli.notebookName(cmd))
+
+			if err := checkStorage(cli.repository); err != nil {
+				if errors.Is(err, errMigrationsPending) {
+					fmt.Println(errorStyle.Render(fmt.Sprintf("This notebook needs a storage upgrade (%v).", err)))
+					fmt.Println(infoStyle.Render("Review it with 'jtx migrate --dry-run', then apply it with 'jtx migrate'."))
+				} else {
+					fmt.Println(errorStyle.Render(fmt.Sprintf("Error checking storage: %v", err)))
+					fmt.Println(infoStyle.Render("See what is pending with: jtx migrate --dry-run"))
+				}
+				os.Exit(1)
+			}
+		},
+	}
+	rootCmd.PersistentFlags().String("notebook", "", "Notebook to use instead of the current one")
 
 	// Add flags for all commands
-	var listFlag, noteFlag, taskFlag, contactFlag, reminderFlag, interactiveFlag bool
+	var listFlag, noteFlag, taskFlag, contactFlag, reminderFlag, ideaFlag, interactiveFlag bool
 	var listDateStr, listMonthStr string
 	rootCmd.Flags().BoolVarP(&listFlag, "list", "l", false, "List today's notes")
 	rootCmd.Flags().StringVar(&listDateStr, "list-date", "", "List notes for a specific date (format: YYYY-MM-DD)")
@@ -84,11 +195,25 @@ func (cli *CLI) SetupCommands() *cobra.Command {
 	rootCmd.Flags().BoolVarP(&taskFlag, "task", "t", false, "Create a new task (interactive mode)")
 	rootCmd.Flags().BoolVarP(&contactFlag, "contact", "c", false, "Create a new contact (interactive mode)")
 	rootCmd.Flags().BoolVarP(&reminderFlag, "reminder", "r", false, "Create a new reminder (interactive mode)")
+	rootCmd.Flags().BoolVarP(&ideaFlag, "idea", "I", false, "Create a new idea (interactive mode)")
 	rootCmd.Flags().BoolVarP(&interactiveFlag, "interactive", "i", false, "Open interactive list view")
+	rootCmd.Flags().StringSlice("tag", nil, "Tag a new note, or list only notes with this tag (repeatable)")
+	rootCmd.Flags().String("project", "", "Put a new note in a project, or list only notes in it and its subprojects")
 
 	// Override the Run function to handle flags
 	rootCmd.Run = cli.handleRootCommand
 
+	// Add subcommands
+	rootCmd.AddCommand(cli.newTrashCommand(), cli.newDoctorCommand(), cli.newStorageCommand(), cli.newMigrateCommand(), cli.newSearchCommand())
+	rootCmd.AddCommand(cli.newBackupCommand(), cli.newRestoreCommand())
+	rootCmd.AddCommand(cli.newNotebookCommand(), cli.newVersioningCommand(), cli.newLogCommand(), cli.newSyncCommand())
+	rootCmd.AddCommand(cli.newEncryptCommand(), cli.newDecryptCommand(), cli.newLockCommand())
+	rootCmd.AddCommand(cli.newShowCommand(), cli.newEditCommand(), cli.newDoneCommand(), cli.newMoveCommand(), cli.newRmCommand())
+	rootCmd.AddCommand(cli.newHistoryCommand(), cli.newDiffCommand(), cli.newRevertCommand())
+	rootCmd.AddCommand(cli.newAttachCommand(), cli.newAttachmentsCommand(), cli.newOpenAttachmentCommand())
+	rootCmd.AddCommand(cli.newIdeaCommand(), cli.newPromoteCommand(), cli.newTagsCommand(), cli.newTagCommand())
+	rootCmd.AddCommand(cli.newProjectCommand(), cli.newOverdueCommand(), cli.newDueCommand(), cli.newRecurringCommand())
+
 	return rootCmd
 }
 
@@ -102,6 +227,7 @@ func (cli *CLI) handleRootCommand(cmd *cobra.Command, args []string) {
 	taskFlag, _ := cmd.Flags().GetBool("task")
 	contactFlag, _ := cmd.Flags().GetBool("contact")
 	reminderFlag, _ := cmd.Flags().GetBool("reminder")
+	ideaFlag, _ := cmd.Flags().GetBool("idea")
 	interactiveFlag, _ := cmd.Flags().GetBool("interactive")
 
 	// Count how many flags are set
@@ -127,6 +253,9 @@ func (cli *CLI) handleRootCommand(cmd *cobra.Command, args []string) {
 	if reminderFlag {
 		flagCount++
 	}
+	if ideaFlag {
+		flagCount++
+	}
 	if interactiveFlag {
 		flagCount++
 	}
@@ -167,6 +296,10 @@ func (cli *CLI) handleRootCommand(cmd *cobra.Command, args []string) {
 		cli.createReminderInteractive()
 		return
 	}
+	if ideaFlag {
+		cli.createIdeaInteractive()
+		return
+	}
 	if interactiveFlag {
 		cli.openInteractiveList(cmd, args)
 		return
@@ -200,10 +333,11 @@ func (cli *CLI) addNote(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	content := strings.Join(args, " ")
+	note := entities.NewNote(strings.Join(args, " "))
+	note.Metadata.Tags = tagsFlag(cmd)
+	note.Metadata.Category = projectFlag(cmd)
 
-	_, err := cli.noteService.CreateNote(content)
-	if err != nil {
+	if err := cli.noteService.SaveNote(note); err != nil {
 		fmt.Println(errorStyle.Render(fmt.Sprintf("Error creating note: %v", err)))
 		os.Exit(1)
 	}
@@ -263,9 +397,22 @@ func (cli *CLI) listTodayNotes(cmd *cobra.Command, args []string) {
 		fmt.Println(errorStyle.Render(fmt.Sprintf("Error retrieving notes: %v", err)))
 		os.Exit(1)
 	}
+	notes = filterByTags(notes, tagsFlag(cmd))
+	notes = filterByProject(notes, projectFlag(cmd))
+
+	// Tasks filed under earlier dates do not show up today, so point at them
+	title := "Today's Notes"
+	overdue, err := cli.noteService.GetDueTasks(-1)
+	if err == nil && len(overdue) > 0 {
+		title = fmt.Sprintf("Today's Notes · overdue tasks: %d (jtx overdue)", len(overdue))
+	}
 
 	if len(notes) == 0 {
 		fmt.Println(infoStyle.Render("No notes found for today."))
+		if len(overdue) > 0 {
+			fmt.Println(infoStyle.Render(fmt.Sprintf("Overdue tasks: %d, see jtx overdue", len(overdue))))
+			return
+		}
 		fmt.Println(infoStyle.Render("Start taking notes with: jx \"your note content\""))
 		return
 	}
@@ -273,16 +420,16 @@ func (cli *CLI) listTodayNotes(cmd *cobra.Command, args []string) {
 	// Check if we're in a TTY environment
 	if cli.isTTY() {
 		// Create and run the interactive list
-		model := NewListModel(notes, "Today's Notes", cli)
+		model := NewListModel(notes, title, cli)
 		program := tea.NewProgram(model, tea.WithAltScreen())
 
 		if _, err := program.Run(); err != nil {
 			// Fall back to text mode if interactive fails
-			cli.showTextList(notes, "Today's Notes")
+			cli.showTextList(notes, title)
 		}
 	} else {
 		// Use text mode for non-TTY environments
-		cli.showTextList(notes, "Today's Notes")
+		cli.showTextList(notes, title)
 	}
 }
 
@@ -295,6 +442,8 @@ func (cli *CLI) listNotesByDate(cmd *cobra.Command, args []string) {
 		fmt.Println(errorStyle.Render(fmt.Sprintf("Error retrieving notes: %v", err)))
 		os.Exit(1)
 	}
+	notes = filterByTags(notes, tagsFlag(cmd))
+	notes = filterByProject(notes, projectFlag(cmd))
 
 	if len(notes) == 0 {
 		fmt.Println(infoStyle.Render(fmt.Sprintf("No notes found for %s.", date)))
@@ -408,6 +557,8 @@ func (cli *CLI) openInteractiveList(cmd *cobra.Command, args []string) {
 		fmt.Println(errorStyle.Render(fmt.Sprintf("Error retrieving notes: %v", err)))
 		os.Exit(1)
 	}
+	notes = filterByTags(notes, tagsFlag(cmd))
+	notes = filterByProject(notes, projectFlag(cmd))
 
 	if len(notes) == 0 {
 		fmt.Println(infoStyle.Render("No notes found for today."))
@@ -578,6 +729,8 @@ func (cli *CLI) updateNoteInteractive(note *entities.Note) {
 
 	// Update the existing note with new content
 	note.Content = updatedNote.Content
+	note.Metadata.Category = updatedNote.Metadata.Category
+	note.Metadata.Tags = updatedNote.Metadata.Tags
 	note.UpdatedAt = updatedNote.UpdatedAt
 
 	// Save the updated note
@@ -629,6 +782,8 @@ func (cli *CLI) updateContactInteractive(contact *entities.Note) {
 	contact.Content = updatedContact.Content
 	contact.Metadata.Phone = updatedContact.Metadata.Phone
 	contact.Metadata.Email = updatedContact.Metadata.Email
+	contact.Metadata.Category = updatedContact.Metadata.Category
+	contact.Metadata.Tags = updatedContact.Metadata.Tags
 	contact.UpdatedAt = updatedContact.UpdatedAt
 
 	// Save the updated contact
@@ -646,18 +801,16 @@ func (cli *CLI) updateContactInteractive(contact *entities.Note) {
 
 // completeTaskInteractive completes a task by changing its status
 func (cli *CLI) completeTaskInteractive(task *entities.Note) {
-	// Update the task status to completed
-	task.Metadata.Status = entities.StatusCompleted
-	task.UpdatedAt = time.Now()
-
-	// Save the updated task
-	if err := cli.noteService.SaveNote(task); err != nil {
+	// Mark the task as completed
+	next, err := cli.noteService.CompleteNote(task)
+	if err != nil {
 		fmt.Println(errorStyle.Render(fmt.Sprintf("Error completing task: %v", err)))
 		os.Exit(1)
 	}
 
 	// Show success message and exit
 	fmt.Println(successStyle.Render("Task completed successfully!"))
+	printNextOccurrence(next)
 
 	// Exit successfully
 	os.Exit(0)
@@ -699,6 +852,10 @@ func (cli *CLI) updateTaskInteractive(task *entities.Note) {
 	task.Content = updatedTask.Content
 	task.Metadata.Priority = updatedTask.Metadata.Priority
 	task.Metadata.Assignee = updatedTask.Metadata.Assignee
+	task.Metadata.Category = updatedTask.Metadata.Category
+	task.Metadata.DueDate = updatedTask.Metadata.DueDate
+	task.Metadata.Recurrence = updatedTask.Metadata.Recurrence
+	task.Metadata.Tags = updatedTask.Metadata.Tags
 	task.UpdatedAt = updatedTask.UpdatedAt
 
 	// Save the updated task
@@ -794,6 +951,9 @@ func (cli *CLI) updateReminderInteractive(reminder *entities.Note) {
 	// Update the existing reminder with new data
 	reminder.Content = updatedReminder.Content
 	reminder.Metadata.ReminderTime = updatedReminder.Metadata.ReminderTime
+	reminder.Metadata.Category = updatedReminder.Metadata.Category
+	reminder.Metadata.Recurrence = updatedReminder.Metadata.Recurrence
+	reminder.Metadata.Tags = updatedReminder.Metadata.Tags
 	reminder.Metadata.Status = updatedReminder.Metadata.Status
 	reminder.UpdatedAt = updatedReminder.UpdatedAt
 
@@ -812,61 +972,21 @@ func (cli *CLI) updateReminderInteractive(reminder *entities.Note) {
 
 // completeReminderInteractive completes a reminder by changing its status
 func (cli *CLI) completeReminderInteractive(reminder *entities.Note) {
-	// Update the reminder status to completed
-	reminder.Metadata.Status = entities.StatusCompleted
-	reminder.UpdatedAt = time.Now()
-
-	// Save the updated reminder
-	if err := cli.noteService.SaveNote(reminder); err != nil {
+	// Mark the reminder as completed
+	next, err := cli.noteService.CompleteNote(reminder)
+	if err != nil {
 		fmt.Println(errorStyle.Render(fmt.Sprintf("Error completing reminder: %v", err)))
 		os.Exit(1)
 	}
 
 	// Show success message and exit
 	fmt.Println(successStyle.Render("Reminder completed successfully!"))
+	printNextOccurrence(next)
 
 	// Exit successfully
 	os.Exit(0)
 }
 
-// deleteNotePermanently deletes a note from the file permanently
-func (cli *CLI) deleteNotePermanently(note *entities.Note) {
-	// Get notes directory from the repository
-	notesDir := cli.repository.(interface{ GetNotesDir() string }).GetNotesDir()
-	filepath := filepath.Join(notesDir, fmt.Sprintf("%s.json", note.Date))
-
-	file, err := os.Open(filepath)
-	if err != nil {
-		return // File doesn't exist or can't be opened
-	}
-	defer file.Close()
-
-	var notes []*entities.Note
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&notes); err != nil {
-		return // Can't decode
-	}
-
-	// Filter out the note to delete
-	var filteredNotes []*entities.Note
-	for _, n := range notes {
-		if n.ID != note.ID {
-			filteredNotes = append(filteredNotes, n)
-		}
-	}
-
-	// Write back the filtered notes
-	writeFile, err := os.Create(filepath)
-	if err != nil {
-		return
-	}
-	defer writeFile.Close()
-
-	encoder := json.NewEncoder(writeFile)
-	encoder.SetIndent("", "  ")
-	encoder.Encode(filteredNotes)
-}
-
 // listNotesByMonth lists notes for a specific month
 func (cli *CLI) listNotesByMonth(cmd *cobra.Command, monthStr string) {
 	// Validate and parse month
@@ -887,6 +1007,8 @@ func (cli *CLI) listNotesByMonth(cmd *cobra.Command, monthStr string) {
 		fmt.Println(errorStyle.Render(fmt.Sprintf("Error retrieving notes: %v", err)))
 		os.Exit(1)
 	}
+	notes = filterByTags(notes, tagsFlag(cmd))
+	notes = filterByProject(notes, projectFlag(cmd))
 
 	if len(notes) == 0 {
 		fmt.Println(infoStyle.Render(fmt.Sprintf("No notes found for %s/%d.", monthStr, currentYear)))
@@ -936,6 +1058,7 @@ func (cli *CLI) showHelp() {
 	content.WriteString("  jtx --task (-t)              Create task\n")
 	content.WriteString("  jtx --reminder (-r)         Create reminder\n")
 	content.WriteString("  jtx --contact (-c)          Create contact\n")
+	content.WriteString("  jtx --idea (-I)              Create idea (or jtx idea \"title\")\n")
 	content.WriteString("  jtx --interactive (-i)       Open interactive view\n\n")
 
 	content.WriteString(fmt.Sprintf("%s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Flags:")))
@@ -946,13 +1069,68 @@ func (cli *CLI) showHelp() {
 	content.WriteString("  -t, --task                   Create task\n")
 	content.WriteString("  -r, --reminder              Create reminder\n")
 	content.WriteString("  -c, --contact                Create contact\n")
-	content.WriteString("  -i, --interactive            Interactive list view\n\n")
+	content.WriteString("  -I, --idea                   Create idea\n")
+	content.WriteString("  -i, --interactive            Interactive list view\n")
+	content.WriteString("      --tag TAG                Tag a new note, or only list notes with a tag\n")
+	content.WriteString("      --project NAME           Put a new note in a project, or only list its notes\n\n")
+
+	content.WriteString(fmt.Sprintf("%s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Notes by ID (an alias or any unique prefix works):")))
+	content.WriteString("  jtx show <id>                Show a note\n")
+	content.WriteString("  jtx edit <id>                Edit a note\n")
+	content.WriteString("  jtx done <id>                Complete a task or reminder\n")
+	content.WriteString("  jtx promote <id>             Turn an idea into a linked task\n")
+	content.WriteString("  jtx move <id> --to DATE      Reschedule (or today/tomorrow/+3d)\n")
+	content.WriteString("  jtx rm <id>                  Move a note to trash\n")
+	content.WriteString("  jtx history <id>             List saved versions of a note\n")
+	content.WriteString("  jtx diff <id> [rev]          Show changes since a version\n")
+	content.WriteString("  jtx revert <id> <rev>        Restore an earlier version\n")
+	content.WriteString("  jtx attach <id> <file>       Attach a file to a note\n")
+	content.WriteString("  jtx attachments <id>         List the attachments of a note\n")
+	content.WriteString("  jtx open-attachment <id> <n> Open an attachment (-o to extract)\n")
+	content.WriteString("  jtx search <terms>           Search all notes (\"phrase\", prefix*)\n")
+	content.WriteString("  jtx tags                     Show all tags with their counts\n")
+	content.WriteString("  jtx tag rename <old> <new>   Rename a tag on every note\n")
+	content.WriteString("  jtx tag merge <tag>... --into <tag>  Fold tags into one\n")
+	content.WriteString("  jtx project list             List projects with task progress\n")
+	content.WriteString("  jtx project show <name>      Show open tasks and recent notes\n")
+	content.WriteString("  jtx project assign <id> <name>  Put a note in a project\n")
+	content.WriteString("  jtx due set <id> <date>      Set a due date (fri, +3d, YYYY-MM-DD)\n")
+	content.WriteString("  jtx due [--within 7d]        List open tasks due soon\n")
+	content.WriteString("  jtx overdue                  List open tasks past their due date\n")
+	content.WriteString("  jtx recurring set <id> <rule>  Repeat (daily, weekly:mon, after:3d)\n")
+	content.WriteString("  jtx recurring                List repeating tasks and reminders\n\n")
+
+	content.WriteString(fmt.Sprintf("%s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Trash:")))
+	content.WriteString("  jtx trash list               List deleted notes\n")
+	content.WriteString("  jtx trash restore <id>       Restore a deleted note\n")
+	content.WriteString("  jtx trash empty              Remove deleted notes for good\n")
+	content.WriteString("      --older-than 30d         Only notes deleted before this age\n")
+	content.WriteString("  jtx doctor [--repair]        Check notes for problems\n")
+	content.WriteString("  jtx storage migrate --to X   Copy notes to sqlite or files\n")
+	content.WriteString("  jtx storage layout nested    Keep day files in YYYY/MM folders\n")
+	content.WriteString("  jtx migrate [--dry-run]      Upgrade the storage schema\n")
+	content.WriteString("  jtx backup [--keep 10]       Write a compressed snapshot\n")
+	content.WriteString("  jtx restore <archive>        Replace notes (or --merge)\n\n")
+
+	content.WriteString(fmt.Sprintf("%s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Notebooks:")))
+	content.WriteString("  jtx notebook list|create|use|rename\n")
+	content.WriteString("      --notebook NAME          Use a notebook for one command\n")
+	content.WriteString("  jtx versioning enable        Commit every change with git\n")
+	content.WriteString("  jtx log [id] / jtx sync      Show history / pull and push\n")
+	content.WriteString("  jtx sync --with DIR          Sync with a shared folder\n")
+	content.WriteString("  jtx encrypt --init           Encrypt the notebook\n")
+	content.WriteString("  jtx decrypt --export DIR     Export a plaintext copy\n")
+	content.WriteString("  ~/.jotterxpress/hooks/       pre-create, post-complete, ... scripts\n\n")
 
 	content.WriteString(fmt.Sprintf("%s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Interactive Shortcuts:")))
 	content.WriteString("  Enter  Preview note\n")
+	content.WriteString("  H      Step through versions (in preview)\n")
 	content.WriteString("  e      Edit note\n")
 	content.WriteString("  c      Complete (tasks/reminders)\n")
-	content.WriteString("  x      Delete note\n")
+	content.WriteString("  p      Postpone to tomorrow\n")
+	content.WriteString("  m      Move to a date\n")
+	content.WriteString("  t      Promote idea to task\n")
+	content.WriteString("  x      Move note to trash\n")
 	content.WriteString("  q      Quit\n")
 
 	// Modal content
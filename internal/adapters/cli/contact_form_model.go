@@ -15,6 +15,8 @@ const (
 	contactName = iota
 	contactPhone
 	contactEmail
+	contactProject
+	contactTags
 )
 
 const (
@@ -39,7 +41,7 @@ type ContactFormModel struct {
 
 // NewContactFormModel creates a new contact form model
 func NewContactFormModel() *ContactFormModel {
-	var inputs []textinput.Model = make([]textinput.Model, 3)
+	var inputs []textinput.Model = make([]textinput.Model, 5)
 
 	// Contact name input
 	inputs[contactName] = textinput.New()
@@ -65,6 +67,12 @@ func NewContactFormModel() *ContactFormModel {
 	inputs[contactEmail].Prompt = ""
 	inputs[contactEmail].Validate = emailValidator
 
+	// Project and tags inputs
+	inputs[contactProject] = newProjectInput("")
+	inputs[contactProject].Prompt = ""
+	inputs[contactTags] = newTagsInput(nil)
+	inputs[contactTags].Prompt = ""
+
 	return &ContactFormModel{
 		inputs:  inputs,
 		focused: 0,
@@ -76,7 +84,7 @@ func NewContactFormModel() *ContactFormModel {
 
 // NewContactFormModelWithData creates a new contact form model with existing data
 func NewContactFormModelWithData(contact *entities.Note) *ContactFormModel {
-	var inputs []textinput.Model = make([]textinput.Model, 3)
+	var inputs []textinput.Model = make([]textinput.Model, 5)
 
 	// Contact name input
 	inputs[contactName] = textinput.New()
@@ -105,6 +113,12 @@ func NewContactFormModelWithData(contact *entities.Note) *ContactFormModel {
 	inputs[contactEmail].Validate = emailValidator
 	inputs[contactEmail].SetValue(contact.Metadata.Email) // Set existing email
 
+	// Project and tags inputs
+	inputs[contactProject] = newProjectInput(contact.Metadata.Category)
+	inputs[contactProject].Prompt = ""
+	inputs[contactTags] = newTagsInput(contact.Metadata.Tags)
+	inputs[contactTags].Prompt = ""
+
 	return &ContactFormModel{
 		inputs:          inputs,
 		focused:         0,
@@ -173,6 +187,9 @@ func (m ContactFormModel) View() string {
  %s  %s
  %s  %s
 
+ %s  %s
+ %s  %s
+
  %s
 `,
 		title,
@@ -182,6 +199,10 @@ func (m ContactFormModel) View() string {
 		contactLabelStyle.Width(40).Render("Email"),
 		m.inputs[contactPhone].View(),
 		m.inputs[contactEmail].View(),
+		contactLabelStyle.Width(30).Render("Project"),
+		contactLabelStyle.Width(50).Render("Tags"),
+		m.inputs[contactProject].View(),
+		m.inputs[contactTags].View(),
 		contactContinueStyle.Render("Press Ctrl+S to create contact, Tab to navigate, Ctrl+C to cancel"),
 	)
 
@@ -202,6 +223,8 @@ func (m *ContactFormModel) createContact() {
 
 	phone := strings.TrimSpace(m.inputs[contactPhone].Value())
 	email := strings.TrimSpace(m.inputs[contactEmail].Value())
+	project := entities.NormalizeProject(m.inputs[contactProject].Value())
+	tags := entities.ParseTags(m.inputs[contactTags].Value())
 
 	if phone == "" && email == "" {
 		m.err = fmt.Errorf("at least phone or email is required")
@@ -213,11 +236,15 @@ func (m *ContactFormModel) createContact() {
 		m.existingContact.Content = name
 		m.existingContact.Metadata.Phone = phone
 		m.existingContact.Metadata.Email = email
+		m.existingContact.Metadata.Category = project
+		m.existingContact.Metadata.Tags = tags
 		m.existingContact.UpdatedAt = time.Now()
 		m.contact = m.existingContact
 	} else {
 		// Create new contact
 		contact := entities.NewContact(name, phone, email)
+		contact.Metadata.Category = project
+		contact.Metadata.Tags = tags
 		m.contact = contact
 	}
 
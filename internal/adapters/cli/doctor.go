@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"jotterxpress/internal/adapters/repository"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// storageDoctor is implemented by repositories that can check their own storage
+type storageDoctor interface {
+	Doctor(repair bool) (*repository.DoctorReport, error)
+}
+
+// newDoctorCommand creates the doctor command
+func (cli *CLI) newDoctorCommand() *cobra.Command {
+	doctorCmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the notes directory for problems",
+		Long: "Scan the notes directory for unreadable files, duplicate note IDs, " +
+			"notes stored under the wrong date and orphaned .txt files.",
+		Args: cobra.NoArgs,
+		Run:  cli.runDoctor,
+	}
+	doctorCmd.Flags().Bool("repair", false, "Attempt a best-effort repair of every problem found")
+
+	return doctorCmd
+}
+
+// runDoctor scans storage and optionally repairs it
+func (cli *CLI) runDoctor(cmd *cobra.Command, args []string) {
+	repair, _ := cmd.Flags().GetBool("repair")
+
+	doctor, ok := cli.repository.(storageDoctor)
+	if !ok {
+		fmt.Println(errorStyle.Render("The current storage backend does not support doctor"))
+		os.Exit(1)
+	}
+
+	report, err := doctor.Doctor(repair)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error checking notes: %v", err)))
+		os.Exit(1)
+	}
+
+	cli.showDoctorReport(report)
+
+	if len(report.Issues) == 0 || repair {
+		return
+	}
+
+	// Offer a repair when running interactively
+	if !cli.isTTY() {
+		fmt.Println(infoStyle.Render("Run 'jtx doctor --repair' to attempt a best-effort repair."))
+		return
+	}
+
+	fmt.Print("Attempt a best-effort repair now? [y/N] ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+		return
+	}
+
+	report, err = doctor.Doctor(true)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error repairing notes: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println("")
+	cli.showDoctorReport(report)
+}
+
+// showDoctorReport prints the result of a doctor run
+func (cli *CLI) showDoctorReport(report *repository.DoctorReport) {
+	fmt.Println(titleStyle.Render("JotterXpress Doctor"))
+	fmt.Println("")
+	fmt.Printf("Scanned %d files, %d notes.\n\n", report.FilesScanned, report.NotesScanned)
+
+	if len(report.Issues) == 0 {
+		fmt.Println(successStyle.Render("No problems found."))
+		return
+	}
+
+	repaired := 0
+	for i, issue := range report.Issues {
+		location := issue.Path
+		if issue.NoteID != "" {
+			if location != "" {
+				location += " "
+			}
+			location += "note " + issue.NoteID
+		}
+
+		status := ""
+		if issue.Repaired {
+			status = successStyle.Render(" [repaired]")
+			repaired++
+		}
+
+		fmt.Printf("%d. %s %s: %s%s\n", i+1, errorStyle.Render(string(issue.Kind)), location, issue.Detail, status)
+	}
+
+	fmt.Println("")
+	if repaired > 0 {
+		fmt.Println(successStyle.Render(fmt.Sprintf("Repaired %d of %d problems.", repaired, len(report.Issues))))
+	} else {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Found %d problems.", len(report.Issues))))
+	}
+}
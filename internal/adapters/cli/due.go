@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// overdueStyle highlights tasks whose due date has passed
+var overdueStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87")).Bold(true)
+
+// newOverdueCommand creates the overdue command
+func (cli *CLI) newOverdueCommand() *cobra.Command {
+	overdueCmd := &cobra.Command{
+		Use:   "overdue",
+		Short: "List open tasks of every date whose due date has passed",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.listDueTasks(cmd, -1, "Overdue Tasks", "No overdue tasks.")
+		},
+	}
+	overdueCmd.Flags().String("project", "", "Only list tasks of this project and its subprojects")
+
+	return overdueCmd
+}
+
+// newDueCommand creates the due command and its subcommands
+func (cli *CLI) newDueCommand() *cobra.Command {
+	dueCmd := &cobra.Command{
+		Use:   "due",
+		Short: "List open tasks due soon, and set or clear due dates",
+		Long: "List the open tasks of every date that are due soon, overdue ones included. " +
+			"Due dates are YYYY-MM-DD, today, tomorrow, a weekday such as fri, or +3d and +2w from today.",
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			withinStr, _ := cmd.Flags().GetString("within")
+			within, err := parseAge(withinStr)
+			if err != nil {
+				fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+				os.Exit(1)
+			}
+
+			days := int(within / (24 * time.Hour))
+			cli.listDueTasks(cmd, days, fmt.Sprintf("Tasks Due Within %s", withinStr), fmt.Sprintf("No tasks due within %s.", withinStr))
+		},
+	}
+	dueCmd.Flags().String("within", "7d", "How far ahead to look (e.g. 3d, 2w)")
+	dueCmd.Flags().String("project", "", "Only list tasks of this project and its subprojects")
+
+	setCmd := &cobra.Command{
+		Use:   "set <id> <date>",
+		Short: "Set the due date of a task",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			due, err := parseDueDate(args[1])
+			if err != nil {
+				fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+				os.Exit(1)
+			}
+			cli.setDueDate(cli.resolveNote(args[0]), due)
+		},
+	}
+
+	clearCmd := &cobra.Command{
+		Use:   "clear <id>",
+		Short: "Remove the due date of a task",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.setDueDate(cli.resolveNote(args[0]), nil)
+		},
+	}
+
+	dueCmd.AddCommand(setCmd, clearCmd)
+	return dueCmd
+}
+
+// listDueTasks lists the open tasks due at most days from today
+func (cli *CLI) listDueTasks(cmd *cobra.Command, days int, title, empty string) {
+	notes, err := cli.noteService.GetDueTasks(days)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error retrieving tasks: %v", err)))
+		os.Exit(1)
+	}
+	notes = filterByProject(notes, projectFlag(cmd))
+
+	if len(notes) == 0 {
+		fmt.Println(infoStyle.Render(empty))
+		return
+	}
+
+	// Check if we're in a TTY environment
+	if cli.isTTY() {
+		model := NewListModel(notes, title, cli)
+		program := tea.NewProgram(model, tea.WithAltScreen())
+
+		if _, err := program.Run(); err != nil {
+			// Fall back to text mode if interactive fails
+			cli.showTextList(notes, title)
+		}
+	} else {
+		cli.showTextList(notes, title)
+	}
+}
+
+// setDueDate sets or clears the due date of a task
+func (cli *CLI) setDueDate(note *entities.Note, due *time.Time) {
+	if note.Type != entities.NoteTypeTask {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: note %s is not a task", note.Alias())))
+		os.Exit(1)
+	}
+
+	note.Metadata.DueDate = due
+	if err := cli.noteService.SaveNote(note); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error updating task: %v", err)))
+		os.Exit(1)
+	}
+
+	if due == nil {
+		fmt.Println(successStyle.Render(fmt.Sprintf("Task %s no longer has a due date.", note.Alias())))
+		return
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("Task %s is due %s.", note.Alias(), due.Format("Mon 2006-01-02"))))
+}
+
+// parseDueDate parses a due date, see parseDate, into midnight local time.
+// An empty string means no due date.
+func parseDueDate(s string) (*time.Time, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	date, err := parseDate(s)
+	if err != nil {
+		return nil, err
+	}
+
+	due, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	return &due, nil
+}
+
+// dueLabel describes when a note is due relative to now, such as "due
+// tomorrow" or "overdue 3d", or returns "" when it has no due date
+func dueLabel(note *entities.Note, now time.Time) string {
+	days, ok := note.DaysUntilDue(now)
+	if !ok {
+		return ""
+	}
+
+	switch {
+	case note.IsOverdue(now):
+		return fmt.Sprintf("overdue %dd", -days)
+	case days == 0:
+		return "due today"
+	case days == 1:
+		return "due tomorrow"
+	case days > 1 && days < 7:
+		return "due " + note.Metadata.DueDate.Format("Mon")
+	}
+	return "due " + note.Metadata.DueDate.Format("Jan 2")
+}
+
+// newDueDateInput creates the due date field of the task form
+func newDueDateInput(due *time.Time) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "fri, +3d (optional)"
+	input.CharLimit = 20
+	input.Width = 20
+	input.Validate = dueDateValidator
+	if due != nil {
+		input.SetValue(due.Format(entities.DueDateLayout))
+	}
+	return input
+}
+
+// dueDateValidator validates due date input
+func dueDateValidator(s string) error {
+	_, err := parseDueDate(s)
+	return err
+}
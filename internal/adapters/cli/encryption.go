@@ -0,0 +1,310 @@
+package cli
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"jotterxpress/internal/adapters/attachments"
+	"jotterxpress/internal/adapters/fsutil"
+	"jotterxpress/internal/adapters/repository"
+	"jotterxpress/internal/adapters/search"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// passphraseEnv supplies the passphrase of an encrypted notebook to scripts
+const passphraseEnv = "JOTTERXPRESS_PASSPHRASE"
+
+// cachedKey is the key cache file of an encrypted notebook
+type cachedKey struct {
+	Key     []byte    `json:"key"`
+	Expires time.Time `json:"expires"`
+}
+
+// newEncryptCommand creates the encrypt command
+func (cli *CLI) newEncryptCommand() *cobra.Command {
+	encryptCmd := &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt the notes of the current notebook",
+		Long: "Encrypt the content and metadata of every note and trashed note with AES-256-GCM, " +
+			"using a key derived from a passphrase. Run it again to finish an interrupted encryption.",
+		Args: cobra.NoArgs,
+		Run:  cli.encryptNotebook,
+	}
+	encryptCmd.Flags().Bool("init", false, "Set a passphrase and encrypt all existing notes")
+	encryptCmd.MarkFlagRequired("init")
+
+	return encryptCmd
+}
+
+// newDecryptCommand creates the decrypt command
+func (cli *CLI) newDecryptCommand() *cobra.Command {
+	decryptCmd := &cobra.Command{
+		Use:   "decrypt",
+		Short: "Export the notes of an encrypted notebook as plaintext",
+		Long:  "Write a decrypted copy of every note and trashed note to a directory, in the files storage format.",
+		Args:  cobra.NoArgs,
+		Run:   cli.decryptNotebook,
+	}
+	decryptCmd.Flags().String("export", "", "Directory to write the decrypted notes to")
+	decryptCmd.MarkFlagRequired("export")
+
+	return decryptCmd
+}
+
+// newLockCommand creates the lock command
+func (cli *CLI) newLockCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lock",
+		Short: "Forget the cached key of an encrypted notebook",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := os.Remove(keyCachePath(cli.notesDir)); err != nil && !os.IsNotExist(err) {
+				fmt.Println(errorStyle.Render(fmt.Sprintf("Error removing cached key: %v", err)))
+				os.Exit(1)
+			}
+			fmt.Println(successStyle.Render("Notebook locked."))
+		},
+	}
+}
+
+// encryptNotebook encrypts every note of the current notebook in place
+func (cli *CLI) encryptNotebook(cmd *cobra.Command, args []string) {
+	var key []byte
+	var err error
+
+	if repository.EncryptionEnabled(cli.notesDir) {
+		// Resume: notes that are still plaintext are sealed below
+		passphrase := readPassphrase("Passphrase: ")
+		key, err = repository.DeriveKey(cli.notesDir, passphrase)
+	} else {
+		passphrase := readPassphrase("New passphrase: ")
+		if os.Getenv(passphraseEnv) == "" && readPassphrase("Repeat passphrase: ") != passphrase {
+			fmt.Println(errorStyle.Render("Error: passphrases do not match"))
+			os.Exit(1)
+		}
+		key, err = repository.InitEncryption(cli.notesDir, passphrase)
+	}
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+
+	encrypted := repository.NewEncryptedRepository(cli.repository, func() (*repository.Cipher, error) {
+		return repository.NewCipher(key)
+	})
+
+	// Reading through the encrypted repository accepts both sealed and
+	// plaintext notes, and saving seals them all
+	stats, err := repository.Copy(encrypted, encrypted)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error encrypting notes: %v", err)))
+		fmt.Println(infoStyle.Render("Run 'jtx encrypt --init' again to finish."))
+		os.Exit(1)
+	}
+
+	// Attachments stored before encryption are sealed with the same key
+	sealed, err := attachments.NewEncryptedStore(filepath.Join(cli.notesDir, attachments.DirName), func() (*repository.Cipher, error) {
+		return repository.NewCipher(key)
+	}).SealAll()
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error encrypting attachments: %v", err)))
+		fmt.Println(infoStyle.Render("Run 'jtx encrypt --init' again to finish."))
+		os.Exit(1)
+	}
+
+	// The persistent search index holds note text in plaintext
+	if err := search.RemoveIndex(filepath.Join(cli.notesDir, ".search-index.json")); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error removing search index: %v", err)))
+		os.Exit(1)
+	}
+
+	cli.cacheKey(key)
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Encrypted %d notes, %d trashed notes and %d attachments.", stats.Notes, stats.Trashed, sealed)))
+	if cli.gitRepo.Enabled() {
+		if err := cli.gitRepo.Commit("encrypt notes"); err != nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Warning: failed to commit change: %v", err)))
+		}
+		fmt.Println(errorStyle.Render("Warning: earlier plaintext versions remain in the git history of this notebook."))
+	}
+	fmt.Println(infoStyle.Render("Keep your passphrase safe: notes cannot be recovered without it."))
+}
+
+// decryptNotebook exports a plaintext copy of the current notebook
+func (cli *CLI) decryptNotebook(cmd *cobra.Command, args []string) {
+	dir, _ := cmd.Flags().GetString("export")
+
+	if !repository.EncryptionEnabled(cli.notesDir) {
+		fmt.Println(errorStyle.Render("Error: this notebook is not encrypted"))
+		os.Exit(1)
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error resolving path: %v", err)))
+		os.Exit(1)
+	}
+	if notesDir, _ := filepath.Abs(cli.notesDir); absDir == notesDir {
+		fmt.Println(errorStyle.Render("Error: export to a directory other than the notebook itself"))
+		os.Exit(1)
+	}
+
+	if entries, err := os.ReadDir(absDir); err == nil && len(entries) > 0 {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %s is not empty", absDir)))
+		os.Exit(1)
+	}
+
+	stats, err := repository.Copy(repository.NewFileRepository(absDir), cli.store)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error exporting notes: %v", err)))
+		os.Exit(1)
+	}
+
+	exported, err := exportAttachments(cli.attachmentStore, attachments.NewStore(filepath.Join(absDir, attachments.DirName)))
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error exporting attachments: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Exported %d notes, %d trashed notes and %d attachments to %s", stats.Notes, stats.Trashed, exported, absDir)))
+	fmt.Println(infoStyle.Render("The export is plaintext. Open it as a notebook with: jtx notebook create NAME --path " + absDir))
+}
+
+// exportAttachments copies every attachment from one store to another and
+// returns how many it copied
+func exportAttachments(from, to *attachments.Store) (int, error) {
+	hashes, err := from.List()
+	if err != nil {
+		return 0, err
+	}
+
+	for i, hash := range hashes {
+		content, err := from.Open(hash)
+		if err != nil {
+			return i, err
+		}
+		_, _, err = to.Put(content)
+		content.Close()
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return len(hashes), nil
+}
+
+// unlockNotebook returns the cipher of the current notebook, using the cached
+// key while it is valid and asking for the passphrase otherwise
+func (cli *CLI) unlockNotebook() (*repository.Cipher, error) {
+	key := cli.loadCachedKey()
+	if key == nil || repository.VerifyKey(cli.notesDir, key) != nil {
+		var err error
+		key, err = repository.DeriveKey(cli.notesDir, readPassphrase("Passphrase: "))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Each use extends the cache, like an agent
+	cli.cacheKey(key)
+
+	return repository.NewCipher(key)
+}
+
+// readPassphrase reads a passphrase from the environment or, without echo,
+// from the terminal
+func readPassphrase(prompt string) string {
+	if passphrase := os.Getenv(passphraseEnv); passphrase != "" {
+		return passphrase
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: this notebook is encrypted, set %s or run jtx in a terminal", passphraseEnv)))
+		os.Exit(1)
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		// Fall back to a plain read when the terminal cannot hide input
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		return strings.TrimRight(line, "\r\n")
+	}
+
+	return string(passphrase)
+}
+
+// keyCachePath returns the key cache file of a notes directory. It lives in
+// the per-user runtime directory when there is one, which is usually memory-backed.
+func keyCachePath(notesDir string) string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	absDir, _ := filepath.Abs(notesDir)
+	sum := sha256.Sum256([]byte(absDir))
+	name := fmt.Sprintf("jotterxpress-%d", os.Getuid())
+
+	return filepath.Join(dir, name, hex.EncodeToString(sum[:8])+".key")
+}
+
+// loadCachedKey returns the cached key of the current notebook, or nil when
+// there is none or it expired
+func (cli *CLI) loadCachedKey() []byte {
+	if cli.config.KeyCacheTimeout() <= 0 {
+		return nil
+	}
+
+	path := keyCachePath(cli.notesDir)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var cached cachedKey
+	if err := json.Unmarshal(data, &cached); err != nil || time.Now().After(cached.Expires) {
+		os.Remove(path)
+		return nil
+	}
+
+	return cached.Key
+}
+
+// cacheKey remembers the key of the current notebook for the configured time
+func (cli *CLI) cacheKey(key []byte) {
+	timeout := cli.config.KeyCacheTimeout()
+	if timeout <= 0 {
+		return
+	}
+
+	path := keyCachePath(cli.notesDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+
+	// Never write a key into a directory other users can reach
+	if info, err := os.Lstat(filepath.Dir(path)); err != nil || !info.IsDir() || info.Mode().Perm() != 0700 {
+		return
+	}
+
+	data, err := json.Marshal(cachedKey{Key: key, Expires: time.Now().Add(timeout)})
+	if err != nil {
+		return
+	}
+
+	// A failed cache write only means asking for the passphrase again
+	if fsutil.WriteFileAtomic(path, data) == nil {
+		os.Chmod(path, 0600)
+	}
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"jotterxpress/internal/adapters/attachments"
+	"jotterxpress/internal/adapters/fsutil"
+	"jotterxpress/internal/adapters/repository"
+	"jotterxpress/internal/adapters/search"
+	"jotterxpress/internal/adapters/syncdir"
+	"jotterxpress/internal/application/services"
+	"jotterxpress/internal/domain/ports"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// syncStateDirName holds, per shared folder, the state of the last sync
+const syncStateDirName = ".sync"
+
+// syncWithFolder syncs the notes two ways with a shared folder
+func (cli *CLI) syncWithFolder(dir string, interactive bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+	if interactive && !cli.isTTY() {
+		fmt.Println(errorStyle.Render("Error: --interactive requires a terminal"))
+		os.Exit(1)
+	}
+
+	store, err := cli.openSyncFolder(dir)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+
+	statePath := cli.syncStatePath(dir)
+	base, err := loadSyncState(statePath)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error reading sync state: %v", err)))
+		os.Exit(1)
+	}
+
+	var resolve ports.ConflictResolver
+	if interactive {
+		resolve = askConflictResolution
+	}
+
+	// Notes are written as they come, so the search index is updated here too
+	syncService := services.NewSyncService(search.NewIndexedRepository(cli.store, cli.searchIndex))
+	report, err := syncService.Sync(store, base, resolve)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error syncing notes: %v", err)))
+		os.Exit(1)
+	}
+
+	// Synced notes may refer to attachments only one side has
+	shared := attachments.NewStore(filepath.Join(dir, syncdir.AttachmentsDirName))
+	if _, err := cli.attachmentStore.Import(shared); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error copying attachments: %v", err)))
+		os.Exit(1)
+	}
+	if _, err := shared.Import(cli.attachmentStore); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error copying attachments: %v", err)))
+		os.Exit(1)
+	}
+
+	if err := saveSyncState(statePath, report.Synced); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Warning: failed to save sync state: %v", err)))
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Notes synced with %s: %d pulled, %d pushed, %d deleted here, %d deleted there, %d conflicts.",
+		dir, report.Pulled, report.Pushed, report.DeletedLocal, report.DeletedRemote, report.Conflicts)))
+	for _, note := range report.ConflictCopies {
+		fmt.Printf("Conflict copy %s: %s\n", note.Alias(), note.String())
+	}
+
+	if cli.gitRepo.Enabled() {
+		if err := cli.gitRepo.Commit("sync with " + dir); err != nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Warning: failed to commit change: %v", err)))
+		}
+	}
+}
+
+// openSyncFolder opens a shared folder as a sync store. An encrypted
+// notebook shares its key parameters with the folder, so notes written
+// there stay sealed and open on every machine with the same passphrase.
+func (cli *CLI) openSyncFolder(dir string) (*syncdir.Store, error) {
+	if cli.unlock == nil {
+		if repository.EncryptionEnabled(dir) {
+			return nil, fmt.Errorf("%s holds encrypted notes, but this notebook is not encrypted", dir)
+		}
+		return syncdir.NewStore(dir)
+	}
+
+	store, err := syncdir.NewEncryptedStore(dir, cli.unlock)
+	if err != nil {
+		return nil, err
+	}
+	if err := repository.ShareEncryption(cli.notesDir, dir); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// syncStatePath returns the file holding the state of the last sync with a
+// shared folder, named after a hash of its path
+func (cli *CLI) syncStatePath(dir string) string {
+	sum := sha256.Sum256([]byte(dir))
+	return filepath.Join(cli.notesDir, syncStateDirName, hex.EncodeToString(sum[:8])+".json")
+}
+
+// loadSyncState reads the UpdatedAt of every note at the last sync
+func loadSyncState(path string) (map[string]time.Time, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]time.Time{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state map[string]time.Time
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid sync state %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// saveSyncState writes the UpdatedAt of every note after a sync
+func saveSyncState(path string, state map[string]time.Time) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(path, append(data, '\n'))
+}
+
+// askConflictResolution shows both versions of a conflicting note and asks
+// which to keep
+func askConflictResolution(conflict ports.SyncConflict) ports.SyncResolution {
+	fmt.Println("")
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Note %s changed on both sides", conflict.Local.Alias())))
+	fmt.Printf("Local  (%s): %s\n", conflict.Local.UpdatedAt.Format("2006-01-02 15:04"), conflict.Local.String())
+	fmt.Printf("Remote (%s): %s\n", conflict.Remote.UpdatedAt.Format("2006-01-02 15:04"), conflict.Remote.String())
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("Keep [l]ocal, [r]emote or [b]oth? [b] ")
+		answer, err := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "l", "local":
+			return ports.KeepLocal
+		case "r", "remote":
+			return ports.KeepRemote
+		case "", "b", "both":
+			return ports.KeepBoth
+		}
+		if err != nil {
+			return ports.KeepBoth
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Styles for diff lines
+	diffAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575"))
+	diffRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
+)
+
+// diffLine is one line of a line diff; Op is '+', '-' or ' '
+type diffLine struct {
+	Op   byte
+	Text string
+}
+
+// newHistoryCommand creates the history command
+func (cli *CLI) newHistoryCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "history <id>",
+		Short: "List the saved versions of a note",
+		Args:  cobra.ExactArgs(1),
+		Run:   cli.showHistory,
+	}
+}
+
+// newDiffCommand creates the diff command
+func (cli *CLI) newDiffCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <id> [rev]",
+		Short: "Show what changed in a note",
+		Long: "Compare a revision of a note with its current version, line by line.\n" +
+			"Without a revision, show the changes made by the last save.",
+		Args: cobra.RangeArgs(1, 2),
+		Run:  cli.diffNote,
+	}
+}
+
+// newRevertCommand creates the revert command
+func (cli *CLI) newRevertCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revert <id> <rev>",
+		Short: "Restore a note to an earlier revision",
+		Long:  "Restore the content and metadata of a note from a revision. The result is saved as a new revision, so a revert can itself be reverted.",
+		Args:  cobra.ExactArgs(2),
+		Run:   cli.revertNote,
+	}
+}
+
+// showHistory lists the revisions of a note
+func (cli *CLI) showHistory(cmd *cobra.Command, args []string) {
+	history := cli.resolveHistory(args[0])
+	current := history[len(history)-1]
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("History of %s (%d revisions)", current.Note.ID, len(history))))
+	fmt.Println("")
+
+	for i := len(history) - 1; i >= 0; i-- {
+		revision := history[i]
+		marker := ""
+		if revision == current {
+			marker = successStyle.Render(" (current)")
+		}
+
+		fmt.Printf("%s %s %s%s\n",
+			infoStyle.Render(fmt.Sprintf("r%d", revision.Number)),
+			revision.SavedAt.Format("2006-01-02 15:04:05"),
+			revision.Note.String(),
+			marker,
+		)
+	}
+}
+
+// diffNote prints the differences between a revision and the current version
+func (cli *CLI) diffNote(cmd *cobra.Command, args []string) {
+	history := cli.resolveHistory(args[0])
+	current := history[len(history)-1]
+
+	var from *entities.Revision
+	if len(args) > 1 {
+		from = findRevision(history, parseRevision(args[1]))
+	} else if len(history) > 1 {
+		from = history[len(history)-2]
+	} else {
+		fmt.Println(infoStyle.Render("This note has no earlier revisions."))
+		return
+	}
+
+	fmt.Println(infoStyle.Render(fmt.Sprintf("--- r%d %s", from.Number, from.SavedAt.Format("2006-01-02 15:04:05"))))
+	fmt.Println(infoStyle.Render(fmt.Sprintf("+++ r%d %s", current.Number, current.SavedAt.Format("2006-01-02 15:04:05"))))
+
+	fmt.Println(renderDiff(diffLines(revisionLines(from.Note), revisionLines(current.Note))))
+}
+
+// revertNote restores a note to one of its revisions
+func (cli *CLI) revertNote(cmd *cobra.Command, args []string) {
+	note := cli.resolveNote(args[0])
+	number := parseRevision(args[1])
+
+	if err := cli.noteService.RevertNote(note, number); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error reverting note: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Note %s reverted to revision %d.", note.ID, number)))
+}
+
+// resolveHistory retrieves the revisions of a note by ID or unique ID prefix
+func (cli *CLI) resolveHistory(ref string) []*entities.Revision {
+	note := cli.resolveNote(ref)
+
+	history, err := cli.noteService.GetHistory(note.ID)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error retrieving history: %v", err)))
+		os.Exit(1)
+	}
+
+	return history
+}
+
+// parseRevision parses a revision number, with or without its "r" prefix
+func parseRevision(arg string) int {
+	number, err := strconv.Atoi(strings.TrimPrefix(arg, "r"))
+	if err != nil || number < 1 {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: invalid revision %q", arg)))
+		os.Exit(1)
+	}
+
+	return number
+}
+
+// findRevision returns the revision with the given number, exiting when there is none
+func findRevision(history []*entities.Revision, number int) *entities.Revision {
+	for _, revision := range history {
+		if revision.Number == number {
+			return revision
+		}
+	}
+
+	fmt.Println(errorStyle.Render(fmt.Sprintf("Error: revision %d not found, see jtx history", number)))
+	os.Exit(1)
+	return nil
+}
+
+// revisionLines renders a note as lines for diffing: its type, its date, its
+// content and one "key: value" line per metadata field
+func revisionLines(note *entities.Note) []string {
+	lines := []string{"type: " + string(note.Type), "date: " + note.Date}
+	lines = append(lines, strings.Split(note.Content, "\n")...)
+
+	data, err := json.Marshal(note.Metadata)
+	if err != nil {
+		return lines
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return lines
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		// One line per attachment, so diffs show which one was added
+		if key == "attachments" {
+			for _, attachment := range note.Metadata.Attachments {
+				lines = append(lines, fmt.Sprintf("attachment: %s (%s)", attachment.Name, shortHash(attachment.SHA256)))
+			}
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %v", key, fields[key]))
+	}
+
+	return lines
+}
+
+// diffLines computes a line diff from a to b using their longest common
+// subsequence
+func diffLines(a, b []string) []diffLine {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, diffLine{'-', a[i]})
+			i++
+		default:
+			diff = append(diff, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, diffLine{'+', b[j]})
+	}
+
+	return diff
+}
+
+// renderDiff colors a line diff for the terminal
+func renderDiff(diff []diffLine) string {
+	changed := false
+	lines := make([]string, 0, len(diff))
+	for _, line := range diff {
+		text := fmt.Sprintf("%c %s", line.Op, line.Text)
+		switch line.Op {
+		case '+':
+			text = diffAddedStyle.Render(text)
+			changed = true
+		case '-':
+			text = diffRemovedStyle.Render(text)
+			changed = true
+		}
+		lines = append(lines, text)
+	}
+
+	if !changed {
+		return "(no changes)"
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	ideaTitle = iota
+	ideaBody
+	ideaMaturity
+	ideaProject
+	ideaTags
+	ideaFieldCount
+)
+
+var ideaTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#F59E0B")).Padding(0, 1)
+
+type IdeaFormModel struct {
+	title        textinput.Model
+	body         textarea.Model
+	maturity     textinput.Model
+	project      textinput.Model
+	tags         textinput.Model
+	focused      int
+	err          error
+	idea         *entities.Note
+	existingIdea *entities.Note // For updating existing ideas
+}
+
+// NewIdeaFormModel creates a new idea form model
+func NewIdeaFormModel() *IdeaFormModel {
+	title := textinput.New()
+	title.Placeholder = "Enter idea title..."
+	title.Focus()
+	title.CharLimit = 200
+	title.Width = 60
+
+	body := textarea.New()
+	body.Placeholder = "Describe the idea (optional)"
+	body.CharLimit = 4000
+	body.SetWidth(70)
+	body.SetHeight(8)
+	body.ShowLineNumbers = false
+
+	maturity := textinput.New()
+	maturity.Placeholder = "raw, explored"
+	maturity.CharLimit = 10
+	maturity.Width = 15
+	maturity.Validate = maturityValidator
+
+	return &IdeaFormModel{
+		title:    title,
+		body:     body,
+		maturity: maturity,
+		project:  newProjectInput(""),
+		tags:     newTagsInput(nil),
+	}
+}
+
+// NewIdeaFormModelWithData creates a new idea form model with existing data
+func NewIdeaFormModelWithData(idea *entities.Note) *IdeaFormModel {
+	m := NewIdeaFormModel()
+	m.title.SetValue(idea.Content)
+	m.body.SetValue(idea.Metadata.Body)
+	m.maturity.SetValue(string(idea.Metadata.Maturity))
+	m.project.SetValue(idea.Metadata.Category)
+	m.tags.SetValue(strings.Join(idea.Metadata.Tags, ", "))
+	m.existingIdea = idea
+	return m
+}
+
+// Init initializes the model
+func (m IdeaFormModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles messages
+func (m IdeaFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlS:
+			// Create the idea and finish
+			m.createIdea()
+			if m.err != nil {
+				return &m, nil
+			}
+			return &m, tea.Quit
+		case tea.KeyCtrlC, tea.KeyEsc:
+			return &m, tea.Quit
+		case tea.KeyShiftTab, tea.KeyCtrlP:
+			m.focus((m.focused + ideaFieldCount - 1) % ideaFieldCount)
+			return &m, nil
+		case tea.KeyTab, tea.KeyCtrlN:
+			m.focus((m.focused + 1) % ideaFieldCount)
+			return &m, nil
+		case tea.KeyEnter:
+			// Enter starts a new line in the body and moves on elsewhere
+			if m.focused != ideaBody {
+				m.focus((m.focused + 1) % ideaFieldCount)
+				return &m, nil
+			}
+		}
+
+	case errMsg:
+		m.err = msg
+		return &m, nil
+	}
+
+	var cmd tea.Cmd
+	switch m.focused {
+	case ideaTitle:
+		m.title, cmd = m.title.Update(msg)
+	case ideaBody:
+		m.body, cmd = m.body.Update(msg)
+	case ideaMaturity:
+		m.maturity, cmd = m.maturity.Update(msg)
+	case ideaProject:
+		m.project, cmd = m.project.Update(msg)
+	case ideaTags:
+		m.tags, cmd = m.tags.Update(msg)
+	}
+	return &m, cmd
+}
+
+// View renders the form
+func (m IdeaFormModel) View() string {
+	heading := "Create New Idea"
+	if m.existingIdea != nil {
+		heading = "Edit Idea"
+	}
+
+	content := fmt.Sprintf(`
+%s
+
+ %s
+ %s
+
+ %s
+%s
+
+ %s
+ %s
+
+ %s  %s
+ %s  %s
+
+ %s
+`,
+		ideaTitleStyle.Render(heading),
+		labelStyle.Width(60).Render("Title"),
+		m.title.View(),
+		labelStyle.Width(60).Render("Body"),
+		m.body.View(),
+		labelStyle.Width(40).Render("Stage (raw → explored → promoted)"),
+		m.maturity.View(),
+		labelStyle.Width(30).Render("Project"),
+		labelStyle.Width(50).Render("Tags"),
+		m.project.View(),
+		m.tags.View(),
+		continueStyle.Render("Press Ctrl+S to save idea, Tab to navigate, Ctrl+C to cancel"),
+	)
+
+	if m.err != nil {
+		content += fmt.Sprintf("\n %s", lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87")).Render(fmt.Sprintf("Error: %v", m.err)))
+	}
+
+	return content + "\n"
+}
+
+// focus moves the focus to the given field
+func (m *IdeaFormModel) focus(field int) {
+	m.focused = field
+	m.title.Blur()
+	m.body.Blur()
+	m.maturity.Blur()
+	m.project.Blur()
+	m.tags.Blur()
+
+	switch field {
+	case ideaTitle:
+		m.title.Focus()
+	case ideaBody:
+		m.body.Focus()
+	case ideaMaturity:
+		m.maturity.Focus()
+	case ideaProject:
+		m.project.Focus()
+	case ideaTags:
+		m.tags.Focus()
+	}
+}
+
+// createIdea creates the idea from form data
+func (m *IdeaFormModel) createIdea() {
+	title := strings.TrimSpace(m.title.Value())
+	if title == "" {
+		m.err = fmt.Errorf("idea title is required")
+		return
+	}
+
+	maturity, err := entities.ParseMaturity(m.maturity.Value())
+	if err != nil {
+		m.err = err
+		return
+	}
+
+	body := strings.TrimSpace(m.body.Value())
+	project := entities.NormalizeProject(m.project.Value())
+	tags := entities.ParseTags(m.tags.Value())
+
+	if m.existingIdea != nil {
+		// Update existing idea
+		m.existingIdea.Content = title
+		m.existingIdea.Metadata.Body = body
+		m.existingIdea.Metadata.Maturity = maturity
+		m.existingIdea.Metadata.Category = project
+		m.existingIdea.Metadata.Tags = tags
+		m.existingIdea.UpdatedAt = time.Now()
+		m.idea = m.existingIdea
+	} else {
+		// Create new idea
+		m.idea = entities.NewIdea(title, body, maturity)
+		m.idea.Metadata.Category = project
+		m.idea.Metadata.Tags = tags
+	}
+
+	m.err = nil
+}
+
+// GetIdea returns the created idea
+func (m *IdeaFormModel) GetIdea() *entities.Note {
+	return m.idea
+}
+
+// maturityValidator validates maturity input
+func maturityValidator(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return nil // Optional field
+	}
+	_, err := entities.ParseMaturity(s)
+	return err
+}
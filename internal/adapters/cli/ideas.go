@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+// newIdeaCommand creates the idea command
+func (cli *CLI) newIdeaCommand() *cobra.Command {
+	ideaCmd := &cobra.Command{
+		Use:   "idea [title]",
+		Short: "Capture an idea",
+		Long: "Capture an idea with a title, an optional body and a stage: raw, explored or promoted. " +
+			"Without a title the interactive form opens.",
+		Run: cli.createIdea,
+	}
+	ideaCmd.Flags().String("body", "", "Longer description of the idea")
+	ideaCmd.Flags().String("stage", "raw", "Stage of the idea: raw or explored")
+	ideaCmd.Flags().StringSlice("tag", nil, "Tag the idea (repeatable)")
+	ideaCmd.Flags().String("project", "", "Put the idea in a project, such as infra/k8s")
+
+	return ideaCmd
+}
+
+// newPromoteCommand creates the promote command
+func (cli *CLI) newPromoteCommand() *cobra.Command {
+	promoteCmd := &cobra.Command{
+		Use:   "promote <id>",
+		Short: "Turn an idea into a task linked to it",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			priority, _ := cmd.Flags().GetString("priority")
+			if err := priorityValidator(priority); err != nil {
+				fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+				os.Exit(1)
+			}
+
+			cli.promoteIdea(cli.resolveNote(args[0]), entities.Priority(strings.ToLower(priority)))
+		},
+	}
+	promoteCmd.Flags().String("priority", "low", "Priority of the new task: low or high")
+
+	return promoteCmd
+}
+
+// createIdea creates an idea from the command line, or with the form when
+// no title is given
+func (cli *CLI) createIdea(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cli.createIdeaInteractive()
+		return
+	}
+
+	body, _ := cmd.Flags().GetString("body")
+	stage, _ := cmd.Flags().GetString("stage")
+
+	maturity, err := entities.ParseMaturity(stage)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+
+	idea := entities.NewIdea(strings.Join(args, " "), strings.TrimSpace(body), maturity)
+	idea.Metadata.Tags = tagsFlag(cmd)
+	idea.Metadata.Category = projectFlag(cmd)
+	if err := cli.noteService.SaveNote(idea); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error saving idea: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(successStyle.Render("Idea saved successfully!"))
+}
+
+// createIdeaInteractive creates an idea using the interactive form
+func (cli *CLI) createIdeaInteractive() {
+	// Check if we're in a TTY environment
+	if !cli.isTTY() {
+		fmt.Println(errorStyle.Render("Interactive mode requires a TTY environment"))
+		os.Exit(1)
+	}
+
+	idea := runIdeaForm(NewIdeaFormModel())
+	if idea == nil {
+		// User cancelled, exit silently
+		os.Exit(0)
+	}
+
+	if err := cli.noteService.SaveNote(idea); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error saving idea: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(successStyle.Render("Idea created successfully!"))
+	os.Exit(0)
+}
+
+// updateIdeaInteractive updates an idea using the interactive form
+func (cli *CLI) updateIdeaInteractive(idea *entities.Note) {
+	// Check if we're in a TTY environment
+	if !cli.isTTY() {
+		fmt.Println(errorStyle.Render("Interactive mode requires a TTY environment"))
+		os.Exit(1)
+	}
+
+	// The form edits the note in place
+	if runIdeaForm(NewIdeaFormModelWithData(idea)) == nil {
+		os.Exit(0)
+	}
+
+	if err := cli.noteService.SaveNote(idea); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error updating idea: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(successStyle.Render("Idea updated successfully!"))
+	os.Exit(0)
+}
+
+// runIdeaForm runs an idea form and returns the idea, or nil when it was cancelled
+func runIdeaForm(model *IdeaFormModel) *entities.Note {
+	finalModel, err := tea.NewProgram(model, tea.WithAltScreen()).Run()
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error running interactive idea form: %v", err)))
+		os.Exit(1)
+	}
+
+	ideaModel, ok := finalModel.(*IdeaFormModel)
+	if !ok {
+		fmt.Println(errorStyle.Render("Invalid model type returned"))
+		os.Exit(1)
+	}
+
+	return ideaModel.GetIdea()
+}
+
+// promoteIdea turns an idea into a linked task
+func (cli *CLI) promoteIdea(idea *entities.Note, priority entities.Priority) {
+	task, err := cli.noteService.PromoteIdea(idea, priority)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error promoting idea: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Idea promoted to task %s!", task.Alias())))
+}
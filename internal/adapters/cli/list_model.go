@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"jotterxpress/internal/domain/entities"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -59,6 +61,16 @@ type OpenReminderFormMsg struct {
 	Note *entities.Note
 }
 
+// OpenIdeaFormMsg is a message to open idea form for editing
+type OpenIdeaFormMsg struct {
+	Note *entities.Note
+}
+
+// PromoteIdeaMsg is a message to promote an idea to a task
+type PromoteIdeaMsg struct {
+	Note *entities.Note
+}
+
 // CompleteTaskMsg is a message to complete a task
 type CompleteTaskMsg struct {
 	Note *entities.Note
@@ -123,6 +135,12 @@ func (i NoteItem) Description() string {
 			}
 			meta = append(meta, assignee)
 		}
+		if label := dueLabel(i.note, time.Now()); label != "" {
+			if i.note.IsOverdue(time.Now()) {
+				label = overdueStyle.Render(label)
+			}
+			meta = append(meta, label)
+		}
 	case entities.NoteTypeContact:
 		if i.note.Metadata.Phone != "" {
 			phone := i.note.Metadata.Phone
@@ -153,15 +171,38 @@ func (i NoteItem) Description() string {
 			}
 			meta = append(meta, status)
 		}
+	case entities.NoteTypeIdea:
+		maturity := i.note.Metadata.Maturity
+		if maturity == "" {
+			maturity = entities.MaturityRaw
+		}
+		meta = append(meta, "idea", string(maturity))
+		if i.note.Metadata.LinkedNoteID != "" {
+			meta = append(meta, "→ task "+entities.IDAlias(i.note.Metadata.LinkedNoteID))
+		}
 	default:
 		// For text notes, just show the date
 	}
 
+	if i.note.Metadata.Recurrence != nil {
+		meta = append(meta, "↻ "+i.note.Metadata.Recurrence.String())
+	}
+	if i.note.Metadata.Category != "" {
+		meta = append(meta, "["+i.note.Metadata.Category+"]")
+	}
+	if len(i.note.Metadata.Tags) > 0 {
+		meta = append(meta, hashtags(i.note.Metadata.Tags))
+	}
+
 	return strings.Join(meta, " • ")
 }
 
+// FilterValue includes the tags, so filtering on "#infra" finds tagged notes
 func (i NoteItem) FilterValue() string {
-	return i.note.Content
+	if len(i.note.Metadata.Tags) == 0 {
+		return i.note.Content
+	}
+	return i.note.Content + " " + hashtags(i.note.Metadata.Tags)
 }
 
 type listKeyMap struct {
@@ -174,6 +215,11 @@ type listKeyMap struct {
 	selectItem       key.Binding
 	editItem         key.Binding
 	completeItem     key.Binding
+	postponeItem     key.Binding
+	moveItem         key.Binding
+	promoteItem      key.Binding
+	prevProject      key.Binding
+	nextProject      key.Binding
 }
 
 func newListKeyMap() *listKeyMap {
@@ -214,6 +260,26 @@ func newListKeyMap() *listKeyMap {
 			key.WithKeys("c"),
 			key.WithHelp("c", "complete"),
 		),
+		postponeItem: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "postpone to tomorrow"),
+		),
+		moveItem: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "move to date"),
+		),
+		promoteItem: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "promote idea to task"),
+		),
+		prevProject: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "previous project"),
+		),
+		nextProject: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next project"),
+		),
 	}
 }
 
@@ -230,7 +296,7 @@ func newDelegateKeyMap() *delegateKeyMap {
 		),
 		remove: key.NewBinding(
 			key.WithKeys("x", "backspace"),
-			key.WithHelp("x", "delete"),
+			key.WithHelp("x", "trash"),
 		),
 	}
 }
@@ -257,12 +323,21 @@ type ListModel struct {
 	list         list.Model
 	keys         *listKeyMap
 	delegateKeys *delegateKeyMap
-	notes        []*entities.Note
+	notes        []*entities.Note // Notes shown, in list order
+	allNotes     []*entities.Note // Notes of every project
+	projects     projectSidebar
 	title        string
 	selected     map[int]struct{} // Track selected items
 	showMenu     bool
 	showPreview  bool
 	selectedNote *entities.Note
+	showHistory  bool                 // Step through revisions in the preview
+	revisions    []*entities.Revision // History of the previewed note
+	historyIndex int                  // Revision shown in the history pane
+	pickingDate  bool                 // Asking for the date to move a note to
+	dateInput    textinput.Model
+	width        int
+	height       int
 	cli          *CLI // Reference to CLI for calling update methods
 }
 
@@ -284,6 +359,8 @@ func NewListModel(notes []*entities.Note, title string, cli *CLI) ListModel {
 		keys:         listKeys,
 		delegateKeys: delegateKeys,
 		notes:        notes,
+		allNotes:     notes,
+		projects:     newProjectSidebar(notes),
 		title:        title,
 		selected:     make(map[int]struct{}),
 		showMenu:     false,
@@ -308,6 +385,11 @@ func NewListModel(notes []*entities.Note, title string, cli *CLI) ListModel {
 			listKeys.selectItem,
 			listKeys.editItem,
 			listKeys.completeItem,
+			listKeys.postponeItem,
+			listKeys.moveItem,
+			listKeys.promoteItem,
+			listKeys.prevProject,
+			listKeys.nextProject,
 		}
 	}
 
@@ -324,8 +406,13 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		// Use full width and height
-		m.list.SetSize(msg.Width, msg.Height)
+		// Use full width and height, less the project sidebar
+		m.width, m.height = msg.Width, msg.Height
+		if m.projects.visible() {
+			m.list.SetSize(msg.Width-projectSidebarWidth, msg.Height)
+		} else {
+			m.list.SetSize(msg.Width, msg.Height)
+		}
 
 	case ContextMenuMsg:
 		if msg.Action == "open" {
@@ -366,6 +453,13 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							return OpenReminderFormMsg{Note: m.selectedNote}
 						}),
 					)
+				} else if m.selectedNote.Type == entities.NoteTypeIdea {
+					// For ideas, open idea form
+					return m, tea.Batch(
+						tea.Cmd(func() tea.Msg {
+							return OpenIdeaFormMsg{Note: m.selectedNote}
+						}),
+					)
 				}
 				// For other types, we'll implement later
 			}
@@ -385,6 +479,15 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}),
 				)
 			}
+		} else if msg.Action == "promote" {
+			// Handle promote idea action
+			if m.selectedNote != nil && m.selectedNote.Type == entities.NoteTypeIdea {
+				return m, tea.Batch(
+					tea.Cmd(func() tea.Msg {
+						return PromoteIdeaMsg{Note: m.selectedNote}
+					}),
+				)
+			}
 		}
 
 	case OpenTextareaMsg:
@@ -419,6 +522,24 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
+	case OpenIdeaFormMsg:
+		// Handle opening idea form for editing
+		if m.cli != nil {
+			m.cli.updateIdeaInteractive(msg.Note)
+			return m, tea.Quit
+		}
+
+	case PromoteIdeaMsg:
+		// Handle promoting an idea, staying in the list
+		if m.cli != nil {
+			m.selectedNote = nil
+			task, err := m.cli.noteService.PromoteIdea(msg.Note, entities.PriorityLow)
+			if err != nil {
+				return m, m.list.NewStatusMessage(errorStyle.Render(fmt.Sprintf("Error promoting idea: %v", err)))
+			}
+			return m, m.list.NewStatusMessage(statusMessageStyle("Promoted to task " + task.Alias()))
+		}
+
 	case CompleteTaskMsg:
 		// Handle completing a task
 		if m.cli != nil {
@@ -444,14 +565,23 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case DeleteNoteMsg:
 		// Handle deleting a note
 		if m.cli != nil {
-			m.cli.deleteNotePermanently(msg.Note)
-			// Rebuild the list without the deleted note
-			var filteredNotes []*entities.Note
-			for _, note := range m.notes {
+			if err := m.cli.deleteNote(msg.Note); err != nil {
+				return m, m.list.NewStatusMessage(errorStyle.Render(fmt.Sprintf("Error deleting note: %v", err)))
+			}
+
+			// Rebuild the list and the project counts without the deleted note
+			var remaining []*entities.Note
+			for _, note := range m.allNotes {
 				if note.ID != msg.Note.ID {
-					filteredNotes = append(filteredNotes, note)
+					remaining = append(remaining, note)
 				}
 			}
+			project := m.projects.current()
+			m.allNotes = remaining
+			m.projects = newProjectSidebar(remaining)
+			m.projects.selectProject(project)
+
+			filteredNotes := m.projects.filter(remaining)
 			m.notes = filteredNotes
 
 			// Rebuild list items
@@ -466,17 +596,59 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			newList.AdditionalFullHelpKeys = m.list.AdditionalFullHelpKeys
 			m.list = newList
 
-			return m, nil
+			return m, m.list.NewStatusMessage(statusMessageStyle("Moved to trash (jtx trash restore " + msg.Note.ID + ")"))
 		}
 		return m, nil
 
 	case tea.KeyMsg:
-		// Handle preview keys first
+		// Handle the date prompt first
+		if m.pickingDate {
+			switch msg.String() {
+			case "enter":
+				m.pickingDate = false
+				return m, m.moveSelected(m.dateInput.Value())
+			case "ctrl+c", "esc":
+				m.pickingDate = false
+				m.selectedNote = nil
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.dateInput, cmd = m.dateInput.Update(msg)
+			return m, cmd
+		}
+
+		// Handle history keys, then preview keys
+		if m.showPreview && m.showHistory {
+			switch msg.String() {
+			case "left", "h":
+				if m.historyIndex > 0 {
+					m.historyIndex--
+				}
+			case "right", "l":
+				if m.historyIndex < len(m.revisions)-1 {
+					m.historyIndex++
+				}
+			case "ctrl+c", "esc", "q":
+				m.showHistory = false
+				m.revisions = nil
+			}
+			return m, nil
+		}
 		if m.showPreview {
-			if k := msg.String(); k == "ctrl+c" || k == "esc" || k == "q" {
+			switch msg.String() {
+			case "ctrl+c", "esc", "q":
 				m.showPreview = false
 				m.selectedNote = nil
-				return m, nil
+			case "H":
+				if m.cli != nil && m.selectedNote != nil {
+					revisions, err := m.cli.noteService.GetHistory(m.selectedNote.ID)
+					if err != nil || len(revisions) == 0 {
+						return m, nil
+					}
+					m.revisions = revisions
+					m.historyIndex = len(revisions) - 1
+					m.showHistory = true
+				}
 			}
 			return m, nil
 		}
@@ -505,6 +677,15 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						}),
 					)
 				}
+				// Promote action (for ideas not promoted yet)
+				if m.selectedNote != nil && m.selectedNote.Type == entities.NoteTypeIdea && m.selectedNote.Metadata.LinkedNoteID == "" {
+					m.showMenu = false
+					return m, tea.Batch(
+						tea.Cmd(func() tea.Msg {
+							return ContextMenuMsg{Action: "promote"}
+						}),
+					)
+				}
 			}
 			return m, nil
 		}
@@ -536,6 +717,9 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else if selectedNote.Type == entities.NoteTypeContact {
 					m.cli.updateContactInteractive(selectedNote)
 					return m, tea.Quit
+				} else if selectedNote.Type == entities.NoteTypeIdea {
+					m.cli.updateIdeaInteractive(selectedNote)
+					return m, tea.Quit
 				}
 			}
 			return m, nil
@@ -555,6 +739,50 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case key.Matches(msg, m.keys.promoteItem):
+			// Promote the currently selected idea
+			currentIndex := m.list.Index()
+			if currentIndex < len(m.notes) && m.notes[currentIndex].Type == entities.NoteTypeIdea {
+				note := m.notes[currentIndex]
+				return m, tea.Cmd(func() tea.Msg {
+					return PromoteIdeaMsg{Note: note}
+				})
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.postponeItem):
+			currentIndex := m.list.Index()
+			if currentIndex < len(m.notes) {
+				m.selectedNote = m.notes[currentIndex]
+				return m, m.moveSelected("tomorrow")
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.moveItem):
+			// Ask for the date, starting from tomorrow
+			currentIndex := m.list.Index()
+			if currentIndex < len(m.notes) {
+				m.selectedNote = m.notes[currentIndex]
+				m.dateInput = textinput.New()
+				m.dateInput.Placeholder = "YYYY-MM-DD, today, tomorrow or +3d"
+				m.dateInput.SetValue(time.Now().AddDate(0, 0, 1).Format("2006-01-02"))
+				m.dateInput.Focus()
+				m.pickingDate = true
+				return m, textinput.Blink
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.prevProject), key.Matches(msg, m.keys.nextProject):
+			if !m.projects.visible() {
+				return m, nil
+			}
+			if key.Matches(msg, m.keys.prevProject) {
+				m.projects.move(-1)
+			} else {
+				m.projects.move(1)
+			}
+			return m, m.showProject()
+
 		case key.Matches(msg, m.keys.selectItem):
 			// Toggle selection of current item
 			currentIndex := m.list.Index()
@@ -598,7 +826,45 @@ func (m ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// showProject narrows the list to the project selected in the sidebar
+func (m *ListModel) showProject() tea.Cmd {
+	m.notes = m.projects.filter(m.allNotes)
+	m.selected = make(map[int]struct{})
+
+	items := make([]list.Item, len(m.notes))
+	for i, note := range m.notes {
+		items[i] = NoteItem{note: note}
+	}
+	m.list.ResetFilter()
+	return m.list.SetItems(items)
+}
+
+// moveSelected moves the selected note to a date and reports the result in
+// the status bar
+func (m *ListModel) moveSelected(to string) tea.Cmd {
+	note := m.selectedNote
+	m.selectedNote = nil
+	if note == nil || m.cli == nil {
+		return nil
+	}
+
+	date, err := parseDate(to)
+	if err == nil {
+		err = m.cli.noteService.MoveNote(note, date)
+	}
+	if err != nil {
+		return m.list.NewStatusMessage(errorStyle.Render(fmt.Sprintf("Error moving note: %v", err)))
+	}
+
+	return m.list.NewStatusMessage(statusMessageStyle("Moved to " + date))
+}
+
 func (m ListModel) View() string {
+	// If the date prompt is open, show it
+	if m.pickingDate && m.selectedNote != nil {
+		return m.renderDatePrompt()
+	}
+
 	// If preview is open, show it
 	if m.showPreview && m.selectedNote != nil {
 		return m.renderPreview()
@@ -609,7 +875,11 @@ func (m ListModel) View() string {
 		return m.renderContextMenu()
 	}
 
-	// Create a prominent title
+	// Create a prominent title, naming the project shown
+	heading := m.title
+	if project := m.projects.current(); project != "" {
+		heading += " · " + project
+	}
 	title := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("#FFFDF5")).
@@ -618,9 +888,13 @@ func (m ListModel) View() string {
 		MarginBottom(1).
 		Width(m.list.Width()).
 		Align(lipgloss.Center).
-		Render(m.title)
+		Render(heading)
 
-	return title + "\n" + m.list.View()
+	view := title + "\n" + m.list.View()
+	if !m.projects.visible() {
+		return view
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, m.projects.View(m.height), view)
 }
 
 func (m ListModel) renderContextMenu() string {
@@ -656,6 +930,13 @@ func (m ListModel) renderContextMenu() string {
 		options = []string{
 			"1. Update",
 		}
+	case entities.NoteTypeIdea:
+		options = []string{
+			"1. Update",
+		}
+		if m.selectedNote.Metadata.LinkedNoteID == "" {
+			options = append(options, "2. Promote to Task")
+		}
 	default: // Text notes
 		options = []string{
 			"1. Update",
@@ -684,13 +965,65 @@ func (m ListModel) renderContextMenu() string {
 	return menuTitle + "\n" + optionsText.String() + "\n" + helpText
 }
 
+// renderDatePrompt asks for the date to move the selected note to
+func (m ListModel) renderDatePrompt() string {
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color("#7D56F4")).
+		Padding(1, 2).
+		MarginBottom(1).
+		Width(m.list.Width()).
+		Align(lipgloss.Center).
+		Render(fmt.Sprintf("Move: %s", m.selectedNote.Content))
+
+	help := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#626262")).
+		MarginTop(1).
+		Render("Enter to move, Esc to cancel")
+
+	return title + "\n  Date: " + m.dateInput.View() + "\n" + help
+}
+
 func (m ListModel) renderPreview() string {
 	if m.selectedNote == nil {
 		return ""
 	}
 
-	note := m.selectedNote
+	if m.showHistory && m.historyIndex < len(m.revisions) {
+		return m.renderHistory()
+	}
+
+	// Center the modal on screen
+	return lipgloss.Place(80, 0, lipgloss.Center, lipgloss.Center, renderNoteDetails(m.selectedNote)) + "\n" +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("  Press H for history, Esc or Q to close")
+}
 
+// renderHistory renders one revision of the previewed note and what changed
+// since the revision before it
+func (m ListModel) renderHistory() string {
+	revision := m.revisions[m.historyIndex]
+
+	header := fmt.Sprintf("Revision %d of %d, saved %s",
+		revision.Number, len(m.revisions), revision.SavedAt.Format("2006-01-02 15:04:05"))
+	if m.historyIndex == len(m.revisions)-1 {
+		header += " (current)"
+	}
+
+	var previous []string
+	if m.historyIndex > 0 {
+		previous = revisionLines(m.revisions[m.historyIndex-1].Note)
+	}
+	changes := renderDiff(diffLines(previous, revisionLines(revision.Note)))
+
+	return infoStyle.Render("  "+header) + "\n" +
+		lipgloss.Place(80, 0, lipgloss.Center, lipgloss.Center, renderNoteDetails(revision.Note)) + "\n\n" +
+		changes + "\n\n" +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("  ←/→ older/newer revision, Esc or Q to go back")
+}
+
+// renderNoteDetails renders all fields of a note in a modal box
+func renderNoteDetails(note *entities.Note) string {
 	// Modal box style
 	modalStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -701,6 +1034,7 @@ func (m ListModel) renderPreview() string {
 
 	// Content
 	var content strings.Builder
+	content.WriteString(fmt.Sprintf("%s %s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("ID:"), note.ID+" ("+note.Alias()+")"))
 	content.WriteString(fmt.Sprintf("%s %s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Type:"), note.Type))
 	content.WriteString(fmt.Sprintf("%s %s\n\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Content:"), note.Content))
 	content.WriteString(fmt.Sprintf("%s %s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Created:"), note.CreatedAt.Format("2006-01-02 15:04:05")))
@@ -719,6 +1053,13 @@ func (m ListModel) renderPreview() string {
 		if note.Metadata.Assignee != "" {
 			content.WriteString(fmt.Sprintf("%s %s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Assignee:"), note.Metadata.Assignee))
 		}
+		if note.Metadata.DueDate != nil {
+			due := note.Metadata.DueDate.Format("Mon 2006-01-02")
+			if note.IsOverdue(time.Now()) {
+				due += " " + overdueStyle.Render("(overdue)")
+			}
+			content.WriteString(fmt.Sprintf("%s %s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Due:"), due))
+		}
 	case entities.NoteTypeContact:
 		if note.Metadata.Phone != "" {
 			content.WriteString(fmt.Sprintf("%s %s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Phone:"), note.Metadata.Phone))
@@ -733,14 +1074,41 @@ func (m ListModel) renderPreview() string {
 		if note.Metadata.Status != "" {
 			content.WriteString(fmt.Sprintf("%s %s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Status:"), note.Metadata.Status))
 		}
+	case entities.NoteTypeIdea:
+		if note.Metadata.Maturity != "" {
+			content.WriteString(fmt.Sprintf("%s %s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Stage:"), note.Metadata.Maturity))
+		}
+		if note.Metadata.LinkedNoteID != "" {
+			content.WriteString(fmt.Sprintf("%s %s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Promoted to:"), entities.IDAlias(note.Metadata.LinkedNoteID)))
+		}
+		if note.Metadata.Body != "" {
+			content.WriteString(fmt.Sprintf("\n%s\n%s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Body:"), note.Metadata.Body))
+		}
+	}
+
+	// Tasks promoted from an idea link back to it
+	if note.Type == entities.NoteTypeTask && note.Metadata.LinkedNoteID != "" {
+		content.WriteString(fmt.Sprintf("%s %s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("From idea:"), entities.IDAlias(note.Metadata.LinkedNoteID)))
+	}
+	if note.Metadata.Recurrence != nil {
+		content.WriteString(fmt.Sprintf("%s %s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Repeats:"), note.Metadata.Recurrence.Describe()))
 	}
 
-	// Modal content
-	modalContent := modalStyle.Render(content.String())
+	if note.Metadata.Category != "" {
+		content.WriteString(fmt.Sprintf("%s %s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Project:"), note.Metadata.Category))
+	}
+	if len(note.Metadata.Tags) > 0 {
+		content.WriteString(fmt.Sprintf("%s %s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Tags:"), hashtags(note.Metadata.Tags)))
+	}
 
-	// Center the modal on screen
-	return lipgloss.Place(80, 0, lipgloss.Center, lipgloss.Center, modalContent) + "\n" +
-		lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("  Press Esc or Q to close")
+	if len(note.Metadata.Attachments) > 0 {
+		content.WriteString(fmt.Sprintf("\n%s\n", lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6")).Render("Attachments:")))
+		for _, line := range attachmentLines(note.Metadata.Attachments) {
+			content.WriteString("  " + line + "\n")
+		}
+	}
+
+	return modalStyle.Render(content.String())
 }
 
 func (m ListModel) generateMarkdownForNote(note *entities.Note) string {
@@ -784,6 +1152,23 @@ func (m ListModel) generateMarkdownForNote(note *entities.Note) string {
 		if note.Metadata.Status != "" {
 			md.WriteString(fmt.Sprintf("**Status:** %s\n\n", note.Metadata.Status))
 		}
+	case entities.NoteTypeIdea:
+		if note.Metadata.Maturity != "" {
+			md.WriteString(fmt.Sprintf("**Stage:** %s\n\n", note.Metadata.Maturity))
+		}
+		if note.Metadata.Body != "" {
+			md.WriteString(note.Metadata.Body + "\n\n")
+		}
+	}
+
+	if note.Metadata.Recurrence != nil {
+		md.WriteString(fmt.Sprintf("**Repeats:** %s\n\n", note.Metadata.Recurrence.Describe()))
+	}
+	if note.Metadata.Category != "" {
+		md.WriteString(fmt.Sprintf("**Project:** %s\n\n", note.Metadata.Category))
+	}
+	if len(note.Metadata.Tags) > 0 {
+		md.WriteString(fmt.Sprintf("**Tags:** %s\n\n", hashtags(note.Metadata.Tags)))
 	}
 
 	return md.String()
@@ -810,7 +1195,7 @@ func newItemDelegate(keys *delegateKeyMap) list.DefaultDelegate {
 			case key.Matches(msg, keys.remove):
 				if i, ok := m.SelectedItem().(NoteItem); ok {
 					return tea.Batch(
-						m.NewStatusMessage(statusMessageStyle("Deleting...")),
+						m.NewStatusMessage(statusMessageStyle("Moving to trash...")),
 						tea.Cmd(func() tea.Msg {
 							return DeleteNoteMsg{Note: i.note}
 						}),
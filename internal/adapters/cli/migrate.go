@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"jotterxpress/internal/adapters/repository"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// schemaMigrator is implemented by repositories with a versioned storage schema
+type schemaMigrator interface {
+	SchemaVersion() (int, error)
+	LatestSchemaVersion() int
+	Migrate(dryRun bool) (*repository.MigrationReport, error)
+}
+
+// newMigrateCommand creates the migrate command
+func (cli *CLI) newMigrateCommand() *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade the storage schema of the current notebook",
+		Long: "Run every pending storage migration in order, after backing up the notes to " +
+			repository.BackupDirName + " in the notes directory. " +
+			"Other commands refuse to run until the pending migrations are applied.",
+		Args: cobra.NoArgs,
+		// Open the notebook without the storage check, so --dry-run can report it
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			cli.openNotebook(cli.notebookName(cmd))
+		},
+		Run: cli.runMigrations,
+	}
+	migrateCmd.Flags().Bool("dry-run", false, "Only report the pending migrations and what they change")
+
+	return migrateCmd
+}
+
+// runMigrations runs or reports the pending migrations of the current notebook
+func (cli *CLI) runMigrations(cmd *cobra.Command, args []string) {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	migrator, ok := cli.repository.(schemaMigrator)
+	if !ok {
+		fmt.Println(errorStyle.Render("The current storage backend does not support migrations"))
+		os.Exit(1)
+	}
+
+	report, err := migrator.Migrate(dryRun)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error migrating storage: %v", err)))
+		os.Exit(1)
+	}
+
+	if len(report.Steps) == 0 {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Storage schema is up to date (version %d).", report.From)))
+		return
+	}
+
+	if dryRun {
+		fmt.Println(titleStyle.Render(fmt.Sprintf("Pending migrations: version %d to %d", report.From, report.To)))
+	} else {
+		fmt.Println(titleStyle.Render(fmt.Sprintf("Migrated storage schema from version %d to %d", report.From, report.To)))
+	}
+	fmt.Println("")
+
+	for _, step := range report.Steps {
+		fmt.Printf("%s %s\n", infoStyle.Render(fmt.Sprintf("%d.", step.Version)), step.Description)
+		if len(step.Changes) == 0 {
+			fmt.Println("   nothing to change")
+		}
+		for _, change := range step.Changes {
+			fmt.Printf("   - %s\n", change)
+		}
+	}
+
+	if report.BackupPath != "" {
+		fmt.Println("")
+		fmt.Println(successStyle.Render("Backup written to " + report.BackupPath))
+	}
+	if dryRun {
+		fmt.Println("")
+		fmt.Println(infoStyle.Render("Run 'jtx migrate' to apply them."))
+	}
+}
+
+// errMigrationsPending is returned by checkStorage when a pending migration
+// would change the stored notes
+var errMigrationsPending = errors.New("storage schema migrations are pending")
+
+// checkStorage stamps the schema version of a repository whose pending
+// migrations have nothing to change, such as a new notebook. Migrations that
+// would change notes are left for jtx migrate, so they can be reviewed with
+// --dry-run first; checkStorage then returns errMigrationsPending.
+func checkStorage(repo any) error {
+	migrator, ok := repo.(schemaMigrator)
+	if !ok {
+		return nil
+	}
+
+	version, err := migrator.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	if version >= migrator.LatestSchemaVersion() {
+		return nil
+	}
+
+	report, err := migrator.Migrate(true)
+	if err != nil {
+		return err
+	}
+	for _, step := range report.Steps {
+		if len(step.Changes) > 0 {
+			return fmt.Errorf("%w: version %d to %d", errMigrationsPending, report.From, report.To)
+		}
+	}
+
+	_, err = migrator.Migrate(false)
+	return err
+}
+
+// upgradeStorage runs the pending migrations of a repository, if any. It
+// stays quiet unless a migration changed something. It is used for storage
+// the user does not work in directly, such as an extracted backup.
+func upgradeStorage(repo any) error {
+	migrator, ok := repo.(schemaMigrator)
+	if !ok {
+		return nil
+	}
+
+	version, err := migrator.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	if version >= migrator.LatestSchemaVersion() {
+		return nil
+	}
+
+	report, err := migrator.Migrate(false)
+	if err != nil {
+		return err
+	}
+
+	if report.BackupPath != "" {
+		fmt.Fprintln(os.Stderr, infoStyle.Render(fmt.Sprintf("Upgraded storage schema from version %d to %d (backup in %s).",
+			report.From, report.To, report.BackupPath)))
+	}
+	return nil
+}
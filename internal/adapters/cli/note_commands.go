@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// newShowCommand creates the show command
+func (cli *CLI) newShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show a note by ID or unique ID prefix",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			note := cli.resolveNote(args[0])
+			fmt.Println(lipgloss.Place(80, 0, lipgloss.Left, lipgloss.Top, renderNoteDetails(note)))
+		},
+	}
+}
+
+// newEditCommand creates the edit command
+func (cli *CLI) newEditCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit <id>",
+		Short: "Edit a note by ID or unique ID prefix",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.editNote(cli.resolveNote(args[0]))
+		},
+	}
+}
+
+// newDoneCommand creates the done command
+func (cli *CLI) newDoneCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "done <id>",
+		Short: "Complete a task or reminder by ID or unique ID prefix",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			note := cli.resolveNote(args[0])
+
+			switch note.Type {
+			case entities.NoteTypeTask:
+				cli.completeTaskInteractive(note)
+			case entities.NoteTypeReminder:
+				cli.completeReminderInteractive(note)
+			default:
+				fmt.Println(errorStyle.Render(fmt.Sprintf("Error: only tasks and reminders can be completed, %s is a %s", note.ID, note.Type)))
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+// newRmCommand creates the rm command
+func (cli *CLI) newRmCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <id>",
+		Short: "Move a note to the trash by ID or unique ID prefix",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			note := cli.resolveNote(args[0])
+
+			if err := cli.deleteNote(note); err != nil {
+				fmt.Println(errorStyle.Render(fmt.Sprintf("Error deleting note: %v", err)))
+				os.Exit(1)
+			}
+
+			fmt.Println(successStyle.Render("Note moved to trash!"))
+			fmt.Println(infoStyle.Render(fmt.Sprintf("Restore it with: jtx trash restore %s", note.Alias())))
+		},
+	}
+}
+
+// newMoveCommand creates the move command
+func (cli *CLI) newMoveCommand() *cobra.Command {
+	moveCmd := &cobra.Command{
+		Use:   "move <id> --to <date>",
+		Short: "Reschedule a note to another date",
+		Long: "Move a note to another date. The date is YYYY-MM-DD, today, tomorrow, a weekday such as fri, " +
+			"or a number of days or weeks from today such as +3d or +2w.",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			to, _ := cmd.Flags().GetString("to")
+			note := cli.resolveNote(args[0])
+
+			date, err := parseDate(to)
+			if err != nil {
+				fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+				os.Exit(1)
+			}
+
+			if err := cli.noteService.MoveNote(note, date); err != nil {
+				fmt.Println(errorStyle.Render(fmt.Sprintf("Error moving note: %v", err)))
+				os.Exit(1)
+			}
+
+			fmt.Println(successStyle.Render(fmt.Sprintf("Note moved to %s.", date)))
+		},
+	}
+	moveCmd.Flags().String("to", "", "Target date: YYYY-MM-DD, today, tomorrow, a weekday, +Nd or +Nw")
+	moveCmd.MarkFlagRequired("to")
+
+	return moveCmd
+}
+
+// parseDate parses a date given as YYYY-MM-DD, today, tomorrow, a weekday
+// such as fri (the next one after today) or a number of days or weeks from
+// today such as +3d or +2w, and returns it as YYYY-MM-DD
+func parseDate(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	today := time.Now()
+
+	switch {
+	case s == "today":
+		return today.Format("2006-01-02"), nil
+	case s == "tomorrow":
+		return today.AddDate(0, 0, 1).Format("2006-01-02"), nil
+	case strings.HasPrefix(s, "+") && (strings.HasSuffix(s, "d") || strings.HasSuffix(s, "w")):
+		n, err := strconv.Atoi(s[1 : len(s)-1])
+		if err != nil {
+			return "", fmt.Errorf("invalid date %q", s)
+		}
+		if strings.HasSuffix(s, "w") {
+			n *= 7
+		}
+		return today.AddDate(0, 0, n).Format("2006-01-02"), nil
+	}
+
+	if weekday, ok := entities.ParseWeekday(s); ok {
+		days := (int(weekday)-int(today.Weekday())+6)%7 + 1
+		return today.AddDate(0, 0, days).Format("2006-01-02"), nil
+	}
+
+	if _, err := time.Parse("2006-01-02", s); err != nil {
+		return "", fmt.Errorf("invalid date %q, expected YYYY-MM-DD, today, tomorrow, a weekday, +Nd or +Nw", s)
+	}
+	return s, nil
+}
+
+// resolveNote finds a note by ID, unique ID prefix or alias, exiting with a helpful
+// message when there is no match or more than one
+func (cli *CLI) resolveNote(ref string) *entities.Note {
+	note, err := cli.noteService.GetNoteByID(ref)
+	if err == nil {
+		return note
+	}
+
+	var ambiguous *ports.AmbiguousIDError
+	switch {
+	case errors.As(err, &ambiguous):
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %q matches %d notes, use a longer prefix:", ref, len(ambiguous.Matches))))
+		for _, id := range ambiguous.Matches {
+			fmt.Printf("  %s\n", id)
+		}
+	case errors.Is(err, ports.ErrNoteNotFound):
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: no note found with ID %q", ref)))
+	default:
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error retrieving note: %v", err)))
+	}
+
+	os.Exit(1)
+	return nil
+}
+
+// editNote opens the editor matching the note type
+func (cli *CLI) editNote(note *entities.Note) {
+	switch note.Type {
+	case entities.NoteTypeTask:
+		cli.updateTaskInteractive(note)
+	case entities.NoteTypeReminder:
+		cli.updateReminderInteractive(note)
+	case entities.NoteTypeContact:
+		cli.updateContactInteractive(note)
+	case entities.NoteTypeIdea:
+		cli.updateIdeaInteractive(note)
+	default:
+		cli.updateNoteInteractive(note)
+	}
+}
@@ -7,14 +7,26 @@ import (
 	"time"
 
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
 type noteErrMsg error
 
+// Fields of the note form, in the order Tab moves through them
+const (
+	noteText = iota
+	noteProject
+	noteTags
+	noteFieldCount
+)
+
 type NoteTextareaModel struct {
 	textarea     textarea.Model
+	project      textinput.Model
+	tags         textinput.Model
+	focused      int
 	err          error
 	note         *entities.Note
 	existingNote *entities.Note // For updating existing notes
@@ -38,6 +50,8 @@ func NewNoteTextareaModel() *NoteTextareaModel {
 
 	return &NoteTextareaModel{
 		textarea: ti,
+		project:  newProjectInput(""),
+		tags:     newTagsInput(nil),
 		err:      nil,
 	}
 }
@@ -55,6 +69,8 @@ func NewNoteTextareaModelWithContent(note *entities.Note) *NoteTextareaModel {
 
 	return &NoteTextareaModel{
 		textarea:     ti,
+		project:      newProjectInput(note.Metadata.Category),
+		tags:         newTagsInput(note.Metadata.Tags),
 		err:          nil,
 		existingNote: note, // Store the existing note
 	}
@@ -75,17 +91,27 @@ func (m NoteTextareaModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Ctrl+S to save
 			content := strings.TrimSpace(m.textarea.Value())
 			if content != "" {
+				project := entities.NormalizeProject(m.project.Value())
+				tags := entities.ParseTags(m.tags.Value())
 				if m.existingNote != nil {
 					// Update existing note
 					m.existingNote.Content = content
+					m.existingNote.Metadata.Category = project
+					m.existingNote.Metadata.Tags = tags
 					m.existingNote.UpdatedAt = time.Now()
 					m.note = m.existingNote
 				} else {
 					// Create new note
 					m.note = entities.NewNote(content)
+					m.note.Metadata.Category = project
+					m.note.Metadata.Tags = tags
 				}
 				return &m, tea.Quit
 			}
+		case tea.KeyTab:
+			return &m, m.focus((m.focused + 1) % noteFieldCount)
+		case tea.KeyShiftTab:
+			return &m, m.focus((m.focused + noteFieldCount - 1) % noteFieldCount)
 		case tea.KeyEsc:
 			if m.textarea.Focused() {
 				m.textarea.Blur()
@@ -94,7 +120,7 @@ func (m NoteTextareaModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.cancelled = true
 			return &m, tea.Quit
 		default:
-			if !m.textarea.Focused() {
+			if m.focused == noteText && !m.textarea.Focused() {
 				cmd = m.textarea.Focus()
 				cmds = append(cmds, cmd)
 			}
@@ -106,7 +132,14 @@ func (m NoteTextareaModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return &m, nil
 	}
 
-	m.textarea, cmd = m.textarea.Update(msg)
+	switch m.focused {
+	case noteProject:
+		m.project, cmd = m.project.Update(msg)
+	case noteTags:
+		m.tags, cmd = m.tags.Update(msg)
+	default:
+		m.textarea, cmd = m.textarea.Update(msg)
+	}
 	cmds = append(cmds, cmd)
 	return &m, tea.Batch(cmds...)
 }
@@ -115,15 +148,36 @@ func (m NoteTextareaModel) View() string {
 	title := noteTitleStyle.Render("Nota de Texto")
 
 	content := fmt.Sprintf(
-		"%s\n\n%s\n\n%s",
+		"%s\n\n%s\n\n%s  %s\n%s  %s\n\n%s",
 		title,
 		m.textarea.View(),
-		noteInfoStyle.Render("Ctrl+S para guardar • Ctrl+C para cancelar"),
+		labelStyle.Width(30).Render("Proyecto"),
+		labelStyle.Width(50).Render("Etiquetas"),
+		m.project.View(),
+		m.tags.View(),
+		noteInfoStyle.Render("Ctrl+S para guardar • Tab para proyecto y etiquetas • Ctrl+C para cancelar"),
 	)
 
 	return content + "\n"
 }
 
+// focus moves the focus to the given field
+func (m *NoteTextareaModel) focus(field int) tea.Cmd {
+	m.focused = field
+	m.textarea.Blur()
+	m.project.Blur()
+	m.tags.Blur()
+
+	switch field {
+	case noteProject:
+		return m.project.Focus()
+	case noteTags:
+		return m.tags.Focus()
+	default:
+		return m.textarea.Focus()
+	}
+}
+
 // GetNote returns the created note
 func (m *NoteTextareaModel) GetNote() *entities.Note {
 	return m.note
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// newNotebookCommand creates the notebook command and its subcommands
+func (cli *CLI) newNotebookCommand() *cobra.Command {
+	notebookCmd := &cobra.Command{
+		Use:   "notebook",
+		Short: "Manage notebooks",
+		Long: "Notebooks keep separate sets of notes, each in its own directory. " +
+			"Use --notebook NAME with any command to work in a notebook other than the current one.",
+		// Managing notebooks does not need a repository
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List notebooks",
+		Args:  cobra.NoArgs,
+		Run:   cli.listNotebooks,
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a notebook",
+		Args:  cobra.ExactArgs(1),
+		Run:   cli.createNotebook,
+	}
+	createCmd.Flags().String("path", "", "Directory for the notebook (default: notebooks/<name> in the JotterXpress home)")
+
+	useCmd := &cobra.Command{
+		Use:   "use <name>",
+		Short: "Make a notebook the current one",
+		Args:  cobra.ExactArgs(1),
+		Run:   cli.useNotebook,
+	}
+
+	renameCmd := &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename a notebook",
+		Args:  cobra.ExactArgs(2),
+		Run:   cli.renameNotebook,
+	}
+
+	notebookCmd.AddCommand(listCmd, createCmd, useCmd, renameCmd)
+	return notebookCmd
+}
+
+// listNotebooks lists all notebooks, marking the current one
+func (cli *CLI) listNotebooks(cmd *cobra.Command, args []string) {
+	fmt.Println(titleStyle.Render("Notebooks"))
+	fmt.Println("")
+
+	for _, name := range cli.config.NotebookNames() {
+		marker := "  "
+		if name == cli.config.Notebook {
+			marker = successStyle.Render("* ")
+		}
+		fmt.Printf("%s%-12s %s (%s)\n", marker, name, cli.config.NotebookDir(name), cli.config.NotebookStorage(name))
+	}
+}
+
+// createNotebook creates a notebook and its directory
+func (cli *CLI) createNotebook(cmd *cobra.Command, args []string) {
+	name := args[0]
+	path, _ := cmd.Flags().GetString("path")
+
+	if path != "" {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Error resolving path: %v", err)))
+			os.Exit(1)
+		}
+		path = absPath
+	}
+
+	if err := cli.config.CreateNotebook(name, path); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+
+	if err := os.MkdirAll(cli.config.NotebookDir(name), 0755); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error creating notebook directory: %v", err)))
+		os.Exit(1)
+	}
+
+	if err := cli.config.Save(); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error saving config: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Notebook %s created in %s", name, cli.config.NotebookDir(name))))
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Switch to it with: jtx notebook use %s", name)))
+}
+
+// useNotebook makes a notebook the current one
+func (cli *CLI) useNotebook(cmd *cobra.Command, args []string) {
+	if err := cli.config.UseNotebook(args[0]); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+
+	if err := cli.config.Save(); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error saving config: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Now using notebook %s", args[0])))
+}
+
+// renameNotebook renames a notebook, moving its directory when it lives in
+// the default location
+func (cli *CLI) renameNotebook(cmd *cobra.Command, args []string) {
+	oldName, newName := args[0], args[1]
+	oldDir := cli.config.NotebookDir(oldName)
+
+	if err := cli.config.RenameNotebook(oldName, newName); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+
+	newDir := cli.config.NotebookDir(newName)
+	moved := false
+	if newDir != oldDir {
+		if _, err := os.Stat(newDir); err == nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %s already exists", newDir)))
+			os.Exit(1)
+		}
+
+		if err := os.Rename(oldDir, newDir); err == nil {
+			moved = true
+		} else if !os.IsNotExist(err) {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Error moving notebook directory: %v", err)))
+			os.Exit(1)
+		}
+	}
+
+	if err := cli.config.Save(); err != nil {
+		// Put the directory back so it still matches the config on disk
+		if moved {
+			os.Rename(newDir, oldDir)
+		}
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error saving config: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Notebook %s renamed to %s", oldName, newName)))
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// projectSidebarWidth is the width of the project sidebar, border included
+const projectSidebarWidth = 28
+
+var (
+	projectSidebarStyle = lipgloss.NewStyle().
+				Width(projectSidebarWidth-1).
+				Padding(1, 1).
+				BorderStyle(lipgloss.NormalBorder()).
+				BorderRight(true).
+				BorderForeground(lipgloss.Color("#626262"))
+
+	projectSelectedStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#25A065"))
+	projectItemStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#FAFAFA"))
+	projectHintStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#767676"))
+)
+
+// projectSidebar lists the projects of the notes in the list view, so the
+// list can be narrowed to one project and its subprojects
+type projectSidebar struct {
+	projects []string       // Projects and the projects above them, sorted
+	counts   map[string]int // Notes in each project and its subprojects
+	total    int            // All notes, in a project or not
+	selected int            // 0 shows every note, i shows projects[i-1]
+}
+
+// newProjectSidebar creates a sidebar for the projects of notes
+func newProjectSidebar(notes []*entities.Note) projectSidebar {
+	sidebar := projectSidebar{counts: make(map[string]int), total: len(notes)}
+	for _, note := range notes {
+		for _, project := range entities.ProjectPath(note.Metadata.Category) {
+			if sidebar.counts[project] == 0 {
+				sidebar.projects = append(sidebar.projects, project)
+			}
+			sidebar.counts[project]++
+		}
+	}
+	sort.Strings(sidebar.projects)
+
+	return sidebar
+}
+
+// visible reports whether there are projects to show
+func (s projectSidebar) visible() bool {
+	return len(s.projects) > 0
+}
+
+// current returns the selected project, or "" when every note is shown
+func (s projectSidebar) current() string {
+	if s.selected == 0 {
+		return ""
+	}
+	return s.projects[s.selected-1]
+}
+
+// move selects the next or previous entry, wrapping around
+func (s *projectSidebar) move(delta int) {
+	entries := len(s.projects) + 1
+	s.selected = (s.selected + delta + entries) % entries
+}
+
+// selectProject selects a project by name, or every note if it is gone
+func (s *projectSidebar) selectProject(name string) {
+	s.selected = 0
+	for i, project := range s.projects {
+		if project == name {
+			s.selected = i + 1
+		}
+	}
+}
+
+// filter returns the notes of the selected project
+func (s projectSidebar) filter(notes []*entities.Note) []*entities.Note {
+	return filterByProject(notes, s.current())
+}
+
+// View renders the sidebar at the given height
+func (s projectSidebar) View(height int) string {
+	var content strings.Builder
+	content.WriteString(labelStyle.Render("Projects") + "\n\n")
+
+	entries := append([]string{""}, s.projects...)
+	for i, project := range entries {
+		name, count := "All notes", s.total
+		if project != "" {
+			name, count = projectTreeName(project), s.counts[project]
+		}
+		line := fmt.Sprintf("%s (%d)", name, count)
+		if len(line) > projectSidebarWidth-6 {
+			line = line[:projectSidebarWidth-9] + "..."
+		}
+
+		if i == s.selected {
+			content.WriteString(projectSelectedStyle.Render("▸ "+line) + "\n")
+		} else {
+			content.WriteString(projectItemStyle.Render("  "+line) + "\n")
+		}
+	}
+	content.WriteString("\n" + projectHintStyle.Render("[ ] switch project"))
+
+	style := projectSidebarStyle
+	if height > 2 {
+		style = style.Height(height - 2)
+	}
+	return style.Render(content.String())
+}
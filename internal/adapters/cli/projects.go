@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"fmt"
+	"jotterxpress/internal/application/services"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/spf13/cobra"
+)
+
+// recentProjectNotes is how many notes other than open tasks project show lists
+const recentProjectNotes = 5
+
+// newProjectCommand creates the project command and its subcommands
+func (cli *CLI) newProjectCommand() *cobra.Command {
+	projectCmd := &cobra.Command{
+		Use:   "project",
+		Short: "Group notes and tasks by project",
+		Long: "Group notes and tasks by project. Projects can be nested with slashes, such as infra/k8s, " +
+			"and a project includes the notes of its subprojects.",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List projects with their notes and task progress",
+		Args:  cobra.NoArgs,
+		Run:   cli.listProjects,
+	}
+
+	showCmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show the progress, open tasks and recent notes of a project",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.showProject(args[0])
+		},
+	}
+
+	assignCmd := &cobra.Command{
+		Use:   "assign <id> <name>",
+		Short: "Put a note in a project",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			project := entities.NormalizeProject(args[1])
+			if project == "" {
+				fmt.Println(errorStyle.Render("Error: project name cannot be empty (use jtx project unassign)"))
+				os.Exit(1)
+			}
+			cli.assignProject(cli.resolveNote(args[0]), project)
+		},
+	}
+
+	unassignCmd := &cobra.Command{
+		Use:   "unassign <id>",
+		Short: "Take a note out of its project",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.assignProject(cli.resolveNote(args[0]), "")
+		},
+	}
+
+	projectCmd.AddCommand(listCmd, showCmd, assignCmd, unassignCmd)
+	return projectCmd
+}
+
+// projectService returns the project service of the current notebook
+func (cli *CLI) projectService() ports.ProjectService {
+	return services.NewProjectService(cli.store)
+}
+
+// listProjects prints every project as a tree with its counts
+func (cli *CLI) listProjects(cmd *cobra.Command, args []string) {
+	projects, err := cli.projectService().GetProjects()
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error retrieving projects: %v", err)))
+		os.Exit(1)
+	}
+
+	if len(projects) == 0 {
+		fmt.Println(infoStyle.Render("No projects yet. Use --project when creating a note, or jtx project assign."))
+		return
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Projects (%d)", len(projects))))
+	fmt.Println("")
+	for _, project := range projects {
+		fmt.Printf("%-30s %s\n", projectTreeName(project.Name), projectCounts(project))
+	}
+}
+
+// showProject prints the progress of a project, its open tasks and its most
+// recently updated notes
+func (cli *CLI) showProject(name string) {
+	project, notes, err := cli.projectService().GetProject(name)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(titleStyle.Render("Project " + project.Name))
+	fmt.Println("")
+	fmt.Println(projectCounts(*project))
+	if tasks := project.OpenTasks + project.CompletedTasks; tasks > 0 {
+		fmt.Printf("%s %d%%\n", progressBar(project.CompletedTasks, tasks, 30), project.CompletedTasks*100/tasks)
+	}
+
+	var open, recent []*entities.Note
+	for _, note := range notes {
+		if note.Type == entities.NoteTypeTask && note.Metadata.Status != entities.StatusCompleted {
+			open = append(open, note)
+		} else if len(recent) < recentProjectNotes {
+			recent = append(recent, note)
+		}
+	}
+
+	if len(open) > 0 {
+		fmt.Println("")
+		fmt.Println(infoStyle.Render("Open tasks"))
+		fmt.Println(cli.noteService.ListNotes(open))
+	}
+	if len(recent) > 0 {
+		fmt.Println("")
+		fmt.Println(infoStyle.Render("Recently updated"))
+		fmt.Println(cli.noteService.ListNotes(recent))
+	}
+}
+
+// assignProject puts a note in a project, or takes it out of its project
+// when project is empty
+func (cli *CLI) assignProject(note *entities.Note, project string) {
+	note.Metadata.Category = project
+	if err := cli.noteService.SaveNote(note); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error updating note: %v", err)))
+		os.Exit(1)
+	}
+
+	if project == "" {
+		fmt.Println(successStyle.Render(fmt.Sprintf("Note %s is no longer in a project.", note.Alias())))
+		return
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("Note %s is now in project %s.", note.Alias(), project)))
+}
+
+// projectTreeName indents the last segment of a project name by its depth,
+// so "infra/k8s" is listed as "  k8s" under "infra"
+func projectTreeName(name string) string {
+	depth := strings.Count(name, "/")
+	return strings.Repeat("  ", depth) + name[strings.LastIndex(name, "/")+1:]
+}
+
+// projectCounts describes the notes and task progress of a project
+func projectCounts(project ports.Project) string {
+	counts := fmt.Sprintf("%d notes", project.Notes)
+	if project.Notes == 1 {
+		counts = "1 note"
+	}
+	if project.OpenTasks+project.CompletedTasks > 0 {
+		counts += fmt.Sprintf(" • tasks: %d open, %d completed", project.OpenTasks, project.CompletedTasks)
+	}
+	return counts
+}
+
+// progressBar draws done out of total as a bar of the given width
+func progressBar(done, total, width int) string {
+	filled := done * width / total
+	return successStyle.Render(strings.Repeat("█", filled)) + strings.Repeat("░", width-filled)
+}
+
+// newProjectInput creates the project field of the forms, filled with project
+func newProjectInput(project string) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "infra/k8s (optional)"
+	input.CharLimit = 100
+	input.Width = 30
+	input.SetValue(project)
+	return input
+}
+
+// projectFlag returns the normalized project given with --project
+func projectFlag(cmd *cobra.Command) string {
+	project, _ := cmd.Flags().GetString("project")
+	return entities.NormalizeProject(project)
+}
+
+// filterByProject returns the notes in project or its subprojects
+func filterByProject(notes []*entities.Note, project string) []*entities.Note {
+	if project == "" {
+		return notes
+	}
+
+	var filtered []*entities.Note
+	for _, note := range notes {
+		if note.InProject(project) {
+			filtered = append(filtered, note)
+		}
+	}
+
+	return filtered
+}
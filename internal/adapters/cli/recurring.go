@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/spf13/cobra"
+)
+
+// newRecurringCommand creates the recurring command and its subcommands
+func (cli *CLI) newRecurringCommand() *cobra.Command {
+	recurringCmd := &cobra.Command{
+		Use:   "recurring",
+		Short: "List repeating tasks and reminders, and set or clear their rules",
+		Long: "List the open tasks and reminders that repeat. Completing one creates its next occurrence. " +
+			"Rules are daily, weekdays, weekly:mon,thu, monthly:15 or after:3d (3 days after completion).",
+		Args: cobra.NoArgs,
+		Run:  cli.listRecurring,
+	}
+	recurringCmd.Flags().String("project", "", "Only list items of this project and its subprojects")
+
+	setCmd := &cobra.Command{
+		Use:   "set <id> <rule>",
+		Short: "Make a task or reminder repeat",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			note := cli.resolveNote(args[0])
+			recurrence, err := entities.ParseRecurrence(args[1], note.ScheduledDate())
+			if err != nil {
+				fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+				os.Exit(1)
+			}
+			cli.setRecurrence(note, recurrence)
+		},
+	}
+
+	clearCmd := &cobra.Command{
+		Use:   "clear <id>",
+		Short: "Stop a task or reminder from repeating",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.setRecurrence(cli.resolveNote(args[0]), nil)
+		},
+	}
+
+	recurringCmd.AddCommand(setCmd, clearCmd)
+	return recurringCmd
+}
+
+// listRecurring lists the open items that repeat with their rules
+func (cli *CLI) listRecurring(cmd *cobra.Command, args []string) {
+	notes, err := cli.noteService.GetRecurring()
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error retrieving recurring items: %v", err)))
+		os.Exit(1)
+	}
+	notes = filterByProject(notes, projectFlag(cmd))
+
+	if len(notes) == 0 {
+		fmt.Println(infoStyle.Render("Nothing repeats yet. Use jtx recurring set <id> <rule>."))
+		return
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Recurring (%d)", len(notes))))
+	fmt.Println("")
+
+	for i, note := range notes {
+		fmt.Printf("%d. %s %-8s %-26s next %s  %s\n",
+			i+1,
+			note.Alias(),
+			note.Type,
+			note.Metadata.Recurrence.Describe(),
+			note.ScheduledDate().Format("Mon 2006-01-02"),
+			note.Content,
+		)
+	}
+}
+
+// setRecurrence sets or clears the repeat rule of a task or reminder
+func (cli *CLI) setRecurrence(note *entities.Note, recurrence *entities.Recurrence) {
+	if note.Type != entities.NoteTypeTask && note.Type != entities.NoteTypeReminder {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: only tasks and reminders can repeat, %s is a %s", note.Alias(), note.Type)))
+		os.Exit(1)
+	}
+
+	note.Metadata.Recurrence = recurrence
+	if err := cli.noteService.SaveNote(note); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error updating note: %v", err)))
+		os.Exit(1)
+	}
+
+	if recurrence == nil {
+		fmt.Println(successStyle.Render(fmt.Sprintf("%s no longer repeats.", note.Alias())))
+		return
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("%s repeats %s.", note.Alias(), recurrence.Describe())))
+}
+
+// printNextOccurrence reports the occurrence created by completing a
+// recurring item, if there is one
+func printNextOccurrence(next *entities.Note) {
+	if next == nil {
+		return
+	}
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Next occurrence on %s (%s).", next.ScheduledDate().Format("Mon 2006-01-02"), next.Alias())))
+}
+
+// newRepeatInput creates the repeat field of the task and reminder forms
+func newRepeatInput(recurrence *entities.Recurrence) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "daily, weekly:mon,thu, after:3d (optional)"
+	input.CharLimit = 40
+	input.Width = 45
+	input.Validate = repeatValidator
+	if recurrence != nil {
+		input.SetValue(recurrence.String())
+	}
+	return input
+}
+
+// parseRepeat parses the repeat field of a form; weekly and monthly rules
+// without days repeat on the weekday or day of the month of from. An empty
+// field means the item does not repeat.
+func parseRepeat(s string, from time.Time) (*entities.Recurrence, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	return entities.ParseRecurrence(s, from)
+}
+
+// repeatValidator validates repeat input
+func repeatValidator(s string) error {
+	_, err := parseRepeat(s, time.Now())
+	return err
+}
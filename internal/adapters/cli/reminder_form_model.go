@@ -14,6 +14,9 @@ import (
 const (
 	reminderContent = iota
 	reminderTime
+	reminderRepeat
+	reminderProject
+	reminderTags
 )
 
 const (
@@ -38,7 +41,7 @@ type ReminderFormModel struct {
 
 // NewReminderFormModel creates a new reminder form model
 func NewReminderFormModel() *ReminderFormModel {
-	var inputs []textinput.Model = make([]textinput.Model, 2)
+	var inputs []textinput.Model = make([]textinput.Model, 5)
 
 	// Reminder content input
 	inputs[reminderContent] = textinput.New()
@@ -54,6 +57,13 @@ func NewReminderFormModel() *ReminderFormModel {
 	inputs[reminderTime].Width = 10
 	inputs[reminderTime].Validate = timeValidator
 
+	// Repeat input
+	inputs[reminderRepeat] = newRepeatInput(nil)
+
+	// Project and tags inputs
+	inputs[reminderProject] = newProjectInput("")
+	inputs[reminderTags] = newTagsInput(nil)
+
 	return &ReminderFormModel{
 		inputs:   inputs,
 		focused:  0,
@@ -65,7 +75,7 @@ func NewReminderFormModel() *ReminderFormModel {
 
 // NewReminderFormModelWithData creates a new reminder form model with existing data
 func NewReminderFormModelWithData(reminder *entities.Note) *ReminderFormModel {
-	var inputs []textinput.Model = make([]textinput.Model, 2)
+	var inputs []textinput.Model = make([]textinput.Model, 5)
 
 	// Reminder content input
 	inputs[reminderContent] = textinput.New()
@@ -85,6 +95,13 @@ func NewReminderFormModelWithData(reminder *entities.Note) *ReminderFormModel {
 		inputs[reminderTime].SetValue(reminder.Metadata.ReminderTime) // Set existing time
 	}
 
+	// Repeat input
+	inputs[reminderRepeat] = newRepeatInput(reminder.Metadata.Recurrence)
+
+	// Project and tags inputs
+	inputs[reminderProject] = newProjectInput(reminder.Metadata.Category)
+	inputs[reminderTags] = newTagsInput(reminder.Metadata.Tags)
+
 	return &ReminderFormModel{
 		inputs:           inputs,
 		focused:          0,
@@ -159,8 +176,11 @@ func (m ReminderFormModel) View() string {
  %s
  %s
 
- %s
- %s
+ %s  %s
+ %s  %s
+
+ %s  %s
+ %s  %s
 
  %s
 `,
@@ -168,7 +188,13 @@ func (m ReminderFormModel) View() string {
 		reminderLabelStyle.Width(50).Render("Reminder Description"),
 		m.inputs[reminderContent].View(),
 		reminderLabelStyle.Width(10).Render("Time"),
+		reminderLabelStyle.Width(45).Render("Repeat"),
 		m.inputs[reminderTime].View(),
+		m.inputs[reminderRepeat].View(),
+		reminderLabelStyle.Width(30).Render("Project"),
+		reminderLabelStyle.Width(50).Render("Tags"),
+		m.inputs[reminderProject].View(),
+		m.inputs[reminderTags].View(),
 		reminderContinueStyle.Render("Press Ctrl+S to create reminder, Tab to navigate, Ctrl+C to cancel"),
 	)
 
@@ -193,6 +219,20 @@ func (m *ReminderFormModel) createReminder() {
 		timeStr = "09:00"
 	}
 
+	project := entities.NormalizeProject(m.inputs[reminderProject].Value())
+	tags := entities.ParseTags(m.inputs[reminderTags].Value())
+
+	// Weekly and monthly rules without days repeat on the reminder's date
+	scheduled := time.Now()
+	if m.existingReminder != nil {
+		scheduled = m.existingReminder.ScheduledDate()
+	}
+	recurrence, err := parseRepeat(m.inputs[reminderRepeat].Value(), scheduled)
+	if err != nil {
+		m.err = err
+		return
+	}
+
 	// Status is always pending for new reminders
 	status := entities.StatusToDo
 
@@ -200,12 +240,18 @@ func (m *ReminderFormModel) createReminder() {
 		// Update existing reminder
 		m.existingReminder.Content = content
 		m.existingReminder.Metadata.ReminderTime = timeStr
+		m.existingReminder.Metadata.Category = project
+		m.existingReminder.Metadata.Tags = tags
+		m.existingReminder.Metadata.Recurrence = recurrence
 		// Don't change the status when updating
 		m.existingReminder.UpdatedAt = time.Now()
 		m.reminder = m.existingReminder
 	} else {
 		// Create new reminder - always pending
 		reminder := entities.NewReminder(content, timeStr, status)
+		reminder.Metadata.Category = project
+		reminder.Metadata.Tags = tags
+		reminder.Metadata.Recurrence = recurrence
 		m.reminder = reminder
 	}
 
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+// newSearchCommand creates the search command
+func (cli *CLI) newSearchCommand() *cobra.Command {
+	searchCmd := &cobra.Command{
+		Use:   "search <terms>",
+		Short: "Search all notes",
+		Long: "Search the content, assignee, email, phone, address, tags and category of every note.\n\n" +
+			"All words must match. Use \"quoted phrases\" to match words in order and\n" +
+			"a trailing * to match a prefix, e.g. jtx search '\"weekly sync\" bob*'.",
+		Args: cobra.ArbitraryArgs,
+		Run:  cli.searchNotes,
+	}
+	searchCmd.Flags().Int("limit", 50, "Maximum number of results (0 for all)")
+	searchCmd.Flags().Bool("reindex", false, "Rebuild the search index before searching")
+	searchCmd.Flags().String("project", "", "Only search notes in this project and its subprojects")
+
+	return searchCmd
+}
+
+// searchNotes runs a full-text search over all notes
+func (cli *CLI) searchNotes(cmd *cobra.Command, args []string) {
+	limit, _ := cmd.Flags().GetInt("limit")
+	reindex, _ := cmd.Flags().GetBool("reindex")
+	query := strings.Join(args, " ")
+
+	if query == "" && !reindex {
+		fmt.Println(errorStyle.Render("Error: search terms cannot be empty"))
+		os.Exit(1)
+	}
+
+	// Build the index on first use, or when asked to
+	if reindex || !cli.searchIndex.Exists() {
+		notes, err := cli.store.GetAllNotes()
+		if err != nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Error reading notes: %v", err)))
+			os.Exit(1)
+		}
+
+		if err := cli.searchIndex.Rebuild(notes); err != nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Error building search index: %v", err)))
+			os.Exit(1)
+		}
+
+		if reindex {
+			fmt.Println(successStyle.Render(fmt.Sprintf("Indexed %d notes.", len(notes))))
+		}
+	}
+
+	if query == "" {
+		return
+	}
+
+	// The project is filtered after the search, so the limit is applied then
+	project := projectFlag(cmd)
+	searchLimit := limit
+	if project != "" {
+		searchLimit = 0
+	}
+
+	hits, err := cli.searchIndex.Search(query, searchLimit)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error searching notes: %v", err)))
+		os.Exit(1)
+	}
+
+	var notes []*entities.Note
+	for _, hit := range hits {
+		note, err := cli.noteService.GetNoteByID(hit.ID)
+		if err != nil {
+			// The index may briefly mention notes removed by another process
+			if errors.Is(err, ports.ErrNoteNotFound) {
+				continue
+			}
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Error retrieving note: %v", err)))
+			os.Exit(1)
+		}
+		notes = append(notes, note)
+	}
+	notes = filterByProject(notes, project)
+	if limit > 0 && len(notes) > limit {
+		notes = notes[:limit]
+	}
+
+	if len(notes) == 0 {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("No notes match %q.", query)))
+		return
+	}
+
+	title := fmt.Sprintf("Search: %s (%d results)", query, len(notes))
+
+	// Check if we're in a TTY environment
+	if cli.isTTY() {
+		model := NewListModel(notes, title, cli)
+		program := tea.NewProgram(model, tea.WithAltScreen())
+
+		if _, err := program.Run(); err != nil {
+			// Fall back to text mode if interactive fails
+			cli.showTextList(notes, title)
+		}
+	} else {
+		cli.showTextList(notes, title)
+	}
+}
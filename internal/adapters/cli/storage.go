@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"jotterxpress/internal/adapters/config"
+	"jotterxpress/internal/adapters/repository"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// newStorageCommand creates the storage command and its subcommands
+func (cli *CLI) newStorageCommand() *cobra.Command {
+	storageCmd := &cobra.Command{
+		Use:   "storage",
+		Short: "Manage the storage backend",
+	}
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Copy all notes to another storage backend and switch to it",
+		Long: "Copy every note and the trash to another storage backend, keeping IDs " +
+			"and timestamps, then select it for the notebook in the config file. The previous data is left in place.",
+		Args: cobra.NoArgs,
+		Run:  cli.migrateStorage,
+	}
+	migrateCmd.Flags().String("to", "", "Target storage backend: files or sqlite")
+	migrateCmd.Flags().Bool("merge", false, "Merge into a target that already contains notes")
+	migrateCmd.MarkFlagRequired("to")
+
+	layoutCmd := &cobra.Command{
+		Use:   "layout [flat|nested]",
+		Short: "Show or change how day files are arranged in the notes directory",
+		Long: "Without an argument, show the layout of the notes directory. The flat layout keeps " +
+			"every day file in the notes directory; the nested layout keeps them in YYYY/MM " +
+			"subdirectories, which suits archives of many years. Converting moves every day file " +
+			"and can be run again to finish an interrupted conversion. Only files storage has a layout.",
+		Args:      cobra.MaximumNArgs(1),
+		ValidArgs: []string{repository.LayoutFlat, repository.LayoutNested},
+		Run:       cli.convertLayout,
+	}
+
+	storageCmd.AddCommand(migrateCmd)
+	storageCmd.AddCommand(layoutCmd)
+	return storageCmd
+}
+
+// layoutConverter is implemented by repositories that can arrange their day
+// files in more than one layout
+type layoutConverter interface {
+	Layout() string
+	ConvertLayout(layout string) (*repository.LayoutReport, error)
+}
+
+// convertLayout shows the layout of the notes directory or converts it
+func (cli *CLI) convertLayout(cmd *cobra.Command, args []string) {
+	converter, ok := cli.repository.(layoutConverter)
+	if !ok {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %s storage has no file layout; it only applies to files storage",
+			cli.config.NotebookStorage(cli.notebook))))
+		os.Exit(1)
+	}
+
+	if len(args) == 0 {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Layout: %s", converter.Layout())))
+		return
+	}
+
+	report, err := converter.ConvertLayout(args[0])
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error converting layout: %v", err)))
+		os.Exit(1)
+	}
+
+	if report.From == report.To && report.Moved == 0 && report.Merged == 0 {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Layout is already %s.", report.To)))
+		return
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("Converted layout from %s to %s: %d day files moved, %d merged.",
+		report.From, report.To, report.Moved, report.Merged)))
+
+	if cli.gitRepo.Enabled() {
+		if err := cli.gitRepo.Commit("convert layout to " + report.To); err != nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Warning: failed to commit change: %v", err)))
+		}
+	}
+}
+
+// migrateStorage copies all notes to another backend and switches to it
+func (cli *CLI) migrateStorage(cmd *cobra.Command, args []string) {
+	target, _ := cmd.Flags().GetString("to")
+	merge, _ := cmd.Flags().GetBool("merge")
+
+	if target != config.StorageFiles && target != config.StorageSQLite {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: unknown storage %q", target)))
+		fmt.Println(infoStyle.Render("Usage: jtx storage migrate --to sqlite (or --to files)"))
+		os.Exit(1)
+	}
+
+	if target == cli.config.NotebookStorage(cli.notebook) {
+		fmt.Println(infoStyle.Render(fmt.Sprintf("Storage is already %s.", target)))
+		return
+	}
+
+	targetRepo, err := openRepository(target, cli.notesDir)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error opening %s storage: %v", target, err)))
+		os.Exit(1)
+	}
+	if closer, ok := targetRepo.(io.Closer); ok {
+		defer closer.Close()
+	}
+	if err := upgradeStorage(targetRepo); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error upgrading %s storage: %v", target, err)))
+		os.Exit(1)
+	}
+
+	existing, err := targetRepo.GetAllNotes()
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error reading %s storage: %v", target, err)))
+		os.Exit(1)
+	}
+	if len(existing) > 0 && !merge {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %s storage already contains %d notes", target, len(existing))))
+		fmt.Println(infoStyle.Render("Use --merge to combine them with the migrated notes"))
+		os.Exit(1)
+	}
+
+	stats, err := repository.Copy(targetRepo, cli.repository)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error migrating notes: %v", err)))
+		os.Exit(1)
+	}
+
+	if err := cli.config.SetNotebookStorage(cli.notebook, target); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+	if err := cli.config.Save(); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error saving config: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Copied %d notes and %d trashed notes to %s storage.", stats.Notes, stats.Trashed, target)))
+	fmt.Println(infoStyle.Render(fmt.Sprintf("Storage is now %s. The previous data was left in %s.", target, cli.notesDir)))
+}
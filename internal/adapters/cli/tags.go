@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"fmt"
+	"jotterxpress/internal/adapters/search"
+	"jotterxpress/internal/application/services"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// tagCloudWidth is the width tag clouds are wrapped at
+const tagCloudWidth = 70
+
+// newTagsCommand creates the tags command
+func (cli *CLI) newTagsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tags",
+		Short: "Show every tag with the number of notes that have it",
+		Args:  cobra.NoArgs,
+		Run:   cli.showTags,
+	}
+}
+
+// newTagCommand creates the tag command and its subcommands
+func (cli *CLI) newTagCommand() *cobra.Command {
+	tagCmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Rename and merge tags across all notes",
+	}
+
+	renameCmd := &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename a tag on every note, including #hashtags in the content",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			cli.mergeTags(args[:1], args[1], fmt.Sprintf("rename tag %s to %s", entities.NormalizeTag(args[0]), entities.NormalizeTag(args[1])))
+		},
+	}
+
+	mergeCmd := &cobra.Command{
+		Use:   "merge <tag>... --into <tag>",
+		Short: "Replace several tags with one on every note",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			into, _ := cmd.Flags().GetString("into")
+			cli.mergeTags(args, into, fmt.Sprintf("merge tags %s into %s", strings.Join(args, ", "), entities.NormalizeTag(into)))
+		},
+	}
+	mergeCmd.Flags().String("into", "", "Tag that replaces the others")
+	mergeCmd.MarkFlagRequired("into")
+
+	tagCmd.AddCommand(renameCmd, mergeCmd)
+	return tagCmd
+}
+
+// tagService returns the tag service of the current notebook. Notes are
+// written as they are retagged, so the search index is updated here too.
+func (cli *CLI) tagService() ports.TagService {
+	return services.NewTagService(search.NewIndexedRepository(cli.store, cli.searchIndex))
+}
+
+// showTags prints a tag cloud: tags with their counts, the most used first
+// and in bold
+func (cli *CLI) showTags(cmd *cobra.Command, args []string) {
+	tags, err := cli.tagService().GetTags()
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error retrieving tags: %v", err)))
+		os.Exit(1)
+	}
+
+	if len(tags) == 0 {
+		fmt.Println(infoStyle.Render("No tags yet. Add #hashtags to a note or use --tag."))
+		return
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Tags (%d)", len(tags))))
+	fmt.Println("")
+	for _, line := range tagCloudLines(tags, tagCloudWidth) {
+		fmt.Println(line)
+	}
+}
+
+// tagCloudLines renders tags as "#tag (count)" words wrapped at width. The
+// quarter of tags used most is bold, the least used quarter is dimmed.
+func tagCloudLines(tags []ports.TagCount, width int) []string {
+	top := tags[0].Count
+	bold := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#8B5CF6"))
+	plain := lipgloss.NewStyle().Foreground(lipgloss.Color("#FAFAFA"))
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("#767676"))
+
+	var lines []string
+	var line strings.Builder
+	length := 0
+	for _, tag := range tags {
+		word := fmt.Sprintf("#%s (%d)", tag.Tag, tag.Count)
+
+		style := plain
+		switch {
+		case tag.Count*4 >= top*3:
+			style = bold
+		case tag.Count*4 <= top:
+			style = dim
+		}
+
+		if length > 0 && length+2+len(word) > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			length = 0
+		}
+		if length > 0 {
+			line.WriteString("  ")
+			length += 2
+		}
+		line.WriteString(style.Render(word))
+		length += len(word)
+	}
+
+	return append(lines, line.String())
+}
+
+// mergeTags replaces tags with into on every note and commits the change
+func (cli *CLI) mergeTags(from []string, into, message string) {
+	changed, err := cli.tagService().MergeTags(from, into)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		os.Exit(1)
+	}
+
+	if changed == 0 {
+		fmt.Println(infoStyle.Render("No notes have these tags."))
+		return
+	}
+	fmt.Println(successStyle.Render(fmt.Sprintf("Retagged %d notes.", changed)))
+
+	if cli.gitRepo.Enabled() {
+		if err := cli.gitRepo.Commit(message); err != nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Warning: failed to commit change: %v", err)))
+		}
+	}
+}
+
+// tagsFlag returns the normalized tags given with --tag
+func tagsFlag(cmd *cobra.Command) []string {
+	values, _ := cmd.Flags().GetStringSlice("tag")
+	return entities.ParseTags(strings.Join(values, ","))
+}
+
+// hashtags formats tags as "#infra #deploy"
+func hashtags(tags []string) string {
+	return "#" + strings.Join(tags, " #")
+}
+
+// filterByTags returns the notes that have every one of tags
+func filterByTags(notes []*entities.Note, tags []string) []*entities.Note {
+	if len(tags) == 0 {
+		return notes
+	}
+
+	var filtered []*entities.Note
+	for _, note := range notes {
+		matches := true
+		for _, tag := range tags {
+			if !note.HasTag(tag) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			filtered = append(filtered, note)
+		}
+	}
+
+	return filtered
+}
+
+// newTagsInput creates the tags field of the forms, filled with tags
+func newTagsInput(tags []string) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "infra, deploy (optional)"
+	input.CharLimit = 200
+	input.Width = 50
+	input.SetValue(strings.Join(tags, ", "))
+	return input
+}
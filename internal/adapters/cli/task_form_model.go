@@ -19,6 +19,10 @@ const (
 	taskContent = iota
 	taskPriority
 	taskAssignee
+	taskDue
+	taskRepeat
+	taskProject
+	taskTags
 )
 
 const (
@@ -45,7 +49,7 @@ type TaskFormModel struct {
 
 // NewTaskFormModel creates a new task form model
 func NewTaskFormModel() *TaskFormModel {
-	var inputs []textinput.Model = make([]textinput.Model, 3)
+	var inputs []textinput.Model = make([]textinput.Model, 7)
 
 	// Task content input
 	inputs[taskContent] = textinput.New()
@@ -67,6 +71,16 @@ func NewTaskFormModel() *TaskFormModel {
 	inputs[taskAssignee].CharLimit = 50
 	inputs[taskAssignee].Width = 30
 
+	// Due date input
+	inputs[taskDue] = newDueDateInput(nil)
+
+	// Repeat input
+	inputs[taskRepeat] = newRepeatInput(nil)
+
+	// Project and tags inputs
+	inputs[taskProject] = newProjectInput("")
+	inputs[taskTags] = newTagsInput(nil)
+
 	return &TaskFormModel{
 		inputs:  inputs,
 		focused: 0,
@@ -78,7 +92,7 @@ func NewTaskFormModel() *TaskFormModel {
 
 // NewTaskFormModelWithData creates a new task form model with existing data
 func NewTaskFormModelWithData(task *entities.Note) *TaskFormModel {
-	var inputs []textinput.Model = make([]textinput.Model, 3)
+	var inputs []textinput.Model = make([]textinput.Model, 7)
 
 	// Task content input
 	inputs[taskContent] = textinput.New()
@@ -103,6 +117,16 @@ func NewTaskFormModelWithData(task *entities.Note) *TaskFormModel {
 	inputs[taskAssignee].Width = 30
 	inputs[taskAssignee].SetValue(task.Metadata.Assignee) // Set existing assignee
 
+	// Due date input
+	inputs[taskDue] = newDueDateInput(task.Metadata.DueDate)
+
+	// Repeat input
+	inputs[taskRepeat] = newRepeatInput(task.Metadata.Recurrence)
+
+	// Project and tags inputs
+	inputs[taskProject] = newProjectInput(task.Metadata.Category)
+	inputs[taskTags] = newTagsInput(task.Metadata.Tags)
+
 	return &TaskFormModel{
 		inputs:       inputs,
 		focused:      0,
@@ -170,6 +194,12 @@ func (m TaskFormModel) View() string {
  %s
  %s
 
+ %s  %s  %s
+ %s  %s  %s
+
+ %s
+ %s
+
  %s  %s
  %s  %s
 
@@ -180,8 +210,16 @@ func (m TaskFormModel) View() string {
 		m.inputs[taskContent].View(),
 		labelStyle.Width(15).Render("Priority"),
 		labelStyle.Width(30).Render("Assignee"),
+		labelStyle.Width(20).Render("Due"),
 		m.inputs[taskPriority].View(),
 		m.inputs[taskAssignee].View(),
+		m.inputs[taskDue].View(),
+		labelStyle.Width(45).Render("Repeat"),
+		m.inputs[taskRepeat].View(),
+		labelStyle.Width(30).Render("Project"),
+		labelStyle.Width(50).Render("Tags"),
+		m.inputs[taskProject].View(),
+		m.inputs[taskTags].View(),
 		continueStyle.Render("Press Enter to create task, Tab to navigate, Ctrl+C to cancel"),
 	)
 
@@ -222,12 +260,42 @@ func (m *TaskFormModel) createTask() {
 	if assignee != "" {
 		task.Metadata.Assignee = assignee
 	}
+	due, err := parseDueDate(m.inputs[taskDue].Value())
+	if err != nil {
+		m.err = err
+		return
+	}
+	task.Metadata.DueDate = due
+
+	// Weekly and monthly rules without days repeat on the scheduled date
+	scheduled := time.Now()
+	if m.existingTask != nil {
+		scheduled = m.existingTask.ScheduledDate()
+	}
+	if due != nil {
+		scheduled = *due
+	}
+	recurrence, err := parseRepeat(m.inputs[taskRepeat].Value(), scheduled)
+	if err != nil {
+		m.err = err
+		return
+	}
+	task.Metadata.Recurrence = recurrence
+
+	project := entities.NormalizeProject(m.inputs[taskProject].Value())
+	tags := entities.ParseTags(m.inputs[taskTags].Value())
+	task.Metadata.Category = project
+	task.Metadata.Tags = tags
 
 	if m.existingTask != nil {
 		// Update existing task
 		m.existingTask.Content = content
 		m.existingTask.Metadata.Priority = priority
 		m.existingTask.Metadata.Assignee = assignee
+		m.existingTask.Metadata.DueDate = due
+		m.existingTask.Metadata.Recurrence = recurrence
+		m.existingTask.Metadata.Category = project
+		m.existingTask.Metadata.Tags = tags
 		m.existingTask.UpdatedAt = time.Now()
 		m.task = m.existingTask
 	} else {
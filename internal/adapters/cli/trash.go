@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newTrashCommand creates the trash command and its subcommands
+func (cli *CLI) newTrashCommand() *cobra.Command {
+	trashCmd := &cobra.Command{
+		Use:   "trash",
+		Short: "Manage deleted notes",
+		Long:  "List, restore and permanently remove notes that were moved to the trash.",
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List notes in the trash",
+		Args:  cobra.NoArgs,
+		Run:   cli.listTrash,
+	}
+	listCmd.Flags().String("project", "", "Only list deleted notes of this project and its subprojects")
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore <id>",
+		Short: "Restore a note from the trash",
+		Args:  cobra.ExactArgs(1),
+		Run:   cli.restoreNote,
+	}
+
+	emptyCmd := &cobra.Command{
+		Use:   "empty",
+		Short: "Permanently remove notes from the trash",
+		Long: "Permanently remove notes from the trash. Without --older-than every note is removed, " +
+			"which needs --all or a confirmation.",
+		Args: cobra.NoArgs,
+		Run:  cli.emptyTrash,
+	}
+	emptyCmd.Flags().String("older-than", "", "Only remove notes deleted before this age (e.g. 30d, 2w, 12h)")
+	emptyCmd.Flags().Bool("all", false, "Remove every note in the trash without asking")
+
+	trashCmd.AddCommand(listCmd, restoreCmd, emptyCmd)
+	return trashCmd
+}
+
+// listTrash lists all notes in the trash
+func (cli *CLI) listTrash(cmd *cobra.Command, args []string) {
+	trash, err := cli.noteService.GetTrash()
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error retrieving trash: %v", err)))
+		os.Exit(1)
+	}
+
+	if project := projectFlag(cmd); project != "" {
+		var inProject []*entities.TrashedNote
+		for _, entry := range trash {
+			if entry.Note.InProject(project) {
+				inProject = append(inProject, entry)
+			}
+		}
+		trash = inProject
+	}
+
+	if len(trash) == 0 {
+		fmt.Println(infoStyle.Render("The trash is empty."))
+		return
+	}
+
+	fmt.Println(titleStyle.Render(fmt.Sprintf("Trash (%d notes)", len(trash))))
+	fmt.Println("")
+
+	for i, entry := range trash {
+		fmt.Printf("%d. %s %s [deleted %s] %s\n",
+			i+1,
+			entry.Note.Alias(),
+			entry.Note.ID,
+			entry.DeletedAt.Format("2006-01-02 15:04"),
+			entry.Note.String(),
+		)
+	}
+}
+
+// restoreNote restores a note from the trash
+func (cli *CLI) restoreNote(cmd *cobra.Command, args []string) {
+	note, err := cli.noteService.RestoreNote(args[0])
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error restoring note: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Note restored to %s!", note.Date)))
+}
+
+// emptyTrash permanently removes notes from the trash
+func (cli *CLI) emptyTrash(cmd *cobra.Command, args []string) {
+	olderThanStr, _ := cmd.Flags().GetString("older-than")
+	all, _ := cmd.Flags().GetBool("all")
+
+	if olderThanStr != "" && all {
+		fmt.Println(errorStyle.Render("Error: --all and --older-than cannot be combined"))
+		os.Exit(1)
+	}
+	if olderThanStr == "" && !all && !cli.confirmEmptyTrash() {
+		return
+	}
+
+	var olderThan time.Duration
+	if olderThanStr != "" {
+		var err error
+		olderThan, err = parseAge(olderThanStr)
+		if err != nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+			fmt.Println(infoStyle.Render("Usage: jtx trash empty --older-than 30d"))
+			os.Exit(1)
+		}
+	}
+
+	removed, err := cli.noteService.EmptyTrash(olderThan)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error emptying trash: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Permanently removed %d notes from the trash.", removed)))
+}
+
+// confirmEmptyTrash asks before removing every note from the trash;
+// without a terminal to ask on it refuses and points to --all
+func (cli *CLI) confirmEmptyTrash() bool {
+	trash, err := cli.noteService.GetTrash()
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error retrieving trash: %v", err)))
+		os.Exit(1)
+	}
+	if len(trash) == 0 {
+		fmt.Println(infoStyle.Render("The trash is empty."))
+		return false
+	}
+
+	if !cli.isTTY() {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: this would permanently remove all %d notes in the trash", len(trash))))
+		fmt.Println(infoStyle.Render("Usage: jtx trash empty --all, or jtx trash empty --older-than 30d"))
+		os.Exit(1)
+	}
+
+	fmt.Printf("Permanently remove all %d notes in the trash? [y/N] ", len(trash))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+		fmt.Println(infoStyle.Render("Nothing was removed."))
+		return false
+	}
+	return true
+}
+
+// deleteNote moves a note to the trash
+func (cli *CLI) deleteNote(note *entities.Note) error {
+	return cli.noteService.DeleteNote(note.ID)
+}
+
+// parseAge parses an age such as "30d", "2w" or any Go duration like "12h"
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("age cannot be empty")
+	}
+
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+
+	if unit, ok := units[s[len(s)-1:]]; ok {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q", s)
+		}
+		return time.Duration(n) * unit, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q", s)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"fmt"
+	"jotterxpress/internal/adapters/config"
+	"jotterxpress/internal/domain/entities"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// newVersioningCommand creates the versioning command and its subcommands
+func (cli *CLI) newVersioningCommand() *cobra.Command {
+	versioningCmd := &cobra.Command{
+		Use:   "versioning",
+		Short: "Manage git versioning of the notes directory",
+	}
+
+	enableCmd := &cobra.Command{
+		Use:   "enable",
+		Short: "Commit every change to a git repository in the notes directory",
+		Long: "Turn the notes directory of the current notebook into a git repository. " +
+			"From then on every save, completion and deletion creates a commit. Requires files storage.",
+		Args: cobra.NoArgs,
+		Run:  cli.enableVersioning,
+	}
+	enableCmd.Flags().String("remote", "", "URL of the remote used by 'jtx sync'")
+
+	versioningCmd.AddCommand(enableCmd)
+	return versioningCmd
+}
+
+// newLogCommand creates the log command
+func (cli *CLI) newLogCommand() *cobra.Command {
+	logCmd := &cobra.Command{
+		Use:   "log [id]",
+		Short: "Show the version history of the notes",
+		Long:  "Show the commits made for changes to notes, newest first. Pass a note ID or ID prefix to show only its history.",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   cli.showLog,
+	}
+	logCmd.Flags().IntP("limit", "n", 20, "Maximum number of entries (0 for all)")
+
+	return logCmd
+}
+
+// newSyncCommand creates the sync command
+func (cli *CLI) newSyncCommand() *cobra.Command {
+	syncCmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Pull and push notes to the git remote, or sync them with a shared folder",
+		Long: "Without flags, pull and push the notes directory to its git remote. With --with, " +
+			"sync notes two ways with a folder shared between machines, such as a Syncthing or " +
+			"Dropbox folder: notes are matched by ID, the version updated last wins, deletions " +
+			"are carried over and a note changed on both sides is kept twice.",
+		Args: cobra.NoArgs,
+		Run:  cli.syncNotes,
+	}
+	syncCmd.Flags().String("remote", "", "Set the remote URL before syncing")
+	syncCmd.Flags().String("with", "", "Sync with a shared folder instead of the git remote")
+	syncCmd.Flags().Bool("interactive", false, "With --with, ask how to resolve each conflict instead of keeping both versions")
+
+	return syncCmd
+}
+
+// enableVersioning turns the notes directory into a git repository
+func (cli *CLI) enableVersioning(cmd *cobra.Command, args []string) {
+	remote, _ := cmd.Flags().GetString("remote")
+
+	if storage := cli.config.NotebookStorage(cli.notebook); storage != config.StorageFiles {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: versioning requires files storage, this notebook uses %s", storage)))
+		fmt.Println(infoStyle.Render("Switch with: jtx storage migrate --to files"))
+		os.Exit(1)
+	}
+
+	if err := cli.gitRepo.Init(); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error enabling versioning: %v", err)))
+		os.Exit(1)
+	}
+
+	if remote != "" {
+		if err := cli.gitRepo.SetRemote(remote); err != nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Error setting remote: %v", err)))
+			os.Exit(1)
+		}
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Versioning enabled in %s", cli.gitRepo.Dir())))
+	fmt.Println(infoStyle.Render("Every change is now committed. Show the history with: jtx log"))
+}
+
+// showLog prints the version history
+func (cli *CLI) showLog(cmd *cobra.Command, args []string) {
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	if !cli.gitRepo.Enabled() {
+		fmt.Println(errorStyle.Render("Error: versioning is not enabled for this notebook"))
+		fmt.Println(infoStyle.Render("Enable it with: jtx versioning enable"))
+		os.Exit(1)
+	}
+
+	grep := ""
+	if len(args) > 0 {
+		grep = args[0]
+
+		// Commit messages hold full IDs, never aliases
+		if entities.IsIDAlias(grep) {
+			if note, err := cli.noteService.GetNoteByID(grep); err == nil {
+				grep = note.ID
+			}
+		}
+	}
+
+	commits, err := cli.gitRepo.Log(limit, grep)
+	if err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error reading history: %v", err)))
+		os.Exit(1)
+	}
+
+	if len(commits) == 0 {
+		fmt.Println(infoStyle.Render("No history yet."))
+		return
+	}
+
+	fmt.Println(titleStyle.Render("Note History"))
+	fmt.Println("")
+
+	for _, commit := range commits {
+		fmt.Printf("%s %s %s\n",
+			infoStyle.Render(commit.Hash[:7]),
+			commit.Date.Format("2006-01-02 15:04"),
+			commit.Message,
+		)
+	}
+}
+
+// syncNotes pulls and pushes the notes directory to its remote
+func (cli *CLI) syncNotes(cmd *cobra.Command, args []string) {
+	remote, _ := cmd.Flags().GetString("remote")
+	with, _ := cmd.Flags().GetString("with")
+	interactive, _ := cmd.Flags().GetBool("interactive")
+
+	if with != "" {
+		if remote != "" {
+			fmt.Println(errorStyle.Render("Error: --with and --remote cannot be used together"))
+			os.Exit(1)
+		}
+		cli.syncWithFolder(with, interactive)
+		return
+	}
+	if interactive {
+		fmt.Println(errorStyle.Render("Error: --interactive requires --with"))
+		os.Exit(1)
+	}
+
+	if !cli.gitRepo.Enabled() {
+		fmt.Println(errorStyle.Render("Error: versioning is not enabled for this notebook"))
+		fmt.Println(infoStyle.Render("Enable it with: jtx versioning enable --remote URL"))
+		os.Exit(1)
+	}
+
+	if remote != "" {
+		if err := cli.gitRepo.SetRemote(remote); err != nil {
+			fmt.Println(errorStyle.Render(fmt.Sprintf("Error setting remote: %v", err)))
+			os.Exit(1)
+		}
+	}
+
+	if cli.gitRepo.Remote() == "" {
+		fmt.Println(errorStyle.Render("Error: no remote configured"))
+		fmt.Println(infoStyle.Render("Set one with: jtx sync --remote URL"))
+		os.Exit(1)
+	}
+
+	if err := cli.gitRepo.Sync(); err != nil {
+		fmt.Println(errorStyle.Render(fmt.Sprintf("Error syncing notes: %v", err)))
+		os.Exit(1)
+	}
+
+	fmt.Println(successStyle.Render(fmt.Sprintf("Notes synced with %s", cli.gitRepo.Remote())))
+}
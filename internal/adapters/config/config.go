@@ -0,0 +1,291 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"jotterxpress/internal/adapters/fsutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Storage backends that can be selected with the "storage" setting
+const (
+	StorageFiles  = "files"
+	StorageSQLite = "sqlite"
+)
+
+// DefaultNotebook is the notebook used when no other one is selected. Its
+// notes live in the "notes" directory, where they were before notebooks existed.
+const DefaultNotebook = "default"
+
+// HomeEnv overrides the application directory (~/.jotterxpress)
+const HomeEnv = "JOTTERXPRESS_HOME"
+
+// notebookName restricts notebook names to something safe to use as a directory
+var notebookName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// Notebook holds the settings of one named notebook
+type Notebook struct {
+	// Path is the notebook directory, absolute or relative to the application
+	// directory. Empty means notebooks/<name>.
+	Path string `toml:"path,omitempty"`
+
+	// Storage selects the repository backend of the notebook
+	Storage string `toml:"storage,omitempty"`
+}
+
+// Config holds the user settings stored in ~/.jotterxpress/config (TOML)
+type Config struct {
+	// Storage selects the repository backend of the default notebook:
+	// "files" (default) or "sqlite"
+	Storage string `toml:"storage"`
+
+	// Notebook is the notebook used when --notebook is not given
+	Notebook string `toml:"notebook,omitempty"`
+
+	// KeyCache is how long the key of an encrypted notebook is remembered
+	// after its last use, e.g. "15m". Empty asks for the passphrase every time.
+	KeyCache string `toml:"key_cache,omitempty"`
+
+	// Notebooks holds every notebook other than the default one
+	Notebooks map[string]*Notebook `toml:"notebooks,omitempty"`
+
+	path string
+}
+
+// HomeDir returns the application directory: $JOTTERXPRESS_HOME if set,
+// otherwise ~/.jotterxpress
+func HomeDir() string {
+	if home := os.Getenv(HomeEnv); home != "" {
+		return home
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+
+	return filepath.Join(homeDir, ".jotterxpress")
+}
+
+// Load reads the config file at path; a missing file yields the defaults
+func Load(path string) (*Config, error) {
+	cfg := &Config{
+		Storage: StorageFiles,
+		path:    path,
+	}
+
+	if _, err := toml.DecodeFile(path, cfg); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	if cfg.Notebook == "" {
+		cfg.Notebook = DefaultNotebook
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Validate checks that all settings have supported values
+func (c *Config) Validate() error {
+	if err := validateStorage(c.Storage); err != nil {
+		return err
+	}
+
+	if c.KeyCache != "" {
+		if timeout, err := time.ParseDuration(c.KeyCache); err != nil || timeout < 0 {
+			return fmt.Errorf("invalid key_cache %q, expected a duration such as \"15m\"", c.KeyCache)
+		}
+	}
+
+	for name, notebook := range c.Notebooks {
+		if !notebookName.MatchString(name) {
+			return fmt.Errorf("invalid notebook name %q", name)
+		}
+		if notebook == nil {
+			return fmt.Errorf("notebook %q has no settings", name)
+		}
+		if notebook.Storage != "" {
+			if err := validateStorage(notebook.Storage); err != nil {
+				return fmt.Errorf("notebook %q: %w", name, err)
+			}
+		}
+	}
+
+	if !c.HasNotebook(c.Notebook) {
+		return fmt.Errorf("current notebook %q does not exist", c.Notebook)
+	}
+
+	return nil
+}
+
+// validateStorage checks that storage names a supported backend
+func validateStorage(storage string) error {
+	switch storage {
+	case StorageFiles, StorageSQLite:
+		return nil
+	default:
+		return fmt.Errorf("unknown storage %q, expected %q or %q", storage, StorageFiles, StorageSQLite)
+	}
+}
+
+// Save writes the config back to the file it was loaded from. The file is
+// replaced atomically, so an interrupted save never leaves it half written.
+func (c *Config) Save() error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(c); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := fsutil.WriteFileAtomic(c.path, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write config %s: %w", c.path, err)
+	}
+
+	return nil
+}
+
+// Path returns the path of the config file
+func (c *Config) Path() string {
+	return c.path
+}
+
+// Home returns the application directory the config file lives in
+func (c *Config) Home() string {
+	return filepath.Dir(c.path)
+}
+
+// KeyCacheTimeout returns how long an encryption key may be cached; zero
+// disables the cache
+func (c *Config) KeyCacheTimeout() time.Duration {
+	timeout, _ := time.ParseDuration(c.KeyCache)
+	return timeout
+}
+
+// HasNotebook reports whether a notebook with the given name exists
+func (c *Config) HasNotebook(name string) bool {
+	if name == DefaultNotebook {
+		return true
+	}
+	_, ok := c.Notebooks[name]
+	return ok
+}
+
+// NotebookNames returns the names of all notebooks, default first
+func (c *Config) NotebookNames() []string {
+	names := []string{DefaultNotebook}
+	for name := range c.Notebooks {
+		if name != DefaultNotebook {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// NotebookDir returns the directory holding the notes of a notebook
+func (c *Config) NotebookDir(name string) string {
+	if notebook, ok := c.Notebooks[name]; ok && notebook.Path != "" {
+		if filepath.IsAbs(notebook.Path) {
+			return notebook.Path
+		}
+		return filepath.Join(c.Home(), notebook.Path)
+	}
+
+	if name == DefaultNotebook {
+		return filepath.Join(c.Home(), "notes")
+	}
+	return filepath.Join(c.Home(), "notebooks", name)
+}
+
+// NotebookStorage returns the storage backend of a notebook
+func (c *Config) NotebookStorage(name string) string {
+	if name == DefaultNotebook {
+		return c.Storage
+	}
+	if notebook, ok := c.Notebooks[name]; ok && notebook.Storage != "" {
+		return notebook.Storage
+	}
+	return StorageFiles
+}
+
+// SetNotebookStorage changes the storage backend of a notebook
+func (c *Config) SetNotebookStorage(name, storage string) error {
+	if err := validateStorage(storage); err != nil {
+		return err
+	}
+
+	switch notebook, ok := c.Notebooks[name]; {
+	case name == DefaultNotebook:
+		c.Storage = storage
+	case ok:
+		notebook.Storage = storage
+	default:
+		return fmt.Errorf("notebook %q does not exist", name)
+	}
+
+	return nil
+}
+
+// CreateNotebook adds a notebook stored at path, or at notebooks/<name> when
+// path is empty. New notebooks use the storage backend of the default notebook.
+func (c *Config) CreateNotebook(name, path string) error {
+	if !notebookName.MatchString(name) {
+		return fmt.Errorf("invalid notebook name %q, use letters, digits, '-' and '_'", name)
+	}
+	if c.HasNotebook(name) {
+		return fmt.Errorf("notebook %q already exists", name)
+	}
+
+	if c.Notebooks == nil {
+		c.Notebooks = make(map[string]*Notebook)
+	}
+	c.Notebooks[name] = &Notebook{Path: path, Storage: c.Storage}
+
+	return nil
+}
+
+// RenameNotebook renames a notebook, keeping it current if it was. Moving
+// its directory is left to the caller.
+func (c *Config) RenameNotebook(oldName, newName string) error {
+	if oldName == DefaultNotebook {
+		return fmt.Errorf("the %s notebook cannot be renamed", DefaultNotebook)
+	}
+	notebook, ok := c.Notebooks[oldName]
+	if !ok {
+		return fmt.Errorf("notebook %q does not exist", oldName)
+	}
+	if !notebookName.MatchString(newName) {
+		return fmt.Errorf("invalid notebook name %q, use letters, digits, '-' and '_'", newName)
+	}
+	if c.HasNotebook(newName) {
+		return fmt.Errorf("notebook %q already exists", newName)
+	}
+
+	delete(c.Notebooks, oldName)
+	c.Notebooks[newName] = notebook
+
+	if c.Notebook == oldName {
+		c.Notebook = newName
+	}
+
+	return nil
+}
+
+// UseNotebook makes a notebook the current one
+func (c *Config) UseNotebook(name string) error {
+	if !c.HasNotebook(name) {
+		return fmt.Errorf("notebook %q does not exist", name)
+	}
+
+	c.Notebook = name
+	return nil
+}
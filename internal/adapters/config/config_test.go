@@ -0,0 +1,228 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig writes a config file into a new home directory and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestHomeDir(t *testing.T) {
+	t.Setenv(HomeEnv, "")
+	if home, err := os.UserHomeDir(); err == nil && HomeDir() != filepath.Join(home, ".jotterxpress") {
+		t.Errorf("HomeDir() = %s without %s, want ~/.jotterxpress", HomeDir(), HomeEnv)
+	}
+
+	t.Setenv(HomeEnv, "/srv/notes")
+	if HomeDir() != "/srv/notes" {
+		t.Errorf("HomeDir() = %s, want $%s", HomeDir(), HomeEnv)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string // empty means no config file
+		storage  string
+		notebook string
+		keyCache time.Duration
+	}{
+		{"defaults", "", StorageFiles, DefaultNotebook, 0},
+		{"empty file", "\n", StorageFiles, DefaultNotebook, 0},
+		{"file overrides defaults", "storage = \"sqlite\"\nkey_cache = \"15m\"\n", StorageSQLite, DefaultNotebook, 15 * time.Minute},
+		{"current notebook", "notebook = \"work\"\n[notebooks.work]\npath = \"work\"\n", StorageFiles, "work", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config")
+			if tt.content != "" {
+				path = writeConfig(t, tt.content)
+			}
+
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if cfg.Storage != tt.storage || cfg.Notebook != tt.notebook || cfg.KeyCacheTimeout() != tt.keyCache {
+				t.Errorf("loaded storage %q, notebook %q, key cache %s, want %q, %q, %s",
+					cfg.Storage, cfg.Notebook, cfg.KeyCacheTimeout(), tt.storage, tt.notebook, tt.keyCache)
+			}
+			if cfg.Path() != path || cfg.Home() != filepath.Dir(path) {
+				t.Errorf("config at %s reports path %s and home %s", path, cfg.Path(), cfg.Home())
+			}
+		})
+	}
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	for name, content := range map[string]string{
+		"not TOML":              "storage = ",
+		"unknown storage":       "storage = \"cloud\"\n",
+		"negative key cache":    "key_cache = \"-5m\"\n",
+		"key cache no duration": "key_cache = \"soon\"\n",
+		"bad notebook name":     "[notebooks.\"../up\"]\n",
+		"bad notebook storage":  "[notebooks.work]\nstorage = \"cloud\"\n",
+		"missing notebook":      "notebook = \"work\"\n",
+	} {
+		if _, err := Load(writeConfig(t, content)); err == nil {
+			t.Errorf("%s: Load succeeded, want an error", name)
+		}
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "home", "config")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	cfg.Storage = StorageSQLite
+	cfg.KeyCache = "1h"
+	if err := cfg.CreateNotebook("work", "/srv/work"); err != nil {
+		t.Fatalf("CreateNotebook failed: %v", err)
+	}
+	if err := cfg.UseNotebook("work"); err != nil {
+		t.Fatalf("UseNotebook failed: %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// Saving replaces the file and leaves no temporary files behind
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("second Save failed: %v", err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "config" {
+		t.Errorf("config directory holds %v, want only the config file", entries)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load of the saved config failed: %v", err)
+	}
+	if loaded.Storage != StorageSQLite || loaded.KeyCache != "1h" || loaded.Notebook != "work" {
+		t.Errorf("saved config loads as storage %q, key cache %q, notebook %q", loaded.Storage, loaded.KeyCache, loaded.Notebook)
+	}
+	if loaded.NotebookDir("work") != "/srv/work" || loaded.NotebookStorage("work") != StorageSQLite {
+		t.Errorf("saved notebook loads as %s (%s)", loaded.NotebookDir("work"), loaded.NotebookStorage("work"))
+	}
+}
+
+func TestNotebooks(t *testing.T) {
+	home := t.TempDir()
+	cfg, err := Load(filepath.Join(home, "config"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got := strings.Join(cfg.NotebookNames(), ","); got != DefaultNotebook {
+		t.Errorf("NotebookNames() = %s, want only %s", got, DefaultNotebook)
+	}
+
+	for _, name := range []string{"work", "archive"} {
+		if err := cfg.CreateNotebook(name, ""); err != nil {
+			t.Fatalf("CreateNotebook(%s) failed: %v", name, err)
+		}
+	}
+	if err := cfg.CreateNotebook("shared", "rel/shared"); err != nil {
+		t.Fatalf("CreateNotebook(shared) failed: %v", err)
+	}
+	for _, name := range []string{"work", DefaultNotebook, "bad name", "-dash", ""} {
+		if err := cfg.CreateNotebook(name, ""); err == nil {
+			t.Errorf("CreateNotebook(%q) succeeded, want an error", name)
+		}
+	}
+
+	if got := strings.Join(cfg.NotebookNames(), ","); got != "default,archive,shared,work" {
+		t.Errorf("NotebookNames() = %s, want default first, then sorted", got)
+	}
+
+	dirs := map[string]string{
+		DefaultNotebook: filepath.Join(home, "notes"),
+		"work":          filepath.Join(home, "notebooks", "work"),
+		"shared":        filepath.Join(home, "rel", "shared"),
+	}
+	for name, want := range dirs {
+		if got := cfg.NotebookDir(name); got != want {
+			t.Errorf("NotebookDir(%s) = %s, want %s", name, got, want)
+		}
+	}
+
+	if err := cfg.UseNotebook("work"); err != nil {
+		t.Fatalf("UseNotebook failed: %v", err)
+	}
+	if err := cfg.UseNotebook("missing"); err == nil {
+		t.Error("UseNotebook of a missing notebook succeeded")
+	}
+
+	// Renaming the current notebook keeps it current
+	if err := cfg.RenameNotebook("work", "job"); err != nil {
+		t.Fatalf("RenameNotebook failed: %v", err)
+	}
+	if cfg.Notebook != "job" || cfg.HasNotebook("work") || !cfg.HasNotebook("job") {
+		t.Errorf("after renaming, current notebook is %s and work exists: %t", cfg.Notebook, cfg.HasNotebook("work"))
+	}
+	for _, names := range [][2]string{{DefaultNotebook, "main"}, {"missing", "other"}, {"job", "archive"}, {"job", "bad name"}} {
+		if err := cfg.RenameNotebook(names[0], names[1]); err == nil {
+			t.Errorf("RenameNotebook(%s, %s) succeeded, want an error", names[0], names[1])
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate failed after notebook changes: %v", err)
+	}
+}
+
+func TestNotebookStorage(t *testing.T) {
+	cfg, err := Load(writeConfig(t, "storage = \"sqlite\"\n[notebooks.legacy]\n[notebooks.fast]\nstorage = \"sqlite\"\n"))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// Notebooks without a storage setting predate per-notebook storage
+	want := map[string]string{DefaultNotebook: StorageSQLite, "legacy": StorageFiles, "fast": StorageSQLite, "missing": StorageFiles}
+	for name, storage := range want {
+		if got := cfg.NotebookStorage(name); got != storage {
+			t.Errorf("NotebookStorage(%s) = %s, want %s", name, got, storage)
+		}
+	}
+
+	// New notebooks start with the storage of the default notebook
+	if err := cfg.CreateNotebook("new", ""); err != nil {
+		t.Fatalf("CreateNotebook failed: %v", err)
+	}
+	if got := cfg.NotebookStorage("new"); got != StorageSQLite {
+		t.Errorf("new notebook uses %s, want %s", got, StorageSQLite)
+	}
+
+	if err := cfg.SetNotebookStorage("legacy", StorageSQLite); err != nil || cfg.NotebookStorage("legacy") != StorageSQLite {
+		t.Errorf("SetNotebookStorage(legacy) did not switch it (%v)", err)
+	}
+	if err := cfg.SetNotebookStorage(DefaultNotebook, StorageFiles); err != nil || cfg.Storage != StorageFiles {
+		t.Errorf("SetNotebookStorage(default) did not switch it (%v)", err)
+	}
+	if err := cfg.SetNotebookStorage("fast", "cloud"); err == nil {
+		t.Error("SetNotebookStorage accepted an unknown storage")
+	}
+	if err := cfg.SetNotebookStorage("missing", StorageFiles); err == nil {
+		t.Error("SetNotebookStorage of a missing notebook succeeded")
+	}
+}
@@ -0,0 +1,70 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "notes", "2025-10-16.json")
+
+	// Missing parent directories are created
+	if err := WriteFileAtomic(path, []byte("first")); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+	if err := WriteFileAtomic(path, []byte("second")); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if string(data) != "second" {
+		t.Errorf("file holds %q, want %q", data, "second")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("file mode is %v, want 0644", info.Mode().Perm())
+	}
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d files, want only the written one", len(entries))
+	}
+}
+
+func TestWriteFileAtomicFailureKeepsOldContent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	if err := WriteFileAtomic(path, []byte("kept")); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+
+	// Renaming over a directory fails after the temporary file is written
+	if err := WriteFileAtomic(dir, []byte("lost")); err == nil {
+		t.Fatal("WriteFileAtomic over a directory succeeded")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "kept" {
+		t.Errorf("file holds %q, %v after a failed write, want %q", data, err, "kept")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d files after a failed write, want 1", len(entries))
+	}
+}
@@ -0,0 +1,78 @@
+//go:build !unix && !windows
+
+package fsutil
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// lockTimeout is how long LockFile waits for another process to release the lock
+	lockTimeout = 10 * time.Second
+
+	// staleLockAge is the age after which a lock file is taken to be left
+	// behind by a process that crashed; no write holds the lock this long
+	staleLockAge = 30 * time.Second
+)
+
+// LockFile acquires an exclusive lock by creating the given file, retrying
+// until it becomes available. The file records the PID and time of the
+// holder, and a lock older than staleLockAge is broken. The returned
+// function releases the lock.
+func LockFile(path string) (func() error, error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0644)
+		if err == nil {
+			_, err = fmt.Fprintf(file, "%d %d\n", os.Getpid(), time.Now().UnixNano())
+			file.Close()
+			if err != nil {
+				os.Remove(path)
+				return nil, fmt.Errorf("failed to write lock file %s: %w", path, err)
+			}
+			return func() error {
+				return os.Remove(path)
+			}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock file %s: %w", path, err)
+		}
+
+		if lockIsStale(path) {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock %s; if no other jtx is running, delete the file", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// lockIsStale reports whether the lock file at path was taken longer than
+// staleLockAge ago. A lock file without a readable time falls back to its
+// modification time.
+func lockIsStale(path string) bool {
+	taken := time.Time{}
+	if data, err := os.ReadFile(path); err == nil {
+		if fields := strings.Fields(string(data)); len(fields) == 2 {
+			if nanos, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+				taken = time.Unix(0, nanos)
+			}
+		}
+	}
+	if taken.IsZero() {
+		info, err := os.Stat(path)
+		if err != nil {
+			// Released while we looked; retry at once
+			return os.IsNotExist(err)
+		}
+		taken = info.ModTime()
+	}
+
+	return time.Since(taken) > staleLockAge
+}
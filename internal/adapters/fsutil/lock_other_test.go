@@ -0,0 +1,29 @@
+//go:build !unix && !windows
+
+package fsutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLockFileBreaksStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lock")
+
+	// A lock left behind by a process that crashed a minute ago
+	taken := time.Now().Add(-time.Minute).UnixNano()
+	if err := os.WriteFile(path, []byte(fmt.Sprintf("4242 %d\n", taken)), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	unlock, err := LockFile(path)
+	if err != nil {
+		t.Fatalf("LockFile did not break the stale lock: %v", err)
+	}
+	if err := unlock(); err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+}
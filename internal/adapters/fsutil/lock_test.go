@@ -0,0 +1,63 @@
+package fsutil
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLockFileExcludesOtherHolders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lock")
+
+	unlock, err := LockFile(path)
+	if err != nil {
+		t.Fatalf("LockFile failed: %v", err)
+	}
+
+	acquired := make(chan func() error)
+	go func() {
+		second, err := LockFile(path)
+		if err != nil {
+			t.Errorf("second LockFile failed: %v", err)
+			close(acquired)
+			return
+		}
+		acquired <- second
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("lock was acquired twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+
+	select {
+	case second := <-acquired:
+		if second == nil {
+			return
+		}
+		if err := second(); err != nil {
+			t.Errorf("second unlock failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("lock was not acquired after it was released")
+	}
+}
+
+func TestLockFileCanBeTakenAgain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lock")
+
+	for i := 0; i < 3; i++ {
+		unlock, err := LockFile(path)
+		if err != nil {
+			t.Fatalf("LockFile %d failed: %v", i, err)
+		}
+		if err := unlock(); err != nil {
+			t.Fatalf("unlock %d failed: %v", i, err)
+		}
+	}
+}
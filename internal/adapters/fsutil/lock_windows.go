@@ -0,0 +1,33 @@
+//go:build windows
+
+package fsutil
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// LockFile acquires an exclusive lock on the given file, blocking until it
+// becomes available. Windows releases the lock when the process exits, so a
+// crash never leaves the notes directory locked. The returned function
+// releases the lock.
+func LockFile(path string) (func() error, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %s: %w", path, err)
+	}
+
+	handle := windows.Handle(file.Fd())
+	overlapped := new(windows.Overlapped)
+	if err := windows.LockFileEx(handle, windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, overlapped); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	return func() error {
+		defer file.Close()
+		return windows.UnlockFileEx(handle, 0, 1, 0, overlapped)
+	}, nil
+}
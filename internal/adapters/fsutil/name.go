@@ -0,0 +1,28 @@
+package fsutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// SafeName returns a file name for an identifier that is valid on every
+// platform and never leaves its directory. Identifiers made only of ASCII
+// letters, digits, '-' and '_' are kept; any other is cleaned and gets a
+// hash of the original to stay unique, so "2025-10-16-09:30:00" and
+// "../../x" become distinct plain names.
+func SafeName(id string) string {
+	clean := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, id)
+
+	if clean != id || clean == "" {
+		sum := sha256.Sum256([]byte(id))
+		clean += "-" + hex.EncodeToString(sum[:4])
+	}
+
+	return clean
+}
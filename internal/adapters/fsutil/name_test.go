@@ -0,0 +1,26 @@
+package fsutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSafeName(t *testing.T) {
+	for _, id := range []string{"3f2b9c1e-5d7a-4e8b-9c0d-1a2b3c4d5e6f", "note_1"} {
+		if got := SafeName(id); got != id {
+			t.Errorf("SafeName(%q) = %q, want it unchanged", id, got)
+		}
+	}
+
+	seen := map[string]string{}
+	for _, id := range []string{"2025-10-16-09:30:00", "2025-10-16-09_30_00", "../../x", "..\\x", "a/b", ""} {
+		got := SafeName(id)
+		if got == "" || strings.ContainsAny(got, `/\:.`) {
+			t.Errorf("SafeName(%q) = %q, want a plain file name", id, got)
+		}
+		if other, ok := seen[got]; ok {
+			t.Errorf("SafeName(%q) = SafeName(%q) = %q", id, other, got)
+		}
+		seen[got] = id
+	}
+}
@@ -0,0 +1,66 @@
+package git
+
+import (
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"strings"
+)
+
+// summaryLength is the maximum number of characters of note content in a
+// commit message
+const summaryLength = 60
+
+// listener commits the notes directory after every change made through the
+// NoteService
+type listener struct {
+	repo      *Repo
+	summaries bool
+	onError   func(error)
+}
+
+// NewListener creates a NoteListener that commits every change to repo.
+// When summaries is false commit messages leave out note content, which
+// keeps encrypted notes out of the history. Commit failures never undo the
+// change; they are passed to onError.
+func NewListener(repo *Repo, summaries bool, onError func(error)) ports.NoteListener {
+	return &listener{repo: repo, summaries: summaries, onError: onError}
+}
+
+// NoteChanged commits the change described by event
+func (l *listener) NoteChanged(event ports.NoteEvent) {
+	if err := l.repo.Commit(CommitMessage(event, l.summaries)); err != nil && l.onError != nil {
+		l.onError(err)
+	}
+}
+
+// CommitMessage describes an event, e.g. "complete task 1729...: Deploy API".
+// The content summary after the colon is only added when summary is true.
+func CommitMessage(event ports.NoteEvent, summary bool) string {
+	if event.Kind == ports.TrashEmptied {
+		return fmt.Sprintf("empty trash (%d notes)", event.Count)
+	}
+
+	if event.Note == nil {
+		return fmt.Sprintf("%s note %s", event.Kind, event.NoteID)
+	}
+
+	noun := string(event.Note.Type)
+	if event.Note.Type == entities.NoteTypeText {
+		noun = "note"
+	}
+
+	if !summary {
+		return fmt.Sprintf("%s %s %s", event.Kind, noun, event.NoteID)
+	}
+
+	text := strings.TrimSpace(event.Note.Content)
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = strings.TrimSpace(text[:i])
+	}
+	if runes := []rune(text); len(runes) > summaryLength {
+		text = string(runes[:summaryLength-1]) + "…"
+	}
+
+	return fmt.Sprintf("%s %s %s: %s", event.Kind, noun, event.NoteID, text)
+}
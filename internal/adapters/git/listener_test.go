@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+	"jotterxpress/internal/adapters/repository"
+	"jotterxpress/internal/application/services"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommitMessage(t *testing.T) {
+	text := entities.NewNote("Deploy the API\nthen tell the team")
+	task := entities.NewTask("Write the release notes", entities.PriorityHigh)
+	long := entities.NewNote(strings.Repeat("a", 80))
+
+	tests := []struct {
+		event   ports.NoteEvent
+		summary bool
+		want    string
+	}{
+		{ports.NoteEvent{Kind: ports.NoteCreated, NoteID: "n1", Note: text}, true, "create note n1: Deploy the API"},
+		{ports.NoteEvent{Kind: ports.NoteCreated, NoteID: "n1", Note: text}, false, "create note n1"},
+		{ports.NoteEvent{Kind: ports.NoteCompleted, NoteID: "t1", Note: task}, true, "complete task t1: Write the release notes"},
+		{ports.NoteEvent{Kind: ports.NoteUpdated, NoteID: "n2", Note: long}, true, "update note n2: " + strings.Repeat("a", summaryLength-1) + "…"},
+		{ports.NoteEvent{Kind: ports.NoteDeleted, NoteID: "n3"}, true, "delete note n3"},
+		{ports.NoteEvent{Kind: ports.TrashEmptied, Count: 4}, true, "empty trash (4 notes)"},
+	}
+
+	for _, tt := range tests {
+		if got := CommitMessage(tt.event, tt.summary); got != tt.want {
+			t.Errorf("CommitMessage(%s, %v) = %q, want %q", tt.event.Kind, tt.summary, got, tt.want)
+		}
+	}
+}
+
+func TestListenerCommitsEveryChange(t *testing.T) {
+	requireGit(t)
+	repo := newTestRepo(t)
+
+	onError := func(err error) { t.Errorf("commit failed: %v", err) }
+	service := services.NewNoteService(repository.NewFileRepository(repo.Dir()), NewListener(repo, true, onError))
+
+	note, err := service.CreateNote("buy more coffee beans")
+	if err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+	note.Content = "buy more tea"
+	if err := service.SaveNote(note); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+	if err := service.DeleteNote(note.ID); err != nil {
+		t.Fatalf("DeleteNote failed: %v", err)
+	}
+
+	want := []string{
+		fmt.Sprintf("delete note %s: buy more tea", note.ID),
+		fmt.Sprintf("update note %s: buy more tea", note.ID),
+		fmt.Sprintf("create note %s: buy more coffee beans", note.ID),
+		"enable versioning",
+	}
+	if got := messages(t, repo); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("history is\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Everything the change wrote is committed
+	if out, err := repo.run("status", "--porcelain"); err != nil || out != "" {
+		t.Errorf("uncommitted files after the changes: %q, %v", out, err)
+	}
+}
+
+func TestListenerOnDisabledRepoDoesNothing(t *testing.T) {
+	requireGit(t)
+
+	repo := NewRepo(filepath.Join(t.TempDir(), "notes"))
+	listener := NewListener(repo, true, func(err error) { t.Errorf("commit failed: %v", err) })
+	service := services.NewNoteService(repository.NewFileRepository(repo.Dir()), listener)
+
+	if _, err := service.CreateNote("not versioned at all"); err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+	if repo.Enabled() {
+		t.Error("listener enabled versioning")
+	}
+}
@@ -0,0 +1,243 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RemoteName is the remote used by Sync
+const RemoteName = "origin"
+
+// ignored lists files in the notes directory that are derived or transient
+// and must never be committed
+var ignored = []string{
+	".lock",
+	"*.lock",
+	".*.tmp-*",
+	".index.json",
+	".search-index.json",
+	".backups/",
+	".sync/",
+}
+
+// Commit is one entry of the version history
+type Commit struct {
+	Hash    string
+	Author  string
+	Date    time.Time
+	Message string
+}
+
+// Repo runs git commands against the notes directory
+type Repo struct {
+	dir string
+}
+
+// NewRepo creates a Repo for the given notes directory
+func NewRepo(dir string) *Repo {
+	return &Repo{dir: dir}
+}
+
+// Dir returns the notes directory
+func (r *Repo) Dir() string {
+	return r.dir
+}
+
+// Enabled reports whether the notes directory is a git repository
+func (r *Repo) Enabled() bool {
+	info, err := os.Stat(filepath.Join(r.dir, ".git"))
+	return err == nil && info.IsDir()
+}
+
+// Init turns the notes directory into a git repository and commits the
+// current notes. An existing repository is kept as it is.
+func (r *Repo) Init() error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("git is not installed")
+	}
+
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create notes directory: %w", err)
+	}
+
+	if !r.Enabled() {
+		if _, err := r.run("init", "--quiet"); err != nil {
+			return err
+		}
+	}
+
+	ignore := strings.Join(ignored, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(r.dir, ".gitignore"), []byte(ignore), 0644); err != nil {
+		return fmt.Errorf("failed to write .gitignore: %w", err)
+	}
+
+	return r.Commit("enable versioning")
+}
+
+// Commit stages every change in the notes directory and commits it. Nothing
+// is committed when there are no changes or versioning is not enabled, even
+// when the notes directory lies inside another git repository.
+func (r *Repo) Commit(message string) error {
+	if !r.Enabled() {
+		return nil
+	}
+
+	if _, err := r.run("add", "--all"); err != nil {
+		return err
+	}
+
+	// diff --cached --quiet exits with 1 when something is staged
+	if _, err := r.run("diff", "--cached", "--quiet"); err == nil {
+		return nil
+	}
+
+	_, err := r.run(r.withIdentity("commit", "--quiet", "--no-verify", "-m", message)...)
+	return err
+}
+
+// Log returns the most recent commits, newest first. When grep is not empty
+// only commits whose message contains it are returned. A limit of 0 returns
+// every commit.
+func (r *Repo) Log(limit int, grep string) ([]Commit, error) {
+	args := []string{"log", "--format=%H%x1f%an%x1f%at%x1f%s"}
+	if limit > 0 {
+		args = append(args, fmt.Sprintf("--max-count=%d", limit))
+	}
+	if grep != "" {
+		args = append(args, "--fixed-strings", "--grep="+grep)
+	}
+
+	// A repository without commits has no history yet
+	if _, err := r.run("rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return nil, nil
+	}
+
+	out, err := r.run(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+
+		var seconds int64
+		fmt.Sscanf(fields[2], "%d", &seconds)
+
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Date:    time.Unix(seconds, 0),
+			Message: fields[3],
+		})
+	}
+
+	return commits, nil
+}
+
+// Remote returns the URL of the sync remote, or an empty string if none is set
+func (r *Repo) Remote() string {
+	out, err := r.run("remote", "get-url", RemoteName)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// SetRemote sets the URL of the sync remote
+func (r *Repo) SetRemote(url string) error {
+	if r.Remote() == "" {
+		_, err := r.run("remote", "add", RemoteName, url)
+		return err
+	}
+
+	_, err := r.run("remote", "set-url", RemoteName, url)
+	return err
+}
+
+// Sync commits pending changes, merges the remote branch and pushes the result.
+// A merge conflict is aborted so the notes directory is left as it was.
+func (r *Repo) Sync() error {
+	if r.Remote() == "" {
+		return fmt.Errorf("no remote configured")
+	}
+
+	if err := r.Commit("sync local changes"); err != nil {
+		return err
+	}
+
+	branch, err := r.run("symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return err
+	}
+	branch = strings.TrimSpace(branch)
+
+	// An empty remote has nothing to pull yet
+	heads, err := r.run("ls-remote", "--heads", RemoteName, branch)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(heads) != "" {
+		if _, err := r.run("fetch", "--quiet", RemoteName, branch); err != nil {
+			return err
+		}
+
+		merge := r.withIdentity("merge", "--quiet", "--no-edit", "--allow-unrelated-histories", "FETCH_HEAD")
+		if _, err := r.run(merge...); err != nil {
+			r.run("merge", "--abort")
+			return fmt.Errorf("remote changes conflict with local notes, resolve them with git in %s: %w", r.dir, err)
+		}
+	}
+
+	if _, err := r.run("push", "--quiet", "--set-upstream", RemoteName, branch); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// withIdentity prefixes a git command with a fallback author when the user
+// has not configured one, so commits never fail for lack of an identity
+func (r *Repo) withIdentity(args ...string) []string {
+	if out, err := r.run("config", "user.email"); err == nil && strings.TrimSpace(out) != "" {
+		return args
+	}
+
+	return append([]string{"-c", "user.name=JotterXpress", "-c", "user.email=jotterxpress@localhost"}, args...)
+}
+
+// run executes a git command in the notes directory and returns its output
+func (r *Repo) run(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// Name the subcommand, skipping any -c options in front of it
+		name := args[0]
+		for i := 0; i+2 < len(args) && args[i] == "-c"; i += 2 {
+			name = args[i+2]
+		}
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return stdout.String(), fmt.Errorf("git %s: %s", name, strings.TrimSpace(stderr.String()))
+		}
+		return stdout.String(), fmt.Errorf("git %s: %w", name, err)
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,220 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// requireGit skips the test when git is not installed and keeps the user's
+// git configuration out of it
+func requireGit(t *testing.T) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+}
+
+// newTestRepo returns a versioned notes directory
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	repo := NewRepo(filepath.Join(t.TempDir(), "notes"))
+	if err := repo.Init(); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	return repo
+}
+
+// messages returns the commit messages of a repo, newest first
+func messages(t *testing.T, repo *Repo) []string {
+	t.Helper()
+
+	commits, err := repo.Log(0, "")
+	if err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+
+	var result []string
+	for _, commit := range commits {
+		result = append(result, commit.Message)
+	}
+	return result
+}
+
+// writeNote writes a file into the notes directory
+func writeNote(t *testing.T, repo *Repo, name, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(repo.Dir(), name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInitCommitsAndIgnoresDerivedFiles(t *testing.T) {
+	requireGit(t)
+	repo := newTestRepo(t)
+
+	if !repo.Enabled() {
+		t.Fatal("repo is not enabled after Init")
+	}
+	if got := strings.Join(messages(t, repo), "|"); got != "enable versioning" {
+		t.Errorf("history is %q, want the initial commit", got)
+	}
+
+	// Derived files never make a commit
+	writeNote(t, repo, ".index.json", "{}")
+	writeNote(t, repo, ".lock", "")
+	if err := repo.Commit("derived files only"); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if got := len(messages(t, repo)); got != 1 {
+		t.Errorf("got %d commits, want no commit for derived files", got)
+	}
+
+	// Init again keeps the history
+	if err := repo.Init(); err != nil {
+		t.Fatalf("second Init failed: %v", err)
+	}
+	if got := len(messages(t, repo)); got != 1 {
+		t.Errorf("got %d commits after a second Init, want 1", got)
+	}
+}
+
+func TestLogFiltersAndLimits(t *testing.T) {
+	requireGit(t)
+	repo := newTestRepo(t)
+
+	for i, name := range []string{"a.json", "b.json", "c.json"} {
+		writeNote(t, repo, name, name)
+		if err := repo.Commit("create note " + strings.Repeat("x", i+1)); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+	}
+
+	commits, err := repo.Log(2, "")
+	if err != nil || len(commits) != 2 || commits[0].Message != "create note xxx" {
+		t.Errorf("Log(2) = %+v, %v, want the two newest commits", commits, err)
+	}
+	commits, err = repo.Log(0, "note xx")
+	if err != nil || len(commits) != 2 {
+		t.Errorf("Log with grep returned %d commits, %v, want 2", len(commits), err)
+	}
+	if commits[0].Hash == "" || commits[0].Author == "" || commits[0].Date.IsZero() {
+		t.Errorf("commit %+v is missing fields", commits[0])
+	}
+}
+
+func TestSyncRoundTrip(t *testing.T) {
+	requireGit(t)
+
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "init", "--quiet", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare failed: %v: %s", err, out)
+	}
+
+	laptop, desktop := newTestRepo(t), newTestRepo(t)
+	for _, repo := range []*Repo{laptop, desktop} {
+		if err := repo.Sync(); err == nil {
+			t.Error("Sync without a remote succeeded")
+		}
+		if err := repo.SetRemote(remote); err != nil {
+			t.Fatalf("SetRemote failed: %v", err)
+		}
+		if repo.Remote() != remote {
+			t.Errorf("remote is %q, want %q", repo.Remote(), remote)
+		}
+	}
+
+	// Uncommitted changes are committed by Sync and pushed to an empty remote
+	writeNote(t, laptop, "2025-10-16.json", "from the laptop")
+	if err := laptop.Sync(); err != nil {
+		t.Fatalf("laptop Sync failed: %v", err)
+	}
+
+	writeNote(t, desktop, "2025-10-17.json", "from the desktop")
+	if err := desktop.Sync(); err != nil {
+		t.Fatalf("desktop Sync failed: %v", err)
+	}
+	if err := laptop.Sync(); err != nil {
+		t.Fatalf("second laptop Sync failed: %v", err)
+	}
+
+	for _, repo := range []*Repo{laptop, desktop} {
+		for name, want := range map[string]string{"2025-10-16.json": "from the laptop", "2025-10-17.json": "from the desktop"} {
+			data, err := os.ReadFile(filepath.Join(repo.Dir(), name))
+			if err != nil || string(data) != want {
+				t.Errorf("%s holds %q, %v, want %q", name, data, err, want)
+			}
+		}
+	}
+}
+
+func TestSyncAbortsConflicts(t *testing.T) {
+	requireGit(t)
+
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "init", "--quiet", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare failed: %v: %s", err, out)
+	}
+
+	laptop, desktop := newTestRepo(t), newTestRepo(t)
+	for _, repo := range []*Repo{laptop, desktop} {
+		if err := repo.SetRemote(remote); err != nil {
+			t.Fatalf("SetRemote failed: %v", err)
+		}
+	}
+
+	writeNote(t, laptop, "2025-10-16.json", "laptop version")
+	if err := laptop.Sync(); err != nil {
+		t.Fatalf("laptop Sync failed: %v", err)
+	}
+	writeNote(t, desktop, "2025-10-16.json", "desktop version")
+	if err := desktop.Sync(); err == nil {
+		t.Fatal("Sync of conflicting changes succeeded")
+	}
+
+	// The merge is aborted, so the local notes are left as they were
+	data, err := os.ReadFile(filepath.Join(desktop.Dir(), "2025-10-16.json"))
+	if err != nil || string(data) != "desktop version" {
+		t.Errorf("note holds %q, %v after the aborted merge, want the local version", data, err)
+	}
+}
+
+func TestDisabledRepoIsNoOp(t *testing.T) {
+	requireGit(t)
+
+	// A notes directory inside another repository must not commit to it
+	parent := t.TempDir()
+	if out, err := exec.Command("git", "init", "--quiet", parent).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v: %s", err, out)
+	}
+	repo := NewRepo(filepath.Join(parent, "notes"))
+	if err := os.MkdirAll(repo.Dir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeNote(t, repo, "2025-10-16.json", "[]")
+
+	if repo.Enabled() {
+		t.Fatal("repo without .git is enabled")
+	}
+	if err := repo.Commit("create note"); err != nil {
+		t.Errorf("Commit on a disabled repo failed: %v", err)
+	}
+	if commits, err := repo.Log(0, ""); err != nil || len(commits) != 0 {
+		t.Errorf("Log on a disabled repo returned %d commits, %v", len(commits), err)
+	}
+	if _, err := os.Stat(filepath.Join(repo.Dir(), ".git")); !os.IsNotExist(err) {
+		t.Errorf("Commit created a repository: %v", err)
+	}
+
+	parentRepo := NewRepo(parent)
+	if commits, err := parentRepo.Log(0, ""); err != nil || len(commits) != 0 {
+		t.Errorf("the enclosing repository got %d commits, %v", len(commits), err)
+	}
+}
@@ -0,0 +1,107 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"jotterxpress/internal/domain/ports"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// DirName is the directory of the jtx home holding hook scripts
+const DirName = "hooks"
+
+// timeout is how long a hook may run before it is stopped
+const timeout = 30 * time.Second
+
+// runner runs the hook scripts of a directory for changes made through the
+// NoteService
+type runner struct {
+	dir      string
+	notebook string
+	output   io.Writer
+	onError  func(error)
+}
+
+// NewRunner creates a NoteListener that runs the executable named
+// "pre-<kind>" in dir before a change, such as pre-delete, and "post-<kind>"
+// after it, such as post-complete. Hooks get the note as JSON on standard
+// input, and their output goes to output. A pre hook that fails refuses the
+// change; post hook failures are passed to onError.
+func NewRunner(dir, notebook string, output io.Writer, onError func(error)) ports.NoteListener {
+	return &runner{dir: dir, notebook: notebook, output: output, onError: onError}
+}
+
+// BeforeChange runs the pre hook of the change described by event
+func (r *runner) BeforeChange(event ports.NoteEvent) error {
+	name := "pre-" + string(event.Kind)
+	if err := r.run(name, event); err != nil {
+		return fmt.Errorf("%s hook refused the change: %w", name, err)
+	}
+	return nil
+}
+
+// NoteChanged runs the post hook of the change described by event
+func (r *runner) NoteChanged(event ports.NoteEvent) {
+	name := "post-" + string(event.Kind)
+	if err := r.run(name, event); err != nil && r.onError != nil {
+		r.onError(fmt.Errorf("%s hook failed: %w", name, err))
+	}
+}
+
+// run runs a hook, if it exists, with the note of event on standard input.
+// The kind of change, note ID and notebook are also passed in the
+// environment as JTX_EVENT, JTX_NOTE_ID and JTX_NOTEBOOK.
+func (r *runner) run(name string, event ports.NoteEvent) error {
+	path := filepath.Join(r.dir, name)
+	if !isExecutable(path) {
+		return nil
+	}
+
+	var input []byte
+	if event.Note != nil {
+		data, err := event.Note.ToJSON()
+		if err != nil {
+			return err
+		}
+		input = data
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Dir = r.dir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = r.output
+	cmd.Stderr = r.output
+	cmd.Env = append(os.Environ(),
+		"JTX_EVENT="+string(event.Kind),
+		"JTX_NOTE_ID="+event.NoteID,
+		"JTX_NOTEBOOK="+r.notebook,
+		"JTX_COUNT="+strconv.Itoa(event.Count),
+	)
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("stopped after %s", timeout)
+	}
+	return err
+}
+
+// isExecutable reports whether path is a file the user can run. Windows has
+// no execute permission, so any file counts there.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0111 != 0
+}
@@ -0,0 +1,63 @@
+package hooks
+
+import (
+	"bytes"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writeHook writes an executable shell script to dir
+func writeHook(t *testing.T, dir, name, script string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunnerRunsHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks are shell scripts")
+	}
+
+	dir := t.TempDir()
+	writeHook(t, dir, "pre-create", "grep -q password && { echo 'looks like a secret'; exit 1; }\nexit 0\n")
+	writeHook(t, dir, "post-complete", "echo \"$JTX_EVENT $JTX_NOTE_ID\"; cat\n")
+
+	var output bytes.Buffer
+	var failures []error
+	listener := NewRunner(dir, "default", &output, func(err error) { failures = append(failures, err) })
+	runner := listener.(ports.NoteGuard)
+
+	note := entities.NewNote("the password is hunter2")
+	if err := runner.BeforeChange(ports.NoteEvent{Kind: ports.NoteCreated, NoteID: note.ID, Note: note}); err == nil {
+		t.Error("pre-create hook did not refuse a note with a password")
+	}
+	if !strings.Contains(output.String(), "looks like a secret") {
+		t.Errorf("hook output %q does not explain the refusal", output.String())
+	}
+
+	note = entities.NewNote("buy milk")
+	if err := runner.BeforeChange(ports.NoteEvent{Kind: ports.NoteCreated, NoteID: note.ID, Note: note}); err != nil {
+		t.Errorf("pre-create hook refused a harmless note: %v", err)
+	}
+
+	// Kinds without a hook are allowed
+	if err := runner.BeforeChange(ports.NoteEvent{Kind: ports.NoteDeleted, NoteID: note.ID, Note: note}); err != nil {
+		t.Errorf("missing pre-delete hook refused the change: %v", err)
+	}
+
+	output.Reset()
+	listener.NoteChanged(ports.NoteEvent{Kind: ports.NoteCompleted, NoteID: note.ID, Note: note})
+	if !strings.HasPrefix(output.String(), "complete "+note.ID) || !strings.Contains(output.String(), `"buy milk"`) {
+		t.Errorf("post-complete hook wrote %q, want the event and the note JSON", output.String())
+	}
+	if len(failures) != 0 {
+		t.Errorf("post hooks failed: %v", failures)
+	}
+}
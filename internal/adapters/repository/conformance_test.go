@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"bytes"
+	"jotterxpress/internal/adapters/repository/repositorytest"
+	"jotterxpress/internal/domain/ports"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileRepositoryConformance(t *testing.T) {
+	repositorytest.TestNoteRepository(t, func(t *testing.T) ports.NoteRepository {
+		return NewFileRepository(t.TempDir())
+	})
+}
+
+func TestSQLiteRepositoryConformance(t *testing.T) {
+	repositorytest.TestNoteRepository(t, func(t *testing.T) ports.NoteRepository {
+		repo, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "notes.db"))
+		if err != nil {
+			t.Fatalf("NewSQLiteRepository failed: %v", err)
+		}
+		t.Cleanup(func() { repo.Close() })
+		return repo
+	})
+}
+
+func TestMemoryRepositoryConformance(t *testing.T) {
+	repositorytest.TestNoteRepository(t, func(t *testing.T) ports.NoteRepository {
+		return NewMemoryRepository()
+	})
+}
+
+func TestEncryptedRepositoryConformance(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	repositorytest.TestNoteRepository(t, func(t *testing.T) ports.NoteRepository {
+		return NewEncryptedRepository(NewMemoryRepository(), func() (*Cipher, error) {
+			return NewCipher(key)
+		})
+	})
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+)
+
+// trashImporter is implemented by repositories that can store trashed notes
+// without changing their deletion time
+type trashImporter interface {
+	AddToTrash(entry *entities.TrashedNote) error
+}
+
+// CopyStats reports how much data Copy transferred
+type CopyStats struct {
+	Notes   int
+	Trashed int
+}
+
+// Copy copies every note, and the trash and note history when dst supports
+// them, from src to dst. IDs and timestamps are kept as they are.
+func Copy(dst, src ports.NoteRepository) (*CopyStats, error) {
+	stats := &CopyStats{}
+
+	notes, err := src.GetAllNotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notes: %w", err)
+	}
+
+	for _, note := range notes {
+		// Read the history first: saving may add to it when src and dst
+		// share their storage
+		history, err := src.GetRevisions(note.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history of note %s: %w", note.ID, err)
+		}
+
+		if err := dst.Save(note); err != nil {
+			return nil, fmt.Errorf("failed to copy note %s: %w", note.ID, err)
+		}
+		if err := copyHistory(dst, note.ID, history); err != nil {
+			return nil, err
+		}
+		stats.Notes++
+	}
+
+	importer, ok := dst.(trashImporter)
+	if !ok {
+		return stats, nil
+	}
+
+	trash, err := src.GetTrash()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	for _, entry := range trash {
+		// Trashed notes only have history when they were edited
+		history, err := src.GetRevisions(entry.Note.ID)
+		if err != nil && !errors.Is(err, ports.ErrNoteNotFound) {
+			return nil, fmt.Errorf("failed to read history of note %s: %w", entry.Note.ID, err)
+		}
+
+		if err := importer.AddToTrash(entry); err != nil {
+			return nil, fmt.Errorf("failed to copy trashed note %s: %w", entry.Note.ID, err)
+		}
+		if err := copyHistory(dst, entry.Note.ID, history); err != nil {
+			return nil, err
+		}
+		stats.Trashed++
+	}
+
+	return stats, nil
+}
+
+// copyHistory stores the history of a note in dst when dst supports it
+func copyHistory(dst ports.NoteRepository, id string, history []*entities.Revision) error {
+	importer, ok := dst.(historyImporter)
+	if !ok || len(history) == 0 {
+		return nil
+	}
+
+	if err := importer.SetRevisions(id, history); err != nil {
+		return fmt.Errorf("failed to copy history of note %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// MergeStats reports what Merge changed
+type MergeStats struct {
+	Added   int
+	Updated int
+	Kept    int
+	Trashed int
+}
+
+// Merge copies the notes of src into dst, deduplicating by ID. A note that
+// exists in both is taken from src only when src updated it later. Trashed
+// notes of src are added when dst supports a trash and knows nothing of the
+// note. New notes keep their history; updated notes get a new revision.
+func Merge(dst, src ports.NoteRepository) (*MergeStats, error) {
+	stats := &MergeStats{}
+
+	notes, err := src.GetAllNotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notes: %w", err)
+	}
+
+	for _, note := range notes {
+		existing, err := dst.GetNoteByID(note.ID)
+		switch {
+		case errors.Is(err, ports.ErrNoteNotFound):
+			history, err := src.GetRevisions(note.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read history of note %s: %w", note.ID, err)
+			}
+			if err := dst.Save(note); err != nil {
+				return nil, fmt.Errorf("failed to merge note %s: %w", note.ID, err)
+			}
+			if err := copyHistory(dst, note.ID, history); err != nil {
+				return nil, err
+			}
+			stats.Added++
+		case err != nil:
+			return nil, fmt.Errorf("failed to read note %s: %w", note.ID, err)
+		case note.UpdatedAt.After(existing.UpdatedAt):
+			if err := dst.Save(note); err != nil {
+				return nil, fmt.Errorf("failed to merge note %s: %w", note.ID, err)
+			}
+			stats.Updated++
+		default:
+			stats.Kept++
+		}
+	}
+
+	importer, ok := dst.(trashImporter)
+	if !ok {
+		return stats, nil
+	}
+
+	trash, err := src.GetTrash()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+	dstTrash, err := dst.GetTrash()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+	trashed := make(map[string]bool, len(dstTrash))
+	for _, entry := range dstTrash {
+		trashed[entry.Note.ID] = true
+	}
+
+	for _, entry := range trash {
+		if trashed[entry.Note.ID] {
+			continue
+		}
+		if _, err := dst.GetNoteByID(entry.Note.ID); !errors.Is(err, ports.ErrNoteNotFound) {
+			if err != nil {
+				return nil, fmt.Errorf("failed to read note %s: %w", entry.Note.ID, err)
+			}
+			continue
+		}
+
+		if err := importer.AddToTrash(entry); err != nil {
+			return nil, fmt.Errorf("failed to merge trashed note %s: %w", entry.Note.ID, err)
+		}
+		stats.Trashed++
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,418 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DoctorIssueKind identifies the kind of problem found by Doctor
+type DoctorIssueKind string
+
+const (
+	IssueUnreadable   DoctorIssueKind = "unreadable"
+	IssueDuplicateID  DoctorIssueKind = "duplicate-id"
+	IssueDateMismatch DoctorIssueKind = "date-mismatch"
+	IssueOrphanedText DoctorIssueKind = "orphaned-txt"
+	IssueSplitDay     DoctorIssueKind = "split-day"
+)
+
+// DoctorIssue describes a single problem found in the notes directory
+type DoctorIssue struct {
+	Kind     DoctorIssueKind
+	Path     string
+	NoteID   string
+	Detail   string
+	Repaired bool
+}
+
+// DoctorReport summarizes a scan of the notes directory
+type DoctorReport struct {
+	FilesScanned int
+	NotesScanned int
+	Issues       []*DoctorIssue
+}
+
+// noteLocation records which day file a note was found in
+type noteLocation struct {
+	date string
+	note *entities.Note
+}
+
+// Doctor scans the notes directory for unreadable files, duplicate IDs,
+// notes whose Date does not match their file, orphaned .txt files and days
+// stored in both the flat and the nested layout. When repair is true it also
+// attempts a best-effort fix of every issue.
+func (r *fileRepository) Doctor(repair bool) (*DoctorReport, error) {
+	report := &DoctorReport{}
+
+	err := r.withLock(func() error {
+		paths, err := r.doctorPaths()
+		if err != nil {
+			return err
+		}
+
+		files := make(map[string][]*entities.Note)
+		sources := make(map[string][]string)
+		dirty := make(map[string]bool)
+		var salvagedFiles, migratedFiles []string
+
+		for _, path := range paths {
+			name := filepath.Base(path)
+			if len(name) < len("2006-01-02") {
+				continue
+			}
+			date := name[:len("2006-01-02")]
+			if _, err := time.Parse("2006-01-02", date); err != nil {
+				continue
+			}
+			suffix := name[len(date):]
+
+			switch {
+			case suffix == ".json":
+				report.FilesScanned++
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return fmt.Errorf("failed to read %s: %w", path, err)
+				}
+				notes, err := decodeNotes(data)
+				if err == nil {
+					sources[date] = append(sources[date], path)
+					if len(sources[date]) == 1 {
+						files[date] = append(files[date], notes...)
+						continue
+					}
+
+					// The same day in both layouts, left by an interrupted conversion
+					issue := &DoctorIssue{Kind: IssueSplitDay, Path: path, Detail: fmt.Sprintf("%s is also stored in %s", date, sources[date][0])}
+					report.Issues = append(report.Issues, issue)
+					files[date] = mergeNotes(files[date], notes)
+					if repair {
+						dirty[date] = true
+						issue.Detail = fmt.Sprintf("%s; merged into one file", issue.Detail)
+						issue.Repaired = true
+					}
+					continue
+				}
+
+				issue := &DoctorIssue{Kind: IssueUnreadable, Path: path, Detail: err.Error()}
+				report.Issues = append(report.Issues, issue)
+				if repair {
+					quarantinePath, err := quarantineFile(path)
+					if err != nil {
+						return fmt.Errorf("failed to quarantine %s: %w", path, err)
+					}
+					salvaged := salvageNotes(data)
+					files[date] = mergeNotes(files[date], salvaged)
+					dirty[date] = true
+					salvagedFiles = append(salvagedFiles, quarantinePath)
+					issue.Detail = fmt.Sprintf("%s; salvaged %d notes", issue.Detail, len(salvaged))
+					issue.Repaired = true
+				}
+
+			case strings.HasPrefix(suffix, ".json.corrupt-") && !strings.HasSuffix(suffix, ".salvaged"):
+				report.FilesScanned++
+				issue := &DoctorIssue{Kind: IssueUnreadable, Path: path, Detail: "quarantined corrupt file"}
+				report.Issues = append(report.Issues, issue)
+				if repair {
+					data, err := os.ReadFile(path)
+					if err != nil {
+						return fmt.Errorf("failed to read %s: %w", path, err)
+					}
+					salvaged := salvageNotes(data)
+					files[date] = mergeNotes(files[date], salvaged)
+					dirty[date] = true
+					salvagedFiles = append(salvagedFiles, path)
+					issue.Detail = fmt.Sprintf("%s; salvaged %d notes", issue.Detail, len(salvaged))
+					issue.Repaired = true
+				}
+
+			case suffix == ".txt":
+				report.FilesScanned++
+				issue := &DoctorIssue{Kind: IssueOrphanedText, Path: path, Detail: "legacy text file not merged into JSON"}
+				report.Issues = append(report.Issues, issue)
+				if repair {
+					notes, err := parseTextNotes(path, date)
+					if err != nil {
+						return err
+					}
+					files[date] = mergeNotes(files[date], notes)
+					dirty[date] = true
+					migratedFiles = append(migratedFiles, path)
+					issue.Detail = fmt.Sprintf("merged %d notes into %s.json", len(notes), date)
+					issue.Repaired = true
+				}
+			}
+		}
+
+		dates := make([]string, 0, len(files))
+		for date, notes := range files {
+			dates = append(dates, date)
+			report.NotesScanned += len(notes)
+		}
+		sort.Strings(dates)
+
+		// Duplicate IDs, within a file or across files
+		byID := make(map[string][]noteLocation)
+		var ids []string
+		for _, date := range dates {
+			for _, note := range files[date] {
+				if _, ok := byID[note.ID]; !ok {
+					ids = append(ids, note.ID)
+				}
+				byID[note.ID] = append(byID[note.ID], noteLocation{date: date, note: note})
+			}
+		}
+
+		for _, id := range ids {
+			locations := byID[id]
+			if len(locations) < 2 {
+				continue
+			}
+
+			var found []string
+			for _, loc := range locations {
+				found = append(found, loc.date+".json")
+			}
+			issue := &DoctorIssue{
+				Kind:   IssueDuplicateID,
+				NoteID: id,
+				Detail: fmt.Sprintf("found %d copies in %s", len(locations), strings.Join(found, ", ")),
+			}
+			report.Issues = append(report.Issues, issue)
+
+			if repair {
+				keeper := pickDuplicateKeeper(locations)
+				newest := locations[0].note
+				for _, loc := range locations {
+					if loc.note.UpdatedAt.After(newest.UpdatedAt) {
+						newest = loc.note
+					}
+					if loc.note == keeper.note {
+						continue
+					}
+					files[loc.date] = removeNote(files[loc.date], loc.note)
+					dirty[loc.date] = true
+				}
+
+				// Keep the most recent content, stored where the note belongs
+				for i, note := range files[keeper.date] {
+					if note == keeper.note {
+						files[keeper.date][i] = newest
+					}
+				}
+				dirty[keeper.date] = true
+				issue.Detail = fmt.Sprintf("%s; kept the copy in %s.json", issue.Detail, keeper.date)
+				issue.Repaired = true
+			}
+		}
+
+		// Notes whose Date does not match the file they live in. A valid Date
+		// is what the user last set, as after an interrupted move, so the
+		// note moves to that day; only an invalid Date is overwritten.
+		var moves []noteLocation
+		for _, date := range dates {
+			for _, note := range files[date] {
+				if note.Date == date {
+					continue
+				}
+				issue := &DoctorIssue{
+					Kind:   IssueDateMismatch,
+					Path:   r.dayFilePath(date),
+					NoteID: note.ID,
+					Detail: fmt.Sprintf("note date %q does not match file date %s", note.Date, date),
+				}
+				report.Issues = append(report.Issues, issue)
+				if !repair {
+					continue
+				}
+
+				if _, err := time.Parse("2006-01-02", note.Date); err != nil {
+					note.Date = date
+					dirty[date] = true
+					issue.Detail = fmt.Sprintf("%s; set the note date to %s", issue.Detail, date)
+				} else {
+					moves = append(moves, noteLocation{date: date, note: note})
+					issue.Detail = fmt.Sprintf("%s; moved the note to %s.json", issue.Detail, note.Date)
+				}
+				issue.Repaired = true
+			}
+		}
+		for _, move := range moves {
+			files[move.date] = removeNote(files[move.date], move.note)
+			files[move.note.Date] = mergeNotes(files[move.note.Date], []*entities.Note{move.note})
+			dirty[move.date] = true
+			dirty[move.note.Date] = true
+		}
+
+		if !repair {
+			return nil
+		}
+
+		for date := range dirty {
+			target := r.dayFilePath(date)
+			if err := r.writeNotesToFile(target, files[date]); err != nil {
+				return fmt.Errorf("failed to write repaired notes: %w", err)
+			}
+
+			// Every note of a split day is now in target
+			for _, path := range sources[date] {
+				if path == target {
+					continue
+				}
+				if err := os.Remove(path); err != nil {
+					return fmt.Errorf("failed to remove %s: %w", path, err)
+				}
+			}
+		}
+
+		// Only retire the source files once their notes are safely written
+		for _, path := range salvagedFiles {
+			if err := os.Rename(path, path+".salvaged"); err != nil {
+				return fmt.Errorf("failed to mark %s as salvaged: %w", path, err)
+			}
+		}
+		for _, path := range migratedFiles {
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", path, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// doctorPaths returns the files of the notes directory and of its YYYY/MM
+// directories, where the nested layout keeps day files
+func (r *fileRepository) doctorPaths() ([]string, error) {
+	entries, err := os.ReadDir(r.notesDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notes directory: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		path := filepath.Join(r.notesDir, entry.Name())
+		if !entry.IsDir() {
+			paths = append(paths, path)
+			continue
+		}
+		if !isDigits(entry.Name(), 4) {
+			continue
+		}
+
+		months, err := filepath.Glob(filepath.Join(path, "[0-9][0-9]", "*"))
+		if err != nil {
+			return nil, err
+		}
+		for _, month := range months {
+			if info, err := os.Stat(month); err == nil && !info.IsDir() {
+				paths = append(paths, month)
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+// decodeNotes decodes the contents of a day file; an empty file holds no notes
+func decodeNotes(data []byte) ([]*entities.Note, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return []*entities.Note{}, nil
+	}
+
+	var notes []*entities.Note
+	if err := json.Unmarshal(data, &notes); err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+// salvageNotes decodes as many complete notes as possible from a damaged
+// day file, stopping at the first note that cannot be decoded
+func salvageNotes(data []byte) []*entities.Note {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	token, err := decoder.Token()
+	if err != nil || token != json.Delim('[') {
+		return nil
+	}
+
+	var notes []*entities.Note
+	for decoder.More() {
+		var note entities.Note
+		if err := decoder.Decode(&note); err != nil {
+			break
+		}
+		if note.ID != "" {
+			notes = append(notes, &note)
+		}
+	}
+
+	return notes
+}
+
+// mergeNotes adds incoming notes to existing ones by ID, keeping whichever
+// copy was updated most recently
+func mergeNotes(existing, incoming []*entities.Note) []*entities.Note {
+	index := make(map[string]int, len(existing))
+	for i, note := range existing {
+		index[note.ID] = i
+	}
+
+	for _, note := range incoming {
+		if i, ok := index[note.ID]; ok {
+			if note.UpdatedAt.After(existing[i].UpdatedAt) {
+				existing[i] = note
+			}
+			continue
+		}
+		index[note.ID] = len(existing)
+		existing = append(existing, note)
+	}
+
+	return existing
+}
+
+// pickDuplicateKeeper chooses which file keeps a duplicated note: a copy
+// stored under its own Date wins, then the most recently updated one
+func pickDuplicateKeeper(locations []noteLocation) noteLocation {
+	keeper := locations[0]
+	for _, loc := range locations[1:] {
+		keeperMatches := keeper.note.Date == keeper.date
+		locMatches := loc.note.Date == loc.date
+		if locMatches != keeperMatches {
+			if locMatches {
+				keeper = loc
+			}
+			continue
+		}
+		if loc.note.UpdatedAt.After(keeper.note.UpdatedAt) {
+			keeper = loc
+		}
+	}
+	return keeper
+}
+
+// removeNote removes a specific note instance from a slice
+func removeNote(notes []*entities.Note, target *entities.Note) []*entities.Note {
+	var kept []*entities.Note
+	for _, note := range notes {
+		if note != target {
+			kept = append(kept, note)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"errors"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// writeDayFile writes raw contents to a day file of the flat layout
+func writeDayFile(t *testing.T, dir, name, contents string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// noteJSON returns the JSON of a note with the given ID, date and update time
+func noteJSON(id, date, updatedAt string) string {
+	return `{"id": "` + id + `", "type": "text", "content": "note ` + id + `", "created_at": "2025-03-10T09:00:00Z", "updated_at": "` +
+		updatedAt + `", "date": "` + date + `", "metadata": {}}`
+}
+
+func TestCorruptDayFileIsSkippedOnReadAndQuarantinedOnWrite(t *testing.T) {
+	dir := t.TempDir()
+	writeDayFile(t, dir, "2025-03-10.json", `[`+noteJSON("n1", "2025-03-10", "2025-03-10T09:00:00Z")+`, {"id": `)
+	writeDayFile(t, dir, "2025-03-11.json", `[`+noteJSON("n2", "2025-03-11", "2025-03-11T09:00:00Z")+`]`)
+	repo := NewFileRepository(dir)
+	var reported []error
+	repo.OnCorruptFile(func(err error) {
+		reported = append(reported, err)
+	})
+
+	// Reads skip the corrupt file, report it once and leave it alone
+	if notes, err := repo.GetNotesByDate("2025-03-10"); err != nil || len(notes) != 0 {
+		t.Fatalf("GetNotesByDate returned %d notes (%v), want none", len(notes), err)
+	}
+	if notes, err := repo.GetAllNotes(); err != nil || len(notes) != 1 {
+		t.Fatalf("GetAllNotes returned %d notes (%v), want the readable one", len(notes), err)
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], ports.ErrCorruptData) {
+		t.Fatalf("reported %v, want the corrupt file once", reported)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2025-03-10.json")); err != nil {
+		t.Fatalf("read moved the corrupt file: %v", err)
+	}
+
+	// A write quarantines it instead of overwriting it
+	note := entities.NewNote("written after the corruption")
+	note.Date = "2025-03-10"
+	err := repo.Save(note)
+	var corrupt *CorruptFileError
+	if !errors.As(err, &corrupt) || !errors.Is(err, ports.ErrCorruptData) {
+		t.Fatalf("Save returned %v, want a CorruptFileError", err)
+	}
+	if !strings.HasPrefix(filepath.Base(corrupt.QuarantinePath), "2025-03-10.json.corrupt-") {
+		t.Errorf("quarantined as %s, want 2025-03-10.json.corrupt-<ts>", corrupt.QuarantinePath)
+	}
+
+	// The next save starts a new day file
+	if err := repo.Save(note); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	data, err := os.ReadFile(corrupt.QuarantinePath)
+	if err != nil || !strings.Contains(string(data), `"n1"`) {
+		t.Errorf("quarantined file lost its contents: %q, %v", data, err)
+	}
+}
+
+func TestSalvageNotes(t *testing.T) {
+	n1 := noteJSON("n1", "2025-03-10", "2025-03-10T09:00:00Z")
+	n2 := noteJSON("n2", "2025-03-10", "2025-03-10T10:00:00Z")
+
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"complete file", "[" + n1 + "," + n2 + "]", []string{"n1", "n2"}},
+		{"truncated in the second note", "[" + n1 + "," + n2[:40], []string{"n1"}},
+		{"garbage after a note", "[" + n1 + ", nonsense]", []string{"n1"}},
+		{"note without an ID", `[{"content": "lost"}, ` + n2 + "]", []string{"n2"}},
+		{"not an array", n1, nil},
+		{"empty", "", nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, note := range salvageNotes([]byte(tt.data)) {
+			got = append(got, note.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: salvaged %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// doctorFixture fills dir with one instance of every issue Doctor finds
+func doctorFixture(t *testing.T, dir string) {
+	t.Helper()
+
+	// Corrupt, with one note that can be salvaged
+	writeDayFile(t, dir, "2025-03-10.json", `[`+noteJSON("salvaged", "2025-03-10", "2025-03-10T09:00:00Z")+`, {"id": `)
+	// A move to 2025-03-12 interrupted before the old file was rewritten,
+	// a note with a broken date and a copy of a note in the wrong file
+	writeDayFile(t, dir, "2025-03-11.json", `[`+
+		noteJSON("moved", "2025-03-12", "2025-03-11T12:00:00Z")+`, `+
+		noteJSON("undated", "someday", "2025-03-11T09:00:00Z")+`, `+
+		noteJSON("dup", "2025-03-12", "2025-03-11T18:00:00Z")+`]`)
+	writeDayFile(t, dir, "2025-03-12.json", `[`+noteJSON("dup", "2025-03-12", "2025-03-11T08:00:00Z")+`]`)
+	writeDayFile(t, dir, "2025-03-13.txt", "[08:30:00] legacy note\n")
+}
+
+func TestDoctorReportsWithoutRepairing(t *testing.T) {
+	dir := t.TempDir()
+	doctorFixture(t, dir)
+	before := dirContents(t, dir)
+
+	report, err := NewFileRepository(dir).Doctor(false)
+	if err != nil {
+		t.Fatalf("Doctor failed: %v", err)
+	}
+
+	kinds := make(map[DoctorIssueKind]int)
+	for _, issue := range report.Issues {
+		kinds[issue.Kind]++
+		if issue.Repaired {
+			t.Errorf("issue %s was repaired without --repair", issue.Kind)
+		}
+	}
+	want := map[DoctorIssueKind]int{IssueUnreadable: 1, IssueDuplicateID: 1, IssueDateMismatch: 3, IssueOrphanedText: 1}
+	for kind, count := range want {
+		if kinds[kind] != count {
+			t.Errorf("found %d %s issues, want %d", kinds[kind], kind, count)
+		}
+	}
+
+	if after := dirContents(t, dir); after != before {
+		t.Errorf("Doctor without repair changed the notes directory from\n%s\nto\n%s", before, after)
+	}
+}
+
+// dirContents returns the names and contents of the files in dir, leaving
+// out the lock file
+func dirContents(t *testing.T, dir string) string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var contents strings.Builder
+	for _, entry := range entries {
+		if entry.Name() == ".lock" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents.WriteString(entry.Name() + ": " + string(data) + "\n")
+	}
+	return contents.String()
+}
+
+func TestDoctorRepairs(t *testing.T) {
+	dir := t.TempDir()
+	doctorFixture(t, dir)
+	repo := NewFileRepository(dir)
+
+	if _, err := repo.Doctor(true); err != nil {
+		t.Fatalf("Doctor repair failed: %v", err)
+	}
+
+	expect := func(date string, want ...string) {
+		t.Helper()
+		notes, err := repo.GetNotesByDate(date)
+		if err != nil {
+			t.Fatalf("GetNotesByDate(%s) failed: %v", date, err)
+		}
+		var got []string
+		for _, note := range notes {
+			got = append(got, note.ID)
+			if note.Date != date {
+				t.Errorf("note %s in %s has date %s", note.ID, date, note.Date)
+			}
+		}
+		sort.Strings(got)
+		sort.Strings(want)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s holds %v, want %v", date, got, want)
+		}
+	}
+	expect("2025-03-10", "salvaged")
+	expect("2025-03-11", "undated")
+	expect("2025-03-12", "moved", "dup")
+	expect("2025-03-13", "2025-03-13-08:30:00")
+
+	// The newest copy of the duplicate is kept where the note belongs
+	dup, err := repo.GetNoteByID("dup")
+	if err != nil {
+		t.Fatalf("GetNoteByID failed: %v", err)
+	}
+	if dup.UpdatedAt.Hour() != 18 {
+		t.Errorf("kept the copy updated at %s, want the newest", dup.UpdatedAt)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "2025-03-13.txt")); !os.IsNotExist(err) {
+		t.Errorf("merged text file was not removed: %v", err)
+	}
+	salvaged, _ := filepath.Glob(filepath.Join(dir, "2025-03-10.json.corrupt-*.salvaged"))
+	if len(salvaged) != 1 {
+		t.Errorf("found %d salvaged corrupt files, want 1", len(salvaged))
+	}
+
+	report, err := repo.Doctor(false)
+	if err != nil {
+		t.Fatalf("Doctor failed: %v", err)
+	}
+	for _, issue := range report.Issues {
+		t.Errorf("issue left after repair: %s %s %s", issue.Kind, issue.NoteID, issue.Detail)
+	}
+}
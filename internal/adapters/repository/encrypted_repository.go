@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"sync"
+)
+
+// sealedFields are the parts of a note that are encrypted. The ID, type,
+// date, timestamps and status stay readable so that notes can still be
+// filed, found and ordered without the key.
+type sealedFields struct {
+	Content  string            `json:"content"`
+	Metadata entities.Metadata `json:"metadata"`
+}
+
+// encryptedRepository seals note content and metadata before they reach the
+// wrapped repository and opens them again on the way out
+type encryptedRepository struct {
+	ports.NoteRepository
+
+	unlock     func() (*Cipher, error)
+	unlockOnce sync.Once
+	cipher     *Cipher
+	unlockErr  error
+}
+
+// NewEncryptedRepository wraps a repository so that notes are stored
+// encrypted. unlock is called once, the first time a note has to be sealed or
+// opened, so commands that never touch note content never ask for the key.
+// Notes that were stored before encryption was enabled are read as they are.
+func NewEncryptedRepository(repository ports.NoteRepository, unlock func() (*Cipher, error)) ports.NoteRepository {
+	return &encryptedRepository{NoteRepository: repository, unlock: unlock}
+}
+
+// getCipher unlocks the repository on first use
+func (r *encryptedRepository) getCipher() (*Cipher, error) {
+	r.unlockOnce.Do(func() {
+		r.cipher, r.unlockErr = r.unlock()
+		if r.unlockErr != nil {
+			r.unlockErr = fmt.Errorf("failed to unlock notes: %w", r.unlockErr)
+		}
+	})
+	return r.cipher, r.unlockErr
+}
+
+// Save seals a note and saves it
+func (r *encryptedRepository) Save(note *entities.Note) error {
+	sealed, err := r.sealUpdate(note)
+	if err != nil {
+		return err
+	}
+
+	return r.NoteRepository.Save(sealed)
+}
+
+// MoveNote seals a note and moves it to a new date
+func (r *encryptedRepository) MoveNote(note *entities.Note, date string) error {
+	sealed, err := r.sealUpdate(note)
+	if err != nil {
+		return err
+	}
+
+	if err := r.NoteRepository.MoveNote(sealed, date); err != nil {
+		return err
+	}
+
+	note.Date = date
+	return nil
+}
+
+// AddToTrash seals a trashed note and stores it, when the wrapped repository supports it
+func (r *encryptedRepository) AddToTrash(entry *entities.TrashedNote) error {
+	importer, ok := r.NoteRepository.(trashImporter)
+	if !ok {
+		return fmt.Errorf("storage does not support importing trashed notes")
+	}
+
+	sealed, err := r.seal(entry.Note)
+	if err != nil {
+		return err
+	}
+
+	return importer.AddToTrash(&entities.TrashedNote{Note: sealed, DeletedAt: entry.DeletedAt})
+}
+
+// GetNoteByID retrieves and opens a note by its exact ID
+func (r *encryptedRepository) GetNoteByID(id string) (*entities.Note, error) {
+	note, err := r.NoteRepository.GetNoteByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.open(note)
+}
+
+// GetNotesByDate retrieves and opens all notes for a specific date
+func (r *encryptedRepository) GetNotesByDate(date string) ([]*entities.Note, error) {
+	return r.openAll(r.NoteRepository.GetNotesByDate(date))
+}
+
+// GetNotesByDateRange retrieves and opens notes within a date range
+func (r *encryptedRepository) GetNotesByDateRange(startDate, endDate string) ([]*entities.Note, error) {
+	return r.openAll(r.NoteRepository.GetNotesByDateRange(startDate, endDate))
+}
+
+// GetTodayNotes retrieves and opens all notes for today
+func (r *encryptedRepository) GetTodayNotes() ([]*entities.Note, error) {
+	return r.openAll(r.NoteRepository.GetTodayNotes())
+}
+
+// GetNotesByMonth retrieves and opens notes for a specific month
+func (r *encryptedRepository) GetNotesByMonth(monthStr string) ([]*entities.Note, error) {
+	return r.openAll(r.NoteRepository.GetNotesByMonth(monthStr))
+}
+
+// GetAllNotes retrieves and opens every note
+func (r *encryptedRepository) GetAllNotes() ([]*entities.Note, error) {
+	return r.openAll(r.NoteRepository.GetAllNotes())
+}
+
+// GetTrash retrieves and opens all notes in the trash
+func (r *encryptedRepository) GetTrash() ([]*entities.TrashedNote, error) {
+	trash, err := r.NoteRepository.GetTrash()
+	if err != nil {
+		return nil, err
+	}
+
+	opened := make([]*entities.TrashedNote, 0, len(trash))
+	for _, entry := range trash {
+		note, err := r.open(entry.Note)
+		if err != nil {
+			return nil, err
+		}
+		opened = append(opened, &entities.TrashedNote{Note: note, DeletedAt: entry.DeletedAt})
+	}
+
+	return opened, nil
+}
+
+// RestoreNote restores a note from the trash and opens it
+func (r *encryptedRepository) RestoreNote(id string) (*entities.Note, error) {
+	note, err := r.NoteRepository.RestoreNote(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.open(note)
+}
+
+// GetRevisions retrieves and opens every saved version of a note
+func (r *encryptedRepository) GetRevisions(id string) ([]*entities.Revision, error) {
+	history, err := r.NoteRepository.GetRevisions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	opened := make([]*entities.Revision, 0, len(history))
+	for _, revision := range history {
+		note, err := r.open(revision.Note)
+		if err != nil {
+			return nil, err
+		}
+		opened = append(opened, &entities.Revision{Number: revision.Number, SavedAt: revision.SavedAt, Note: note})
+	}
+
+	return opened, nil
+}
+
+// SetRevisions seals the history of a note and stores it, when the wrapped
+// repository supports it
+func (r *encryptedRepository) SetRevisions(id string, revisions []*entities.Revision) error {
+	importer, ok := r.NoteRepository.(historyImporter)
+	if !ok {
+		return fmt.Errorf("storage does not support importing note history")
+	}
+
+	sealed := make([]*entities.Revision, 0, len(revisions))
+	for _, revision := range revisions {
+		note, err := r.seal(revision.Note)
+		if err != nil {
+			return err
+		}
+		sealed = append(sealed, &entities.Revision{Number: revision.Number, SavedAt: revision.SavedAt, Note: note})
+	}
+
+	return importer.SetRevisions(id, sealed)
+}
+
+// seal returns a copy of note with its content and metadata encrypted
+func (r *encryptedRepository) seal(note *entities.Note) (*entities.Note, error) {
+	c, err := r.getCipher()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.SealNote(note)
+}
+
+// SealNote returns a copy of note with its content and metadata encrypted.
+// The ID, type, date, timestamps and status stay readable.
+func (c *Cipher) SealNote(note *entities.Note) (*entities.Note, error) {
+	plaintext, err := json.Marshal(sealedFields{Content: note.Content, Metadata: note.Metadata})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode note %s: %w", note.ID, err)
+	}
+
+	blob, err := c.Seal(plaintext, note.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt note %s: %w", note.ID, err)
+	}
+
+	sealed := *note
+	sealed.Content = blob
+	sealed.Metadata = entities.Metadata{Status: note.Metadata.Status}
+
+	return &sealed, nil
+}
+
+// sealUpdate seals a note that is about to replace its stored version. When
+// the content and metadata are unchanged, the stored ciphertext is kept:
+// sealing is randomized, and a new ciphertext would record a revision for a
+// save that only touched the timestamps.
+func (r *encryptedRepository) sealUpdate(note *entities.Note) (*entities.Note, error) {
+	stored, err := r.NoteRepository.GetNoteByID(note.ID)
+	if err != nil || !isSealed(stored.Content) {
+		return r.seal(note)
+	}
+
+	opened, err := r.open(stored)
+	if err != nil {
+		return r.seal(note)
+	}
+
+	before, errBefore := json.Marshal(sealedFields{Content: opened.Content, Metadata: opened.Metadata})
+	after, errAfter := json.Marshal(sealedFields{Content: note.Content, Metadata: note.Metadata})
+	if errBefore != nil || errAfter != nil || !bytes.Equal(before, after) {
+		return r.seal(note)
+	}
+
+	sealed := *note
+	sealed.Content = stored.Content
+	sealed.Metadata = stored.Metadata
+
+	return &sealed, nil
+}
+
+// open returns a copy of note with its content and metadata decrypted.
+// Notes that were never sealed are returned unchanged.
+func (r *encryptedRepository) open(note *entities.Note) (*entities.Note, error) {
+	if !isSealed(note.Content) {
+		return note, nil
+	}
+
+	c, err := r.getCipher()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.OpenNote(note)
+}
+
+// OpenNote returns a copy of note with its content and metadata decrypted.
+// Notes that were never sealed are returned unchanged.
+func (c *Cipher) OpenNote(note *entities.Note) (*entities.Note, error) {
+	if !isSealed(note.Content) {
+		return note, nil
+	}
+
+	plaintext, err := c.Open(note.Content, note.ID)
+	if err != nil {
+		return nil, fmt.Errorf("note %s: %w", note.ID, err)
+	}
+
+	var fields sealedFields
+	if err := json.Unmarshal(plaintext, &fields); err != nil {
+		return nil, fmt.Errorf("note %s: invalid decrypted data: %w", note.ID, err)
+	}
+
+	opened := *note
+	opened.Content = fields.Content
+	opened.Metadata = fields.Metadata
+
+	return &opened, nil
+}
+
+// openAll opens every note of a read result
+func (r *encryptedRepository) openAll(notes []*entities.Note, err error) ([]*entities.Note, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	opened := make([]*entities.Note, 0, len(notes))
+	for _, note := range notes {
+		note, err := r.open(note)
+		if err != nil {
+			return nil, err
+		}
+		opened = append(opened, note)
+	}
+
+	return opened, nil
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"jotterxpress/internal/adapters/fsutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// encryptionFileName holds the key derivation parameters of an
+	// encrypted notes directory
+	encryptionFileName = ".encryption.json"
+
+	// sealedPrefix marks note content that holds a sealed blob
+	sealedPrefix = "jtx-enc:v1:"
+
+	// kdfIterations is the PBKDF2-SHA256 work factor for new notes directories
+	kdfIterations = 600000
+
+	// keyCheck is sealed into the parameters file to verify passphrases
+	keyCheck = "jotterxpress"
+)
+
+// ErrWrongPassphrase is returned when a passphrase or cached key does not
+// match the encrypted notes directory
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+
+// encryptionParams is stored next to the notes. It holds nothing secret,
+// only what is needed to derive and verify the key.
+type encryptionParams struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Check      string `json:"check"`
+}
+
+// Cipher seals and opens data with AES-256-GCM
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a Cipher from a 32-byte key
+func NewCipher(key []byte) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cipher{aead: aead}, nil
+}
+
+// Seal encrypts plaintext bound to the given associated data, such as a
+// note ID, so a blob cannot be moved to another note unnoticed
+func (c *Cipher) Seal(plaintext []byte, associated string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, plaintext, []byte(associated))
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a blob produced by Seal with the same associated data
+func (c *Cipher) Open(blob, associated string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(blob, sealedPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("malformed encrypted data: %w", err)
+	}
+
+	if len(data) < c.aead.NonceSize() {
+		return nil, fmt.Errorf("malformed encrypted data: too short")
+	}
+
+	nonce, ciphertext := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, []byte(associated))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// isSealed reports whether s is a blob produced by Seal
+func isSealed(s string) bool {
+	return strings.HasPrefix(s, sealedPrefix)
+}
+
+// IsSealed reports whether data is a blob produced by Seal
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(sealedPrefix))
+}
+
+// EncryptionEnabled reports whether the notes in dir are encrypted
+func EncryptionEnabled(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, encryptionFileName))
+	return err == nil
+}
+
+// InitEncryption sets up encryption for dir and returns the key derived from
+// the passphrase. Existing notes are not touched.
+func InitEncryption(dir, passphrase string) ([]byte, error) {
+	if EncryptionEnabled(dir) {
+		return nil, fmt.Errorf("notes in %s are already encrypted", dir)
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase cannot be empty")
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	params := &encryptionParams{
+		Version:    1,
+		KDF:        "pbkdf2-sha256",
+		Iterations: kdfIterations,
+		Salt:       salt,
+	}
+
+	key, err := params.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if params.Check, err = c.Seal([]byte(keyCheck), keyCheck); err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := fsutil.WriteFileAtomic(filepath.Join(dir, encryptionFileName), append(data, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to write encryption parameters: %w", err)
+	}
+
+	return key, nil
+}
+
+// DeriveKey derives the key of the encrypted notes in dir from a passphrase
+func DeriveKey(dir, passphrase string) ([]byte, error) {
+	params, err := readEncryptionParams(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := params.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := params.verify(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// VerifyKey checks that a key, for example a cached one, belongs to the
+// encrypted notes in dir
+func VerifyKey(dir string, key []byte) error {
+	params, err := readEncryptionParams(dir)
+	if err != nil {
+		return err
+	}
+
+	return params.verify(key)
+}
+
+// readEncryptionParams reads the key derivation parameters of dir
+func readEncryptionParams(dir string) (*encryptionParams, error) {
+	data, err := os.ReadFile(filepath.Join(dir, encryptionFileName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption parameters: %w", err)
+	}
+
+	var params encryptionParams
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, fmt.Errorf("invalid encryption parameters: %w", err)
+	}
+
+	if params.Version != 1 || params.KDF != "pbkdf2-sha256" {
+		return nil, fmt.Errorf("unsupported encryption parameters (version %d, %s)", params.Version, params.KDF)
+	}
+
+	return &params, nil
+}
+
+// deriveKey stretches a passphrase into a 32-byte key
+func (p *encryptionParams) deriveKey(passphrase string) ([]byte, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, p.Salt, p.Iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	return key, nil
+}
+
+// verify checks a key against the sealed check value
+func (p *encryptionParams) verify(key []byte) error {
+	c, err := NewCipher(key)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := c.Open(p.Check, keyCheck)
+	if err != nil || string(plaintext) != keyCheck {
+		return ErrWrongPassphrase
+	}
+
+	return nil
+}
+
+// ShareEncryption gives dir the key derivation parameters of notesDir, so
+// notes sealed in either open in both with the same passphrase. It fails
+// when dir already has other parameters, or has some and notesDir has none.
+func ShareEncryption(notesDir, dir string) error {
+	if SameEncryption(notesDir, dir) {
+		return nil
+	}
+	if EncryptionEnabled(dir) {
+		if EncryptionEnabled(notesDir) {
+			return fmt.Errorf("%s is encrypted with another passphrase or key", dir)
+		}
+		return fmt.Errorf("%s is encrypted, but these notes are not", dir)
+	}
+
+	data, err := os.ReadFile(filepath.Join(notesDir, encryptionFileName))
+	if err != nil {
+		return fmt.Errorf("failed to read encryption parameters: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(filepath.Join(dir, encryptionFileName), data); err != nil {
+		return fmt.Errorf("failed to write encryption parameters: %w", err)
+	}
+
+	return nil
+}
+
+// SameEncryption reports whether notes can be copied as they are between two
+// notes directories: neither is encrypted, or both use the same key
+// derivation parameters, and so the same passphrase and key
+func SameEncryption(a, b string) bool {
+	paramsA, errA := os.ReadFile(filepath.Join(a, encryptionFileName))
+	paramsB, errB := os.ReadFile(filepath.Join(b, encryptionFileName))
+	if os.IsNotExist(errA) && os.IsNotExist(errB) {
+		return true
+	}
+	return errA == nil && errB == nil && string(paramsA) == string(paramsB)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"fmt"
+	"jotterxpress/internal/domain/ports"
+)
+
+// CorruptFileError reports a day file that could not be decoded. A write
+// renames the file to QuarantinePath so that it never overwrites it; reads
+// leave QuarantinePath empty and the file in place.
+type CorruptFileError struct {
+	Path           string
+	QuarantinePath string
+	Err            error
+}
+
+func (e *CorruptFileError) Error() string {
+	if e.QuarantinePath == "" {
+		return fmt.Sprintf("%s is corrupt (%v) and was skipped, run 'jtx doctor' to inspect and repair it", e.Path, e.Err)
+	}
+	return fmt.Sprintf("%s is corrupt (%v); it was moved to %s, run 'jtx doctor' to inspect and repair it",
+		e.Path, e.Err, e.QuarantinePath)
+}
+
+// Unwrap lets callers match both ports.ErrCorruptData and the decode error
+func (e *CorruptFileError) Unwrap() []error {
+	return []error{ports.ErrCorruptData, e.Err}
+}
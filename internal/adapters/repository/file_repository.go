@@ -3,18 +3,31 @@ package repository
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"jotterxpress/internal/adapters/fsutil"
 	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
+const (
+	// maxParallelReads bounds how many day files are read at the same time
+	maxParallelReads = 8
+)
+
 // fileRepository implements the NoteRepository interface using file system
 type fileRepository struct {
 	notesDir string
+
+	// onCorrupt is told about corrupt day files that reads skip, once each
+	onCorrupt func(error)
+	reported  sync.Map
 }
 
 // NewFileRepository creates a new file-based repository
@@ -24,6 +37,13 @@ func NewFileRepository(notesDir string) *fileRepository {
 	}
 }
 
+// OnCorruptFile sets the function told about corrupt day files. Reads skip
+// them and leave them in place; only writes, which hold the lock, quarantine
+// them.
+func (r *fileRepository) OnCorruptFile(report func(error)) {
+	r.onCorrupt = report
+}
+
 // GetNotesDir returns the notes directory path
 func (r *fileRepository) GetNotesDir() string {
 	return r.notesDir
@@ -31,37 +51,53 @@ func (r *fileRepository) GetNotesDir() string {
 
 // Save saves a note to a file
 func (r *fileRepository) Save(note *entities.Note) error {
-	// Ensure notes directory exists
-	if err := os.MkdirAll(r.notesDir, 0755); err != nil {
-		return fmt.Errorf("failed to create notes directory: %w", err)
-	}
+	return r.withLock(func() error {
+		return r.saveLocked(note)
+	})
+}
 
+// saveLocked saves a note to its day file; the caller must hold the lock
+func (r *fileRepository) saveLocked(note *entities.Note) error {
 	// Create filename based on date
-	filename := fmt.Sprintf("%s.json", note.Date)
-	filepath := filepath.Join(r.notesDir, filename)
+	filepath := r.dayFilePath(note.Date)
 
 	// Read existing notes
-	notes, err := r.readNotesFromFile(filepath, note.Date)
+	notes, err := r.readNotesLocked(filepath, note.Date)
 	if err != nil {
 		return fmt.Errorf("failed to read existing notes: %w", err)
 	}
 
 	// Check if note already exists (by ID) and update it, otherwise add as new
 	found := false
+	var previous *entities.Note
 	for i, existingNote := range notes {
 		if existingNote.ID == note.ID {
 			// Update existing note
+			previous = existingNote
 			notes[i] = note
 			found = true
 			break
 		}
 	}
 
-	// If note doesn't exist, add it as new
+	// A note saved with a new date moves out of the day file of its old one;
+	// otherwise it is added as new
 	if !found {
+		_, fromDate, err := r.locateNote(note.ID)
+		if err != nil {
+			return err
+		}
+		if fromDate != "" && fromDate != note.Date {
+			return r.moveLocked(note, fromDate)
+		}
 		notes = append(notes, note)
 	}
 
+	// Keep the new version in the note's history before replacing the old one
+	if err := r.recordRevision(previous, note); err != nil {
+		return err
+	}
+
 	// Write all notes back to file
 	if err := r.writeNotesToFile(filepath, notes); err != nil {
 		return fmt.Errorf("failed to write notes to file: %w", err)
@@ -70,42 +106,142 @@ func (r *fileRepository) Save(note *entities.Note) error {
 	return nil
 }
 
+// MoveNote stores a note under a new date and removes it from the day file
+// of its old date
+func (r *fileRepository) MoveNote(note *entities.Note, date string) error {
+	return r.withLock(func() error {
+		index, err := r.loadIndex()
+		if err != nil {
+			return err
+		}
+
+		fromDate, ok := index.dateOf(note.ID)
+		if !ok {
+			return fmt.Errorf("note %s: %w", note.ID, ports.ErrNoteNotFound)
+		}
+
+		note.Date = date
+		if fromDate == date {
+			return r.saveLocked(note)
+		}
+		return r.moveLocked(note, fromDate)
+	})
+}
+
+// moveLocked saves a note to its day file and removes it from the day file
+// of fromDate. The new day file is written first, so an interrupted move
+// leaves a duplicate for jtx doctor rather than losing the note. The caller
+// must hold the lock.
+func (r *fileRepository) moveLocked(note *entities.Note, fromDate string) error {
+	fromPath := r.dayFilePath(fromDate)
+	oldNotes, err := r.readNotesLocked(fromPath, fromDate)
+	if err != nil {
+		return fmt.Errorf("failed to read notes for %s: %w", fromDate, err)
+	}
+
+	var previous *entities.Note
+	remaining := make([]*entities.Note, 0, len(oldNotes))
+	for _, existing := range oldNotes {
+		if existing.ID == note.ID {
+			previous = existing
+			continue
+		}
+		remaining = append(remaining, existing)
+	}
+
+	toPath := r.dayFilePath(note.Date)
+	notes, err := r.readNotesLocked(toPath, note.Date)
+	if err != nil {
+		return fmt.Errorf("failed to read notes for %s: %w", note.Date, err)
+	}
+	notes = append(notes, note)
+
+	if err := r.recordRevision(previous, note); err != nil {
+		return err
+	}
+
+	if err := r.writeNotesToFile(toPath, notes); err != nil {
+		return fmt.Errorf("failed to write notes to file: %w", err)
+	}
+	if err := r.writeNotesToFile(fromPath, remaining); err != nil {
+		return fmt.Errorf("failed to write notes to file: %w", err)
+	}
+
+	return nil
+}
+
+// GetNoteByID retrieves a note by its exact ID using the ID index. The day
+// files are only rescanned for notes the persisted index does not hold,
+// such as ones added by a git pull.
+func (r *fileRepository) GetNoteByID(id string) (*entities.Note, error) {
+	note, _, err := r.locateNote(id)
+	if err != nil || note != nil {
+		return note, err
+	}
+
+	index, err := r.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	date, ok := index.dateOf(id)
+	if !ok {
+		return nil, fmt.Errorf("note %s: %w", id, ports.ErrNoteNotFound)
+	}
+
+	notes, err := r.GetNotesByDate(date)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, note := range notes {
+		if note.ID == id {
+			return note, nil
+		}
+	}
+
+	return nil, fmt.Errorf("note %s: %w", id, ports.ErrNoteNotFound)
+}
+
+// FindNoteIDs returns the IDs of all notes whose ID starts with prefix
+func (r *fileRepository) FindNoteIDs(prefix string) ([]string, error) {
+	index, err := r.loadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	return index.idsWithPrefix(prefix), nil
+}
+
 // GetNotesByDate retrieves all notes for a specific date
 func (r *fileRepository) GetNotesByDate(date string) ([]*entities.Note, error) {
-	filename := fmt.Sprintf("%s.json", date)
-	filepath := filepath.Join(r.notesDir, filename)
-
-	return r.readNotesFromFile(filepath, date)
+	return r.readNotesOrSkip(r.dayFilePath(date), date)
 }
 
-// GetNotesByDateRange retrieves notes within a date range
+// GetNotesByDateRange retrieves notes within a date range. The day files
+// that exist are found by listing the notes directory, and the year and
+// month directories in range for the nested layout, then read concurrently.
 func (r *fileRepository) GetNotesByDateRange(startDate, endDate string) ([]*entities.Note, error) {
-	start, err := time.Parse("2006-01-02", startDate)
-	if err != nil {
+	if _, err := time.Parse("2006-01-02", startDate); err != nil {
 		return nil, fmt.Errorf("invalid start date format: %w", err)
 	}
 
-	end, err := time.Parse("2006-01-02", endDate)
-	if err != nil {
+	if _, err := time.Parse("2006-01-02", endDate); err != nil {
 		return nil, fmt.Errorf("invalid end date format: %w", err)
 	}
 
-	var allNotes []*entities.Note
+	files, err := r.listDayFilesBetween(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
 
-	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
-		dateStr := d.Format("2006-01-02")
-		notes, err := r.GetNotesByDate(dateStr)
-		if err != nil {
-			// Skip files that don't exist
-			continue
-		}
-		allNotes = append(allNotes, notes...)
+	allNotes, err := r.readDayFiles(files)
+	if err != nil {
+		return nil, err
 	}
 
 	// Sort notes by update time (most recently updated first)
-	sort.Slice(allNotes, func(i, j int) bool {
-		return allNotes[i].UpdatedAt.After(allNotes[j].UpdatedAt)
-	})
+	sortByUpdatedAt(allNotes)
 
 	return allNotes, nil
 }
@@ -116,7 +252,8 @@ func (r *fileRepository) GetTodayNotes() ([]*entities.Note, error) {
 	return r.GetNotesByDate(today)
 }
 
-// GetNotesByMonth retrieves notes for a specific month
+// GetNotesByMonth retrieves notes for a specific month; in the nested layout
+// that reads a single month directory
 // monthStr format: "2025-10" (YYYY-MM)
 func (r *fileRepository) GetNotesByMonth(monthStr string) ([]*entities.Note, error) {
 	// Parse the month string to get the first and last day of the month
@@ -139,34 +276,461 @@ func (r *fileRepository) GetNotesByMonth(monthStr string) ([]*entities.Note, err
 	)
 }
 
-// DeleteNote deletes a note by ID (not implemented for file-based storage)
+// GetAllNotes retrieves every note in the repository
+func (r *fileRepository) GetAllNotes() ([]*entities.Note, error) {
+	files, err := r.listDayFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	allNotes, err := r.readDayFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	sortByUpdatedAt(allNotes)
+	return allNotes, nil
+}
+
+// DeleteNote removes a note from its day file and moves it to the trash
 func (r *fileRepository) DeleteNote(id string) error {
-	return fmt.Errorf("delete operation not supported in file-based repository")
+	return r.withLock(func() error {
+		return r.deleteLocked(id)
+	})
+}
+
+// deleteLocked moves a note to the trash; the caller must hold the lock
+func (r *fileRepository) deleteLocked(id string) error {
+	index, err := r.loadIndex()
+	if err != nil {
+		return err
+	}
+
+	date, ok := index.dateOf(id)
+	if !ok {
+		return fmt.Errorf("note %s: %w", id, ports.ErrNoteNotFound)
+	}
+
+	filepath := r.dayFilePath(date)
+	notes, err := r.readNotesLocked(filepath, date)
+	if err != nil {
+		return fmt.Errorf("failed to read notes for %s: %w", date, err)
+	}
+
+	for i, note := range notes {
+		if note.ID != id {
+			continue
+		}
+
+		// Write the trash first so a failure never loses the note
+		trash, err := r.readTrash()
+		if err != nil {
+			return err
+		}
+		trash = append(trash, &entities.TrashedNote{Note: note, DeletedAt: time.Now()})
+		if err := r.writeTrash(trash); err != nil {
+			return err
+		}
+
+		remaining := append(notes[:i:i], notes[i+1:]...)
+		if err := r.writeNotesToFile(filepath, remaining); err != nil {
+			return fmt.Errorf("failed to write notes to file: %w", err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("note %s: %w", id, ports.ErrNoteNotFound)
+}
+
+// GetTrash retrieves all trashed notes, most recently deleted first
+func (r *fileRepository) GetTrash() ([]*entities.TrashedNote, error) {
+	trash, err := r.readTrash()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(trash, func(i, j int) bool {
+		return trash[i].DeletedAt.After(trash[j].DeletedAt)
+	})
+
+	return trash, nil
 }
 
-// readNotesFromFile reads notes from a specific file
+// RestoreNote moves a note from the trash back into its day file
+func (r *fileRepository) RestoreNote(id string) (*entities.Note, error) {
+	var restored *entities.Note
+	err := r.withLock(func() error {
+		trash, err := r.readTrash()
+		if err != nil {
+			return err
+		}
+
+		for i, entry := range trash {
+			if entry.Note.ID != id {
+				continue
+			}
+
+			// Save the note first so a failure never loses it
+			if err := r.saveLocked(entry.Note); err != nil {
+				return err
+			}
+
+			remaining := append(trash[:i:i], trash[i+1:]...)
+			if err := r.writeTrash(remaining); err != nil {
+				return err
+			}
+			restored = entry.Note
+			return nil
+		}
+
+		return fmt.Errorf("trashed note %s: %w", id, ports.ErrNoteNotFound)
+	})
+
+	return restored, err
+}
+
+// EmptyTrash permanently removes trashed notes deleted before the given time
+func (r *fileRepository) EmptyTrash(before time.Time) (int, error) {
+	removed := 0
+	err := r.withLock(func() error {
+		trash, err := r.readTrash()
+		if err != nil {
+			return err
+		}
+
+		var kept, expired []*entities.TrashedNote
+		for _, entry := range trash {
+			if entry.DeletedAt.Before(before) {
+				expired = append(expired, entry)
+				continue
+			}
+			kept = append(kept, entry)
+		}
+
+		if len(expired) == 0 {
+			return nil
+		}
+
+		if err := r.writeTrash(kept); err != nil {
+			return err
+		}
+		removed = len(expired)
+
+		for _, entry := range expired {
+			if err := r.removeHistory(entry.Note.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return removed, err
+}
+
+// AddToTrash stores an already trashed note, keeping its deletion time
+func (r *fileRepository) AddToTrash(entry *entities.TrashedNote) error {
+	return r.withLock(func() error {
+		trash, err := r.readTrash()
+		if err != nil {
+			return err
+		}
+
+		var kept []*entities.TrashedNote
+		for _, existing := range trash {
+			if existing.Note.ID != entry.Note.ID {
+				kept = append(kept, existing)
+			}
+		}
+
+		return r.writeTrash(append(kept, entry))
+	})
+}
+
+// withLock runs fn while holding the exclusive lock on the notes directory,
+// so a whole read-modify-write cycle is never interleaved with another writer
+func (r *fileRepository) withLock(fn func() error) error {
+	if err := os.MkdirAll(r.notesDir, 0755); err != nil {
+		return fmt.Errorf("failed to create notes directory: %w", err)
+	}
+
+	unlock, err := fsutil.LockFile(filepath.Join(r.notesDir, ".lock"))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return fn()
+}
+
+// dayFile describes an existing day file
+type dayFile struct {
+	date string
+	path string
+}
+
+// listDayFiles returns all existing day files sorted by date
+func (r *fileRepository) listDayFiles() ([]dayFile, error) {
+	return r.listDayFilesBetween("", "")
+}
+
+// listDayFilesBetween returns the existing day files from startDate to
+// endDate inclusive, sorted by date, with one file per date. An empty bound is
+// open. Only names are read, and year and month directories outside the range
+// are never opened.
+func (r *fileRepository) listDayFilesBetween(startDate, endDate string) ([]dayFile, error) {
+	files, err := r.scanDayFiles(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// A date with a day file in both layouts is read from the nested one,
+	// like dayFilePath does
+	unique := files[:0]
+	for _, file := range files {
+		if n := len(unique); n > 0 && unique[n-1].date == file.date {
+			if file.path == r.nestedDayFilePath(file.date) {
+				unique[n-1] = file
+			}
+			continue
+		}
+		unique = append(unique, file)
+	}
+
+	return unique, nil
+}
+
+// scanDayFiles returns the day files from startDate to endDate inclusive in
+// both layouts, sorted by date. A date can appear twice.
+func (r *fileRepository) scanDayFiles(startDate, endDate string) ([]dayFile, error) {
+	names, err := readDirNames(r.notesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dates in YYYY-MM-DD order the same way as strings, and so do their
+	// year and month prefixes
+	inRange := func(prefix string) bool {
+		return (startDate == "" || prefix >= startDate[:min(len(prefix), len(startDate))]) &&
+			(endDate == "" || prefix <= endDate[:min(len(prefix), len(endDate))])
+	}
+
+	var files []dayFile
+	addDayFiles := func(dir string, names []string, month string) {
+		for _, name := range names {
+			date, ok := strings.CutSuffix(name, ".json")
+			if !ok || len(date) != len("2006-01-02") || !inRange(date) {
+				continue
+			}
+			if month != "" && date[:7] != month {
+				continue
+			}
+			if _, err := time.Parse("2006-01-02", date); err != nil {
+				continue
+			}
+
+			files = append(files, dayFile{date: date, path: filepath.Join(dir, name)})
+		}
+	}
+	addDayFiles(r.notesDir, names, "")
+
+	for _, year := range names {
+		if !isDigits(year, 4) || !inRange(year) {
+			continue
+		}
+
+		yearDir := filepath.Join(r.notesDir, year)
+		months, err := readDirNames(yearDir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, month := range months {
+			if !isDigits(month, 2) || !inRange(year+"-"+month) {
+				continue
+			}
+
+			monthDir := filepath.Join(yearDir, month)
+			days, err := readDirNames(monthDir)
+			if err != nil {
+				return nil, err
+			}
+			addDayFiles(monthDir, days, year+"-"+month)
+		}
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].date < files[j].date
+	})
+	return files, nil
+}
+
+// readDirNames returns the names in a directory; a missing directory or a
+// file in its place is empty
+func readDirNames(path string) ([]string, error) {
+	dir, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read notes directory: %w", err)
+	}
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		if info, statErr := dir.Stat(); statErr == nil && !info.IsDir() {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read notes directory: %w", err)
+	}
+
+	return names, nil
+}
+
+// isDigits reports whether s consists of exactly n ASCII digits
+func isDigits(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// readDayFiles reads day files concurrently, with at most maxParallelReads
+// readers, and returns their notes in file order. The first error in file
+// order is returned.
+func (r *fileRepository) readDayFiles(files []dayFile) ([]*entities.Note, error) {
+	results := make([][]*entities.Note, len(files))
+	errs := make([]error, len(files))
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(maxParallelReads, len(files)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i], errs[i] = r.readNotesOrSkip(files[i].path, files[i].date)
+			}
+		}()
+	}
+	for i := range files {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var notes []*entities.Note
+	for i := range files {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		notes = append(notes, results[i]...)
+	}
+
+	return notes, nil
+}
+
+// trashFilePath returns the path of the trash file
+func (r *fileRepository) trashFilePath() string {
+	return filepath.Join(r.notesDir, ".trash.json")
+}
+
+// readTrash reads all trashed notes
+func (r *fileRepository) readTrash() ([]*entities.TrashedNote, error) {
+	data, err := os.ReadFile(r.trashFilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*entities.TrashedNote{}, nil
+		}
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	var trash []*entities.TrashedNote
+	if err := json.Unmarshal(data, &trash); err != nil {
+		return nil, fmt.Errorf("failed to decode trash: %w", err)
+	}
+
+	return trash, nil
+}
+
+// writeTrash writes all trashed notes
+func (r *fileRepository) writeTrash(trash []*entities.TrashedNote) error {
+	data, err := json.MarshalIndent(trash, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode trash: %w", err)
+	}
+
+	if err := fsutil.WriteFileAtomic(r.trashFilePath(), data); err != nil {
+		return fmt.Errorf("failed to write trash: %w", err)
+	}
+
+	return nil
+}
+
+// readNotesFromFile reads notes from a specific file. Callers go through
+// readNotesLocked or readNotesOrSkip, which decide what a corrupt file means.
 func (r *fileRepository) readNotesFromFile(filepath, date string) ([]*entities.Note, error) {
-	file, err := os.Open(filepath)
+	data, err := os.ReadFile(filepath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return []*entities.Note{}, nil
 		}
 		return nil, fmt.Errorf("failed to open file %s: %w", filepath, err)
 	}
-	defer file.Close()
 
-	var notes []*entities.Note
-	decoder := json.NewDecoder(file)
+	notes, err := decodeNotes(data)
+	if err != nil {
+		return nil, &CorruptFileError{Path: filepath, Err: err}
+	}
+
+	sortNotes(notes)
+
+	return notes, nil
+}
+
+// readNotesLocked reads the notes of a day file about to be written. A
+// corrupt file is quarantined first, since treating it as empty would let
+// the write destroy it. The caller must hold the lock.
+func (r *fileRepository) readNotesLocked(path, date string) ([]*entities.Note, error) {
+	notes, err := r.readNotesFromFile(path, date)
+	var corrupt *CorruptFileError
+	if !errors.As(err, &corrupt) {
+		return notes, err
+	}
+
+	quarantinePath, qerr := quarantineFile(path)
+	if qerr != nil {
+		return nil, fmt.Errorf("%s is corrupt (%v) and could not be quarantined: %w", path, corrupt.Err, qerr)
+	}
+	corrupt.QuarantinePath = quarantinePath
+	return nil, corrupt
+}
+
+// readNotesOrSkip reads the notes of a day file for reading only. A corrupt
+// file is reported and skipped; it stays in place for the next write or
+// jtx doctor to deal with under the lock.
+func (r *fileRepository) readNotesOrSkip(path, date string) ([]*entities.Note, error) {
+	notes, err := r.readNotesFromFile(path, date)
+	var corrupt *CorruptFileError
+	if !errors.As(err, &corrupt) {
+		return notes, err
+	}
 
-	// Try to decode as array first (new format)
-	if err := decoder.Decode(&notes); err != nil {
-		// If that fails, try to migrate from old text format
-		return r.migrateFromTextFormat(filepath, date)
+	if _, seen := r.reported.LoadOrStore(path, true); !seen && r.onCorrupt != nil {
+		r.onCorrupt(corrupt)
 	}
+	return []*entities.Note{}, nil
+}
 
-	// Sort notes with custom logic:
-	// 1. Pending reminders first (NoteTypeReminder with status pending)
-	// 2. Rest sorted by UpdatedAt (most recently updated first)
+// sortNotes sorts notes with custom logic:
+// 1. Pending reminders first (NoteTypeReminder with status pending)
+// 2. Rest sorted by UpdatedAt (most recently updated first)
+func sortNotes(notes []*entities.Note) {
 	sort.Slice(notes, func(i, j int) bool {
 		ni := notes[i]
 		nj := notes[j]
@@ -192,32 +756,36 @@ func (r *fileRepository) readNotesFromFile(filepath, date string) ([]*entities.N
 		// For non-reminders or completed reminders, sort by UpdatedAt
 		return ni.UpdatedAt.After(nj.UpdatedAt)
 	})
+}
 
-	return notes, nil
+// sortByUpdatedAt sorts notes by update time (most recently updated first)
+func sortByUpdatedAt(notes []*entities.Note) {
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].UpdatedAt.After(notes[j].UpdatedAt)
+	})
 }
 
 // writeNotesToFile writes notes to a specific file
 func (r *fileRepository) writeNotesToFile(filepath string, notes []*entities.Note) error {
-	file, err := os.Create(filepath)
-	if err != nil {
-		return fmt.Errorf("failed to create file %s: %w", filepath, err)
+	if notes == nil {
+		notes = []*entities.Note{}
 	}
-	defer file.Close()
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-
-	if err := encoder.Encode(notes); err != nil {
+	data, err := json.MarshalIndent(notes, "", "  ")
+	if err != nil {
 		return fmt.Errorf("failed to encode notes to JSON: %w", err)
 	}
 
+	if err := fsutil.WriteFileAtomic(filepath, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filepath, err)
+	}
+
+	r.indexDayFile(filepath, notes)
 	return nil
 }
 
-// migrateFromTextFormat migrates old text format to JSON format
-func (r *fileRepository) migrateFromTextFormat(filepath, date string) ([]*entities.Note, error) {
-	// Try to read as text file
-	textFilepath := strings.Replace(filepath, ".json", ".txt", 1)
+// parseTextNotes reads notes stored in the old "[HH:MM:SS] content" text format
+func parseTextNotes(textFilepath, date string) ([]*entities.Note, error) {
 	file, err := os.Open(textFilepath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -243,8 +811,8 @@ func (r *fileRepository) migrateFromTextFormat(filepath, date string) ([]*entiti
 				timeStr := strings.TrimPrefix(parts[0], "[")
 				content := parts[1]
 
-				// Parse time
-				createdAt, err := time.Parse("15:04:05", timeStr)
+				// The file name holds the date and the line the local time
+				createdAt, err := time.ParseInLocation("2006-01-02 15:04:05", date+" "+timeStr, time.Local)
 				if err != nil {
 					continue // Skip malformed lines
 				}
@@ -269,14 +837,15 @@ func (r *fileRepository) migrateFromTextFormat(filepath, date string) ([]*entiti
 		return nil, fmt.Errorf("error reading text file: %w", err)
 	}
 
-	// Write notes in new JSON format
-	if len(notes) > 0 {
-		if err := r.writeNotesToFile(filepath, notes); err != nil {
-			return nil, fmt.Errorf("failed to migrate notes to JSON: %w", err)
-		}
-		// Remove old text file
-		os.Remove(textFilepath)
-	}
-
 	return notes, nil
 }
+
+// quarantineFile renames a corrupt file out of the way so it is never
+// overwritten, returning its new path
+func quarantineFile(path string) (string, error) {
+	quarantinePath := fmt.Sprintf("%s.corrupt-%s", path, time.Now().Format("20060102T150405"))
+	if err := os.Rename(path, quarantinePath); err != nil {
+		return "", err
+	}
+	return quarantinePath, nil
+}
@@ -0,0 +1,435 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileRepositoryConcurrentSavesLoseNoNotes(t *testing.T) {
+	dir := t.TempDir()
+	date := time.Now().Format("2006-01-02")
+
+	const writers = 16
+	const notesPerWriter = 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*notesPerWriter)
+
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			// Each writer gets its own repository, like a separate jtx process
+			repo := NewFileRepository(dir)
+			for i := 0; i < notesPerWriter; i++ {
+				note := entities.NewNote(fmt.Sprintf("note %d from writer %d", i, w))
+				note.ID = fmt.Sprintf("w%02d-n%02d", w, i)
+				note.Date = date
+				if err := repo.Save(note); err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	notes, err := NewFileRepository(dir).GetNotesByDate(date)
+	if err != nil {
+		t.Fatalf("GetNotesByDate failed: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, note := range notes {
+		seen[note.ID] = true
+	}
+	for w := 0; w < writers; w++ {
+		for i := 0; i < notesPerWriter; i++ {
+			id := fmt.Sprintf("w%02d-n%02d", w, i)
+			if !seen[id] {
+				t.Errorf("note %s was lost", id)
+			}
+		}
+	}
+	if len(notes) != writers*notesPerWriter {
+		t.Errorf("got %d notes, want %d", len(notes), writers*notesPerWriter)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Errorf("temporary file %s was left behind", entry.Name())
+		}
+	}
+}
+
+// writeArchive fills dir with a synthetic archive of day files covering the
+// given number of years, with a few notes on one day out of four
+func writeArchive(b *testing.B, dir string, start time.Time, years int) {
+	b.Helper()
+
+	repo := NewFileRepository(dir)
+	end := start.AddDate(years, 0, 0)
+	for d, i := start, 0; d.Before(end); d, i = d.AddDate(0, 0, 1), i+1 {
+		if i%4 != 0 {
+			continue
+		}
+
+		date := d.Format("2006-01-02")
+		var notes []*entities.Note
+		for n := 0; n < 3; n++ {
+			note := entities.NewNote(fmt.Sprintf("note %d of %s", n, date))
+			note.ID = fmt.Sprintf("%s-%d", date, n)
+			note.Date = date
+			notes = append(notes, note)
+		}
+
+		if err := repo.writeNotesToFile(repo.dayFilePath(date), notes); err != nil {
+			b.Fatalf("writeNotesToFile failed: %v", err)
+		}
+	}
+}
+
+// getNotesDayByDay is the former range read, which opened one file per
+// calendar day; it is the baseline for the range benchmarks
+func getNotesDayByDay(r *fileRepository, startDate, endDate string) ([]*entities.Note, error) {
+	start, _ := time.Parse("2006-01-02", startDate)
+	end, _ := time.Parse("2006-01-02", endDate)
+
+	var allNotes []*entities.Note
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		notes, err := r.GetNotesByDate(d.Format("2006-01-02"))
+		if err != nil {
+			return nil, err
+		}
+		allNotes = append(allNotes, notes...)
+	}
+
+	sortByUpdatedAt(allNotes)
+	return allNotes, nil
+}
+
+func BenchmarkGetNotesByDateRange(b *testing.B) {
+	dir := b.TempDir()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	writeArchive(b, dir, start, 5)
+	repo := NewFileRepository(dir)
+
+	ranges := []struct {
+		name       string
+		start, end string
+	}{
+		{"month", "2022-06-01", "2022-06-30"},
+		{"year", "2022-01-01", "2022-12-31"},
+		{"five-years", "2020-01-01", "2024-12-31"},
+	}
+
+	for _, rg := range ranges {
+		b.Run(rg.name+"/range-read", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.GetNotesByDateRange(rg.start, rg.end); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(rg.name+"/day-by-day", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := getNotesDayByDay(repo, rg.start, rg.end); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestGetNotesByDateRangeMatchesDayByDay(t *testing.T) {
+	dir := t.TempDir()
+	repo := NewFileRepository(dir)
+
+	for _, date := range []string{"2023-12-31", "2024-01-01", "2024-01-15", "2024-02-01", "2024-06-30"} {
+		note := entities.NewNote("note of " + date)
+		note.Date = date
+		if err := repo.Save(note); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	// Ranges of any length are read from the directory listing
+	ranges := []struct {
+		start, end string
+		want       int
+	}{
+		{"2024-01-15", "2024-01-15", 1},
+		{"2023-12-31", "2024-01-06", 2},
+		{"2024-01-01", "2024-01-31", 2},
+		{"2024-01-01", "2024-12-31", 4},
+		{"2020-01-01", "2023-12-30", 0},
+	}
+
+	for _, rg := range ranges {
+		got, err := repo.GetNotesByDateRange(rg.start, rg.end)
+		if err != nil {
+			t.Fatalf("GetNotesByDateRange(%s, %s) failed: %v", rg.start, rg.end, err)
+		}
+		want, err := getNotesDayByDay(repo, rg.start, rg.end)
+		if err != nil {
+			t.Fatalf("day-by-day read failed: %v", err)
+		}
+
+		if len(got) != rg.want || len(want) != rg.want {
+			t.Fatalf("%s to %s: got %d notes, day by day %d, want %d", rg.start, rg.end, len(got), len(want), rg.want)
+		}
+		for i := range got {
+			if got[i].ID != want[i].ID {
+				t.Errorf("%s to %s: note %d is %s, want %s", rg.start, rg.end, i, got[i].ID, want[i].ID)
+			}
+		}
+	}
+}
+
+func TestConvertLayoutKeepsEveryNote(t *testing.T) {
+	dir := t.TempDir()
+	repo := NewFileRepository(dir)
+
+	dates := []string{"2023-12-31", "2024-01-01", "2024-01-15", "2024-02-01"}
+	for _, date := range dates {
+		note := entities.NewNote("note of " + date)
+		note.Date = date
+		if err := repo.Save(note); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	// A day split across both layouts by an interrupted conversion
+	leftover := entities.NewNote("leftover")
+	leftover.Date = "2024-01-15"
+
+	if err := repo.writeNotesToFile(repo.nestedDayFilePath("2024-01-15"), []*entities.Note{leftover}); err != nil {
+		t.Fatalf("writeNotesToFile failed: %v", err)
+	}
+
+	for i, layout := range []string{LayoutNested, LayoutFlat, LayoutNested} {
+		report, err := repo.ConvertLayout(layout)
+		if err != nil {
+			t.Fatalf("ConvertLayout(%s) failed: %v", layout, err)
+		}
+		if repo.Layout() != layout {
+			t.Errorf("layout is %s after converting to %s", repo.Layout(), layout)
+		}
+		if i == 0 && report.Merged != 1 {
+			t.Errorf("converting to %s merged %d day files, want 1", layout, report.Merged)
+		}
+
+		for _, date := range dates {
+			if _, err := os.Stat(repo.layoutDayFilePath(layout, date)); err != nil {
+				t.Errorf("%s layout: %v", layout, err)
+			}
+		}
+
+		all, err := repo.GetAllNotes()
+		if err != nil {
+			t.Fatalf("GetAllNotes failed: %v", err)
+		}
+		if len(all) != len(dates)+1 {
+			t.Errorf("%s layout: got %d notes, want %d", layout, len(all), len(dates)+1)
+		}
+
+		month, err := repo.GetNotesByMonth("2024-01")
+		if err != nil {
+			t.Fatalf("GetNotesByMonth failed: %v", err)
+		}
+		if len(month) != 3 {
+			t.Errorf("%s layout: got %d notes in 2024-01, want 3", layout, len(month))
+		}
+
+		year, err := repo.GetNotesByDateRange("2024-01-01", "2024-12-31")
+		if err != nil {
+			t.Fatalf("GetNotesByDateRange failed: %v", err)
+		}
+		if len(year) != 4 {
+			t.Errorf("%s layout: got %d notes in 2024, want 4", layout, len(year))
+		}
+	}
+}
+
+func TestFileRepositoryTrashRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	repo := NewFileRepository(dir)
+
+	kept := entities.NewNote("kept")
+	kept.Date = "2025-10-16"
+	trashed := entities.NewNote("trashed")
+	trashed.Date = "2025-10-16"
+	for _, note := range []*entities.Note{kept, trashed} {
+		if err := repo.Save(note); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	trashed.Content = "trashed, edited"
+	if err := repo.Save(trashed); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if err := repo.DeleteNote(trashed.ID); err != nil {
+		t.Fatalf("DeleteNote failed: %v", err)
+	}
+
+	// Another process sees the deletion through the files alone
+	other := NewFileRepository(dir)
+	if _, err := other.GetNoteByID(trashed.ID); !errors.Is(err, ports.ErrNoteNotFound) {
+		t.Errorf("GetNoteByID of a trashed note returned %v, want ErrNoteNotFound", err)
+	}
+	if notes, _ := other.GetNotesByDate(kept.Date); len(notes) != 1 || notes[0].ID != kept.ID {
+		t.Errorf("day file holds %d notes after DeleteNote, want only the kept note", len(notes))
+	}
+	trash, err := other.GetTrash()
+	if err != nil || len(trash) != 1 || trash[0].Note.Content != "trashed, edited" {
+		t.Fatalf("GetTrash returned %d notes (%v), want the trashed note", len(trash), err)
+	}
+
+	restored, err := other.RestoreNote(trashed.ID)
+	if err != nil {
+		t.Fatalf("RestoreNote failed: %v", err)
+	}
+	if restored.Date != trashed.Date {
+		t.Errorf("note restored to %s, want %s", restored.Date, trashed.Date)
+	}
+	if note, err := repo.GetNoteByID(trashed.ID); err != nil || note.Content != "trashed, edited" {
+		t.Errorf("GetNoteByID after RestoreNote returned %v, %v", note, err)
+	}
+	if revisions, err := repo.GetRevisions(trashed.ID); err != nil || len(revisions) != 2 {
+		t.Errorf("restored note has %d revisions (%v), want its 2 earlier ones", len(revisions), err)
+	}
+
+	// Emptying the trash removes the note and its history for good
+	if err := repo.DeleteNote(trashed.ID); err != nil {
+		t.Fatalf("DeleteNote failed: %v", err)
+	}
+	historyPath, err := repo.historyFilePath(trashed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := repo.EmptyTrash(time.Now().Add(time.Second)); err != nil || removed != 1 {
+		t.Fatalf("EmptyTrash removed %d notes (%v), want 1", removed, err)
+	}
+	if _, err := os.Stat(historyPath); !os.IsNotExist(err) {
+		t.Errorf("history of the removed note was kept: %v", err)
+	}
+	if trash, _ := NewFileRepository(dir).GetTrash(); len(trash) != 0 {
+		t.Errorf("trash holds %d notes after emptying, want 0", len(trash))
+	}
+	if _, err := repo.RestoreNote(trashed.ID); !errors.Is(err, ports.ErrNoteNotFound) {
+		t.Errorf("RestoreNote after EmptyTrash returned %v, want ErrNoteNotFound", err)
+	}
+	if notes, _ := repo.GetAllNotes(); len(notes) != 1 || notes[0].ID != kept.ID {
+		t.Errorf("repository holds %d notes, want only the kept note", len(notes))
+	}
+}
+
+func TestSaveWithNewDateMovesNote(t *testing.T) {
+	dir := t.TempDir()
+	repo := NewFileRepository(dir)
+
+	note := entities.NewNote("moving note")
+	note.Date = "2025-10-16"
+	if err := repo.Save(note); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// The index may name a day file that no longer holds the note, when
+	// the files were changed outside jtx
+	other := entities.NewNote("other note")
+	other.Date = "2025-10-16"
+	if err := repo.Save(other); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := NewFileRepository(dir).MoveNote(other, "2025-10-17"); err != nil {
+		t.Fatalf("MoveNote failed: %v", err)
+	}
+	moved, err := json.Marshal([]*entities.Note{other})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(repo.dayFilePath("2025-10-17"), []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "2025-10-18.json"), moved, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, n := range []*entities.Note{note, other} {
+		n.Date = "2025-10-20"
+		n.Content += ", moved"
+		if err := repo.Save(n); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	all, err := repo.GetAllNotes()
+	if err != nil {
+		t.Fatalf("GetAllNotes failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("repository holds %d notes, want 2", len(all))
+	}
+	for _, n := range all {
+		if n.Date != "2025-10-20" || !strings.HasSuffix(n.Content, ", moved") {
+			t.Errorf("note %s is %q under %s, want the moved version", n.ID, n.Content, n.Date)
+		}
+	}
+	if revisions, err := repo.GetRevisions(note.ID); err != nil || len(revisions) != 2 {
+		t.Errorf("moved note has %d revisions (%v), want 2", len(revisions), err)
+	}
+}
+
+func TestGetNoteByIDFindsNotesAddedOutsideJtx(t *testing.T) {
+	dir := t.TempDir()
+	repo := NewFileRepository(dir)
+
+	known := entities.NewNote("known note")
+	known.Date = "2025-10-16"
+	if err := repo.Save(known); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := repo.GetNoteByID(known.ID); err != nil {
+		t.Fatalf("GetNoteByID failed: %v", err)
+	}
+
+	// A day file written by another machine is not in the persisted index
+	pulled := entities.NewNote("pulled note")
+	pulled.Date = "2025-10-17"
+	data, err := json.Marshal([]*entities.Note{pulled})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "2025-10-17.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{known.ID, pulled.ID} {
+		if _, err := repo.GetNoteByID(id); err != nil {
+			t.Errorf("GetNoteByID(%s) failed: %v", id, err)
+		}
+	}
+	if _, err := repo.GetNoteByID("missing"); !errors.Is(err, ports.ErrNoteNotFound) {
+		t.Errorf("GetNoteByID of a missing note returned %v, want ErrNoteNotFound", err)
+	}
+}
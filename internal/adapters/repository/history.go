@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"jotterxpress/internal/adapters/fsutil"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// historyDirName is the directory holding one revision file per note
+const historyDirName = ".history"
+
+// historyImporter is implemented by repositories that can store the
+// revisions of a note as they are, for example when copying between backends
+type historyImporter interface {
+	SetRevisions(id string, revisions []*entities.Revision) error
+}
+
+// appendRevision returns history with note added as the next revision, and
+// whether anything was added. A note that only differs from the latest
+// revision in UpdatedAt is not added. previous is the stored version the
+// note replaces; it becomes the first revision when the history is empty, so
+// notes written before history was kept do not lose their original version.
+func appendRevision(history []*entities.Revision, previous, note *entities.Note) ([]*entities.Revision, bool) {
+	added := false
+
+	if len(history) == 0 && previous != nil {
+		history = append(history, &entities.Revision{Number: 1, SavedAt: previous.UpdatedAt, Note: previous})
+		added = true
+	}
+
+	if len(history) > 0 && sameVersion(history[len(history)-1].Note, note) {
+		return history, added
+	}
+
+	number := 1
+	if len(history) > 0 {
+		number = history[len(history)-1].Number + 1
+	}
+
+	return append(history, &entities.Revision{Number: number, SavedAt: note.UpdatedAt, Note: note}), true
+}
+
+// sameVersion reports whether two notes differ at most in UpdatedAt
+func sameVersion(a, b *entities.Note) bool {
+	ac, bc := *a, *b
+	ac.UpdatedAt, bc.UpdatedAt = time.Time{}, time.Time{}
+
+	aj, errA := json.Marshal(&ac)
+	bj, errB := json.Marshal(&bc)
+	return errA == nil && errB == nil && bytes.Equal(aj, bj)
+}
+
+// currentRevision presents a note without recorded history as its only revision
+func currentRevision(note *entities.Note) []*entities.Revision {
+	return []*entities.Revision{{Number: 1, SavedAt: note.UpdatedAt, Note: note}}
+}
+
+// GetRevisions retrieves every saved version of a note, oldest first
+func (r *fileRepository) GetRevisions(id string) ([]*entities.Revision, error) {
+	history, err := r.readHistory(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) > 0 {
+		return history, nil
+	}
+
+	// Only the ID index is consulted, so that reading the history of many
+	// notes, or of trashed ones, does not rescan the day files for each
+	note, _, err := r.locateNote(id)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, fmt.Errorf("note %s: %w", id, ports.ErrNoteNotFound)
+	}
+
+	return currentRevision(note), nil
+}
+
+// SetRevisions replaces the history of a note
+func (r *fileRepository) SetRevisions(id string, revisions []*entities.Revision) error {
+	return r.withLock(func() error {
+		return r.writeHistory(id, revisions)
+	})
+}
+
+// recordRevision adds note to its history; see appendRevision
+func (r *fileRepository) recordRevision(previous, note *entities.Note) error {
+	history, err := r.readHistory(note.ID)
+	if err != nil {
+		return err
+	}
+
+	history, added := appendRevision(history, previous, note)
+	if !added {
+		return nil
+	}
+
+	return r.writeHistory(note.ID, history)
+}
+
+// historyFilePath returns the path of the revision file of a note. The ID
+// is turned into a safe file name, since IDs can come from another machine
+// through folder sync; IDs with path separators are refused outright.
+func (r *fileRepository) historyFilePath(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) {
+		return "", fmt.Errorf("invalid note ID %q", id)
+	}
+	return filepath.Join(r.notesDir, historyDirName, fsutil.SafeName(id)+".json"), nil
+}
+
+// readHistory reads the revisions of a note; a missing file is an empty
+// history. Revisions of other notes, in a file that happens to have the
+// same name, are ignored.
+func (r *fileRepository) readHistory(id string) ([]*entities.Revision, error) {
+	path, err := r.historyFilePath(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read history of note %s: %w", id, err)
+	}
+
+	var history []*entities.Revision
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to decode history of note %s: %w", id, err)
+	}
+
+	var own []*entities.Revision
+	for _, revision := range history {
+		if revision.Note != nil && revision.Note.ID == id {
+			own = append(own, revision)
+		}
+	}
+
+	return own, nil
+}
+
+// writeHistory stores the revisions of a note
+func (r *fileRepository) writeHistory(id string, history []*entities.Revision) error {
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode history of note %s: %w", id, err)
+	}
+
+	path, err := r.historyFilePath(id)
+	if err != nil {
+		return err
+	}
+
+	if err := fsutil.WriteFileAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write history of note %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// removeHistory deletes the history of a note
+func (r *fileRepository) removeHistory(id string) error {
+	path, err := r.historyFilePath(id)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove history of note %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHistoryFileNamesAreSafe(t *testing.T) {
+	dir := t.TempDir()
+	repo := NewFileRepository(dir)
+
+	// IDs of notes converted from .txt files contain colons
+	note := entities.NewNote("legacy note")
+	note.ID = "2025-10-16-09:30:00"
+	note.Date = "2025-10-16"
+	if err := repo.Save(note); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	note.Content = "edited"
+	if err := repo.Save(note); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, historyDirName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() == note.ID+".json" {
+		t.Fatalf("history directory holds %v, want one file with a safe name", entries)
+	}
+	if revisions, err := repo.GetRevisions(note.ID); err != nil || len(revisions) != 2 {
+		t.Errorf("GetRevisions returned %d revisions (%v), want 2", len(revisions), err)
+	}
+
+	for _, id := range []string{"../escaped", `..\escaped`, "nested/id"} {
+		note := entities.NewNote("hostile note")
+		note.ID = id
+		note.Date = "2025-10-16"
+		if err := repo.Save(note); err == nil {
+			t.Errorf("Save accepted ID %q", id)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escaped.json")); !os.IsNotExist(err) {
+		t.Errorf("a history file was written outside %s: %v", historyDirName, err)
+	}
+	if notes, _ := repo.GetNotesByDate("2025-10-16"); len(notes) != 1 {
+		t.Errorf("day file holds %d notes, want only the legacy note", len(notes))
+	}
+}
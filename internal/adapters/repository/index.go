@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"jotterxpress/internal/adapters/fsutil"
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// indexedFile records the note IDs of one day file together with the file
+// size and modification time they were read at
+type indexedFile struct {
+	ModTime int64    `json:"mod_time"`
+	Size    int64    `json:"size"`
+	IDs     []string `json:"ids"`
+}
+
+// noteIndex maps note IDs to the date of the day file that holds them. It is
+// persisted in the notes directory and refreshed whenever a day file changes.
+type noteIndex struct {
+	Files map[string]*indexedFile `json:"files"`
+
+	byID map[string]string
+}
+
+// dateOf returns the date of the day file holding the note
+func (idx *noteIndex) dateOf(id string) (string, bool) {
+	date, ok := idx.byID[id]
+	return date, ok
+}
+
+// idsWithPrefix returns all indexed IDs starting with prefix, sorted
+func (idx *noteIndex) idsWithPrefix(prefix string) []string {
+	var ids []string
+	for id := range idx.byID {
+		if strings.HasPrefix(id, prefix) {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids
+}
+
+// mapIDs rebuilds the lookup from note ID to date
+func (idx *noteIndex) mapIDs() {
+	idx.byID = make(map[string]string)
+	for date, file := range idx.Files {
+		for _, id := range file.IDs {
+			idx.byID[id] = date
+		}
+	}
+}
+
+// indexFilePath returns the path of the ID index file
+func (r *fileRepository) indexFilePath() string {
+	return filepath.Join(r.notesDir, ".index.json")
+}
+
+// loadIndex loads the ID index, re-reading any day file that was added,
+// changed or removed since the index was last written
+func (r *fileRepository) loadIndex() (*noteIndex, error) {
+	index := &noteIndex{Files: make(map[string]*indexedFile)}
+
+	// A missing or unreadable index is simply rebuilt from scratch
+	if data, err := os.ReadFile(r.indexFilePath()); err == nil {
+		if err := json.Unmarshal(data, index); err != nil || index.Files == nil {
+			index.Files = make(map[string]*indexedFile)
+		}
+	}
+
+	files, err := r.listDayFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	stale := false
+	present := make(map[string]bool, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file.path)
+		if err != nil {
+			// The file was removed while listing
+			continue
+		}
+		present[file.date] = true
+
+		cached, ok := index.Files[file.date]
+		if ok && cached.ModTime == info.ModTime().UnixNano() && cached.Size == info.Size() {
+			continue
+		}
+
+		notes, err := r.readNotesOrSkip(file.path, file.date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to index %s: %w", file.path, err)
+		}
+
+		entry := &indexedFile{ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+		for _, note := range notes {
+			entry.IDs = append(entry.IDs, note.ID)
+		}
+		index.Files[file.date] = entry
+		stale = true
+	}
+
+	for date := range index.Files {
+		if !present[date] {
+			delete(index.Files, date)
+			stale = true
+		}
+	}
+
+	index.mapIDs()
+
+	if stale {
+		// The index is only a cache; failing to persist it is not an error
+		if data, err := json.Marshal(index); err == nil {
+			fsutil.WriteFileAtomic(r.indexFilePath(), data)
+		}
+	}
+
+	return index, nil
+}
+
+// readIndex returns the persisted ID index without checking it against the
+// day files, which writes through the repository keep it in step with. A
+// missing or unreadable index is built with loadIndex.
+func (r *fileRepository) readIndex() (*noteIndex, error) {
+	data, err := os.ReadFile(r.indexFilePath())
+	if err != nil {
+		return r.loadIndex()
+	}
+
+	index := &noteIndex{}
+	if err := json.Unmarshal(data, index); err != nil || index.Files == nil {
+		return r.loadIndex()
+	}
+
+	index.mapIDs()
+	return index, nil
+}
+
+// indexDayFile records the notes just written to a day file in the persisted
+// ID index, so readIndex stays current without a rescan. Files that are not
+// the day file loadIndex would read for their date are left to loadIndex.
+// The caller must hold the lock.
+func (r *fileRepository) indexDayFile(path string, notes []*entities.Note) {
+	date, ok := strings.CutSuffix(filepath.Base(path), ".json")
+	if !ok || len(date) != len("2006-01-02") || r.dayFilePath(date) != path {
+		return
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return
+	}
+
+	data, err := os.ReadFile(r.indexFilePath())
+	if err != nil {
+		return
+	}
+	index := &noteIndex{}
+	if err := json.Unmarshal(data, index); err != nil || index.Files == nil {
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	entry := &indexedFile{ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+	for _, note := range notes {
+		entry.IDs = append(entry.IDs, note.ID)
+	}
+	index.Files[date] = entry
+
+	// The index is only a cache; failing to persist it is not an error
+	if data, err := json.Marshal(index); err == nil {
+		fsutil.WriteFileAtomic(r.indexFilePath(), data)
+	}
+}
+
+// locateNote returns a note and the date of the day file holding it, or nil
+// when no day file does. It trusts the persisted ID index, and only rescans
+// the day files when the index names a file that no longer holds the note.
+func (r *fileRepository) locateNote(id string) (*entities.Note, string, error) {
+	index, err := r.readIndex()
+	if err != nil {
+		return nil, "", err
+	}
+
+	for rescanned := false; ; rescanned = true {
+		date, ok := index.dateOf(id)
+		if !ok {
+			return nil, "", nil
+		}
+
+		notes, err := r.readNotesOrSkip(r.dayFilePath(date), date)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read notes for %s: %w", date, err)
+		}
+		for _, note := range notes {
+			if note.ID == id {
+				return note, date, nil
+			}
+		}
+
+		if rescanned {
+			return nil, "", nil
+		}
+		if index, err = r.loadIndex(); err != nil {
+			return nil, "", err
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"jotterxpress/internal/adapters/fsutil"
+	"os"
+	"path/filepath"
+)
+
+// Day file layouts of a notes directory
+const (
+	// LayoutFlat keeps every day file in the notes directory itself
+	LayoutFlat = "flat"
+
+	// LayoutNested keeps day files in YYYY/MM subdirectories
+	LayoutNested = "nested"
+)
+
+// layoutFileName records the layout of a notes directory; it is absent for
+// the flat layout
+const layoutFileName = ".layout.json"
+
+// layoutFile is the content of the layout file
+type layoutFile struct {
+	Layout string `json:"layout"`
+}
+
+// LayoutReport describes what ConvertLayout did
+type LayoutReport struct {
+	From   string
+	To     string
+	Moved  int
+	Merged int
+}
+
+// Layout returns the layout new day files are written in. Day files are
+// read from either layout whatever it is.
+func (r *fileRepository) Layout() string {
+	data, err := os.ReadFile(filepath.Join(r.notesDir, layoutFileName))
+	if err != nil {
+		return LayoutFlat
+	}
+
+	var file layoutFile
+	if err := json.Unmarshal(data, &file); err != nil || file.Layout != LayoutNested {
+		return LayoutFlat
+	}
+	return LayoutNested
+}
+
+// flatDayFilePath returns the path of a day file in the flat layout
+func (r *fileRepository) flatDayFilePath(date string) string {
+	return filepath.Join(r.notesDir, date+".json")
+}
+
+// nestedDayFilePath returns the path of a day file in the nested layout
+func (r *fileRepository) nestedDayFilePath(date string) string {
+	return filepath.Join(r.notesDir, date[:4], date[5:7], date+".json")
+}
+
+// layoutDayFilePath returns the path of a day file in the given layout
+func (r *fileRepository) layoutDayFilePath(layout, date string) string {
+	if layout == LayoutNested {
+		return r.nestedDayFilePath(date)
+	}
+	return r.flatDayFilePath(date)
+}
+
+// dayFilePath returns the path of the file holding notes for a date: where
+// it already is, in either layout, or where the current layout puts it
+func (r *fileRepository) dayFilePath(date string) string {
+	if len(date) != len("2006-01-02") {
+		return r.flatDayFilePath(date)
+	}
+
+	nested := r.nestedDayFilePath(date)
+	if _, err := os.Stat(nested); err == nil {
+		return nested
+	}
+	flat := r.flatDayFilePath(date)
+	if _, err := os.Stat(flat); err == nil {
+		return flat
+	}
+
+	return r.layoutDayFilePath(r.Layout(), date)
+}
+
+// ConvertLayout moves every day file to the given layout and makes it the
+// layout of new day files. A date with a day file in both layouts, left by an
+// interrupted conversion or an older jtx, has its notes merged by ID.
+// Running it again after an interruption finishes the conversion.
+func (r *fileRepository) ConvertLayout(layout string) (*LayoutReport, error) {
+	if layout != LayoutFlat && layout != LayoutNested {
+		return nil, fmt.Errorf("unknown layout %q, expected %s or %s", layout, LayoutFlat, LayoutNested)
+	}
+
+	report := &LayoutReport{From: r.Layout(), To: layout}
+	err := r.withLock(func() error {
+		// Switch first, so notes saved after an interruption already go to the new layout
+		if err := r.writeLayout(layout); err != nil {
+			return err
+		}
+
+		files, err := r.scanDayFiles("", "")
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			target := r.layoutDayFilePath(layout, file.date)
+			if file.path == target {
+				continue
+			}
+
+			if _, err := os.Stat(target); os.IsNotExist(err) {
+				if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+					return fmt.Errorf("failed to create directory for %s: %w", file.date, err)
+				}
+				if err := os.Rename(file.path, target); err != nil {
+					return fmt.Errorf("failed to move %s: %w", file.path, err)
+				}
+				report.Moved++
+				continue
+			}
+
+			if err := r.mergeDayFile(file, target); err != nil {
+				return err
+			}
+			report.Merged++
+		}
+
+		if layout == LayoutFlat {
+			return r.removeEmptyLayoutDirs()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// mergeDayFile merges the notes of a day file into the day file at target
+// and removes it
+func (r *fileRepository) mergeDayFile(file dayFile, target string) error {
+	notes, err := r.readNotesLocked(file.path, file.date)
+	if err != nil {
+		return err
+	}
+	existing, err := r.readNotesLocked(target, file.date)
+	if err != nil {
+		return err
+	}
+
+	if err := r.writeNotesToFile(target, mergeNotes(existing, notes)); err != nil {
+		return fmt.Errorf("failed to write notes to file: %w", err)
+	}
+	if err := os.Remove(file.path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", file.path, err)
+	}
+
+	return nil
+}
+
+// writeLayout records the layout of the notes directory
+func (r *fileRepository) writeLayout(layout string) error {
+	path := filepath.Join(r.notesDir, layoutFileName)
+	if layout == LayoutFlat {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to write layout: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(layoutFile{Layout: layout})
+	if err != nil {
+		return fmt.Errorf("failed to encode layout: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write layout: %w", err)
+	}
+
+	return nil
+}
+
+// removeEmptyLayoutDirs removes the year and month directories left empty
+// after moving day files out of them
+func (r *fileRepository) removeEmptyLayoutDirs() error {
+	years, err := filepath.Glob(filepath.Join(r.notesDir, "[0-9][0-9][0-9][0-9]"))
+	if err != nil {
+		return err
+	}
+
+	for _, year := range years {
+		months, err := filepath.Glob(filepath.Join(year, "[0-9][0-9]"))
+		if err != nil {
+			return err
+		}
+
+		// os.Remove leaves directories that are not empty
+		for _, month := range months {
+			os.Remove(month)
+		}
+		os.Remove(year)
+	}
+
+	return nil
+}
@@ -0,0 +1,326 @@
+package repository
+
+import (
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryRepository implements the NoteRepository interface in memory. Notes
+// are copied on the way in and out, so callers see the same isolation as with
+// the stored backends. It is meant for tests and throwaway notebooks.
+type memoryRepository struct {
+	mu      sync.Mutex
+	notes   map[string]*entities.Note
+	trash   map[string]*entities.TrashedNote
+	history map[string][]*entities.Revision
+}
+
+// NewMemoryRepository creates an empty in-memory repository
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{
+		notes:   make(map[string]*entities.Note),
+		trash:   make(map[string]*entities.TrashedNote),
+		history: make(map[string][]*entities.Revision),
+	}
+}
+
+// Save inserts or updates a note
+func (r *memoryRepository) Save(note *entities.Note) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.saveLocked(note)
+}
+
+// saveLocked stores a copy of a note and records it in its history; the
+// caller must hold the lock
+func (r *memoryRepository) saveLocked(note *entities.Note) error {
+	stored, err := cloneNote(note)
+	if err != nil {
+		return err
+	}
+
+	history, added := appendRevision(r.history[note.ID], r.notes[note.ID], stored)
+	if added {
+		r.history[note.ID] = history
+	}
+
+	r.notes[note.ID] = stored
+	return nil
+}
+
+// MoveNote stores a note under a new date. Notes are keyed by ID, so saving
+// the note replaces it at its old date.
+func (r *memoryRepository) MoveNote(note *entities.Note, date string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.notes[note.ID]; !ok {
+		return fmt.Errorf("note %s: %w", note.ID, ports.ErrNoteNotFound)
+	}
+
+	note.Date = date
+	return r.saveLocked(note)
+}
+
+// GetNoteByID retrieves a note by its exact ID
+func (r *memoryRepository) GetNoteByID(id string) (*entities.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	note, ok := r.notes[id]
+	if !ok {
+		return nil, fmt.Errorf("note %s: %w", id, ports.ErrNoteNotFound)
+	}
+
+	return cloneNote(note)
+}
+
+// FindNoteIDs returns the IDs of all notes whose ID starts with prefix
+func (r *memoryRepository) FindNoteIDs(prefix string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []string
+	for id := range r.notes {
+		if strings.HasPrefix(id, prefix) {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// GetNotesByDate retrieves all notes for a specific date
+func (r *memoryRepository) GetNotesByDate(date string) ([]*entities.Note, error) {
+	notes, err := r.filter(func(note *entities.Note) bool {
+		return note.Date == date
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortNotes(notes)
+	return notes, nil
+}
+
+// GetNotesByDateRange retrieves notes within a date range
+func (r *memoryRepository) GetNotesByDateRange(startDate, endDate string) ([]*entities.Note, error) {
+	if _, err := time.Parse("2006-01-02", startDate); err != nil {
+		return nil, fmt.Errorf("invalid start date format: %w", err)
+	}
+	if _, err := time.Parse("2006-01-02", endDate); err != nil {
+		return nil, fmt.Errorf("invalid end date format: %w", err)
+	}
+
+	notes, err := r.filter(func(note *entities.Note) bool {
+		return note.Date >= startDate && note.Date <= endDate
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortByUpdatedAt(notes)
+	return notes, nil
+}
+
+// GetTodayNotes retrieves all notes for today
+func (r *memoryRepository) GetTodayNotes() ([]*entities.Note, error) {
+	return r.GetNotesByDate(time.Now().Format("2006-01-02"))
+}
+
+// GetNotesByMonth retrieves notes for a specific month
+// monthStr format: "2025-10" (YYYY-MM)
+func (r *memoryRepository) GetNotesByMonth(monthStr string) ([]*entities.Note, error) {
+	if _, err := time.Parse("2006-01", monthStr); err != nil {
+		return nil, fmt.Errorf("invalid month format, expected YYYY-MM: %w", err)
+	}
+
+	return r.GetNotesByDateRange(monthStr+"-01", monthStr+"-31")
+}
+
+// GetAllNotes retrieves every note in the repository
+func (r *memoryRepository) GetAllNotes() ([]*entities.Note, error) {
+	notes, err := r.filter(func(note *entities.Note) bool {
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortByUpdatedAt(notes)
+	return notes, nil
+}
+
+// GetRevisions retrieves every saved version of a note, oldest first
+func (r *memoryRepository) GetRevisions(id string) ([]*entities.Revision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	history := r.history[id]
+	if len(history) == 0 {
+		note, ok := r.notes[id]
+		if !ok {
+			return nil, fmt.Errorf("note %s: %w", id, ports.ErrNoteNotFound)
+		}
+		history = currentRevision(note)
+	}
+
+	revisions := make([]*entities.Revision, 0, len(history))
+	for _, revision := range history {
+		note, err := cloneNote(revision.Note)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &entities.Revision{Number: revision.Number, SavedAt: revision.SavedAt, Note: note})
+	}
+
+	return revisions, nil
+}
+
+// SetRevisions replaces the history of a note
+func (r *memoryRepository) SetRevisions(id string, revisions []*entities.Revision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	history := make([]*entities.Revision, 0, len(revisions))
+	for _, revision := range revisions {
+		note, err := cloneNote(revision.Note)
+		if err != nil {
+			return err
+		}
+		history = append(history, &entities.Revision{Number: revision.Number, SavedAt: revision.SavedAt, Note: note})
+	}
+
+	r.history[id] = history
+	return nil
+}
+
+// DeleteNote moves a note to the trash
+func (r *memoryRepository) DeleteNote(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	note, ok := r.notes[id]
+	if !ok {
+		return fmt.Errorf("note %s: %w", id, ports.ErrNoteNotFound)
+	}
+
+	r.trash[id] = &entities.TrashedNote{Note: note, DeletedAt: time.Now()}
+	delete(r.notes, id)
+	return nil
+}
+
+// GetTrash retrieves all trashed notes, most recently deleted first
+func (r *memoryRepository) GetTrash() ([]*entities.TrashedNote, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trash := []*entities.TrashedNote{}
+	for _, entry := range r.trash {
+		note, err := cloneNote(entry.Note)
+		if err != nil {
+			return nil, err
+		}
+		trash = append(trash, &entities.TrashedNote{Note: note, DeletedAt: entry.DeletedAt})
+	}
+
+	sort.Slice(trash, func(i, j int) bool {
+		return trash[i].DeletedAt.After(trash[j].DeletedAt)
+	})
+
+	return trash, nil
+}
+
+// RestoreNote moves a note from the trash back to its date
+func (r *memoryRepository) RestoreNote(id string) (*entities.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.trash[id]
+	if !ok {
+		return nil, fmt.Errorf("trashed note %s: %w", id, ports.ErrNoteNotFound)
+	}
+
+	if err := r.saveLocked(entry.Note); err != nil {
+		return nil, err
+	}
+	delete(r.trash, id)
+
+	return cloneNote(entry.Note)
+}
+
+// EmptyTrash permanently removes trashed notes deleted before the given
+// time, together with their history
+func (r *memoryRepository) EmptyTrash(before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	removed := 0
+	for id, entry := range r.trash {
+		if entry.DeletedAt.Before(before) {
+			delete(r.trash, id)
+			delete(r.history, id)
+			removed++
+		}
+	}
+
+	return removed, nil
+}
+
+// AddToTrash stores an already trashed note, keeping its deletion time
+func (r *memoryRepository) AddToTrash(entry *entities.TrashedNote) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	note, err := cloneNote(entry.Note)
+	if err != nil {
+		return err
+	}
+
+	r.trash[note.ID] = &entities.TrashedNote{Note: note, DeletedAt: entry.DeletedAt}
+	return nil
+}
+
+// filter returns copies of the notes matching keep
+func (r *memoryRepository) filter(keep func(note *entities.Note) bool) ([]*entities.Note, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notes := []*entities.Note{}
+	for _, note := range r.notes {
+		if !keep(note) {
+			continue
+		}
+
+		clone, err := cloneNote(note)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, clone)
+	}
+
+	return notes, nil
+}
+
+// cloneNote returns a deep copy of a note, made through its JSON encoding
+// like a round trip through storage
+func cloneNote(note *entities.Note) (*entities.Note, error) {
+	data, err := note.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode note: %w", err)
+	}
+
+	clone, err := entities.FromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode note: %w", err)
+	}
+
+	return clone, nil
+}
@@ -0,0 +1,613 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"jotterxpress/internal/adapters/fsutil"
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BackupDirName is the directory of the notes directory that holds the
+// backups taken before migrations
+const BackupDirName = ".backups"
+
+// Migration describes one step of a storage schema upgrade. Versions start
+// at 1 and each migration upgrades the schema from Version-1 to Version.
+type Migration struct {
+	Version     int
+	Description string
+}
+
+// MigrationStep is a pending migration together with the changes it makes
+type MigrationStep struct {
+	Migration
+	Changes []string
+}
+
+// MigrationReport describes the migrations that were run, or would be run
+type MigrationReport struct {
+	From       int
+	To         int
+	Steps      []*MigrationStep
+	BackupPath string
+}
+
+// fileMigration is a migration of the files storage. plan lists the changes
+// apply would make without making them.
+type fileMigration struct {
+	Migration
+	plan  func(r *fileRepository) ([]string, error)
+	apply func(r *fileRepository) error
+}
+
+// fileMigrations are the migrations of the files storage, in order. Add new
+// migrations at the end with the next version.
+var fileMigrations = []fileMigration{
+	{
+		Migration: Migration{Version: 1, Description: "convert legacy .txt day files to JSON"},
+		plan:      planTextMigration,
+		apply:     applyTextMigration,
+	},
+	{
+		Migration: Migration{Version: 2, Description: "repair year-0 timestamps of notes converted from .txt files"},
+		plan:      planTimestampRepair,
+		apply:     applyTimestampRepair,
+	},
+}
+
+// sqliteMigration is a migration of the SQLite storage. plan lists the
+// changes apply would make without making them; apply runs in a transaction.
+type sqliteMigration struct {
+	Migration
+	plan  func(r *sqliteRepository) ([]string, error)
+	apply func(tx *sql.Tx) error
+}
+
+// sqliteMigrations are the migrations of the SQLite storage, in order. Add
+// new migrations at the end with the next version.
+var sqliteMigrations = []sqliteMigration{
+	{
+		Migration: Migration{Version: 1, Description: "add the revisions table for note history"},
+		plan:      planRevisionsTable,
+		apply:     applyRevisionsTable,
+	},
+	{
+		Migration: Migration{Version: 2, Description: "repair year-0 timestamps of notes converted from .txt files"},
+		plan:      planSQLiteTimestampRepair,
+		apply:     applySQLiteTimestampRepair,
+	},
+}
+
+// schemaFile is the file that stamps the schema version of the files storage
+type schemaFile struct {
+	Version int `json:"version"`
+}
+
+// LatestSchemaVersion returns the schema version the files storage is migrated to
+func (r *fileRepository) LatestSchemaVersion() int {
+	return fileMigrations[len(fileMigrations)-1].Version
+}
+
+// SchemaVersion returns the schema version stamped into the notes directory.
+// A notes directory without a stamp predates versioning and is at version 0.
+func (r *fileRepository) SchemaVersion() (int, error) {
+	data, err := os.ReadFile(r.schemaFilePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	var schema schemaFile
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return 0, fmt.Errorf("failed to decode schema version: %w", err)
+	}
+
+	return schema.Version, nil
+}
+
+// Migrate runs every pending migration in order. The notes directory is
+// backed up first unless no migration has anything to change. With dryRun
+// set it only reports what would be done.
+func (r *fileRepository) Migrate(dryRun bool) (*MigrationReport, error) {
+	var report *MigrationReport
+
+	err := r.withLock(func() error {
+		from, err := r.SchemaVersion()
+		if err != nil {
+			return err
+		}
+		report = &MigrationReport{From: from, To: from}
+
+		var pending []fileMigration
+		changes := false
+		for _, migration := range fileMigrations {
+			if migration.Version <= from {
+				continue
+			}
+
+			planned, err := migration.plan(r)
+			if err != nil {
+				return fmt.Errorf("failed to plan migration %d: %w", migration.Version, err)
+			}
+
+			pending = append(pending, migration)
+			report.Steps = append(report.Steps, &MigrationStep{Migration: migration.Migration, Changes: planned})
+			report.To = migration.Version
+			changes = changes || len(planned) > 0
+		}
+
+		if dryRun || len(pending) == 0 {
+			return nil
+		}
+
+		if changes {
+			report.BackupPath, err = r.backup(from)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Stamp after every step so an interrupted upgrade resumes where it stopped
+		for _, migration := range pending {
+			if err := migration.apply(r); err != nil {
+				return fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Description, err)
+			}
+			if err := r.writeSchemaVersion(migration.Version); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// schemaFilePath returns the path of the schema version file
+func (r *fileRepository) schemaFilePath() string {
+	return filepath.Join(r.notesDir, ".schema.json")
+}
+
+// writeSchemaVersion stamps the schema version into the notes directory
+func (r *fileRepository) writeSchemaVersion(version int) error {
+	data, err := json.Marshal(schemaFile{Version: version})
+	if err != nil {
+		return err
+	}
+
+	if err := fsutil.WriteFileAtomic(r.schemaFilePath(), append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write schema version: %w", err)
+	}
+
+	return nil
+}
+
+// backup copies the notes directory, without earlier backups, git data and
+// lock files, to a new directory under BackupDirName and returns its path
+func (r *fileRepository) backup(version int) (string, error) {
+	name := fmt.Sprintf("schema-v%d-%s", version, time.Now().Format("20060102T150405"))
+	target := filepath.Join(r.notesDir, BackupDirName, name)
+
+	err := filepath.WalkDir(r.notesDir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(r.notesDir, path)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case rel == BackupDirName || rel == ".git":
+			return filepath.SkipDir
+		case rel == "." || entry.IsDir():
+			return os.MkdirAll(filepath.Join(target, rel), 0755)
+		case strings.HasSuffix(rel, ".lock") || !entry.Type().IsRegular():
+			return nil
+		}
+
+		return copyFile(path, filepath.Join(target, rel))
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to back up notes: %w", err)
+	}
+
+	return target, nil
+}
+
+// copyFile copies a regular file
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// LatestSchemaVersion returns the schema version the SQLite storage is migrated to
+func (r *sqliteRepository) LatestSchemaVersion() int {
+	return sqliteMigrations[len(sqliteMigrations)-1].Version
+}
+
+// SchemaVersion returns the schema version stamped into the database
+func (r *sqliteRepository) SchemaVersion() (int, error) {
+	var version int
+	if err := r.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// Migrate runs every pending migration in order. The database is backed up
+// first unless no migration has anything to change. With dryRun set it only
+// reports what would be done.
+func (r *sqliteRepository) Migrate(dryRun bool) (*MigrationReport, error) {
+	return r.migrate(dryRun, true)
+}
+
+// migrate runs the pending migrations, taking a backup first when backup is set
+func (r *sqliteRepository) migrate(dryRun, backup bool) (*MigrationReport, error) {
+	from, err := r.SchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	report := &MigrationReport{From: from, To: from}
+
+	var pending []sqliteMigration
+	changes := false
+	for _, migration := range sqliteMigrations {
+		if migration.Version <= from {
+			continue
+		}
+
+		planned, err := migration.plan(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to plan migration %d: %w", migration.Version, err)
+		}
+
+		pending = append(pending, migration)
+		report.Steps = append(report.Steps, &MigrationStep{Migration: migration.Migration, Changes: planned})
+		report.To = migration.Version
+		changes = changes || len(planned) > 0
+	}
+
+	if dryRun || len(pending) == 0 {
+		return report, nil
+	}
+
+	if changes && backup {
+		if report.BackupPath, err = r.backup(from); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, migration := range pending {
+		if err := migration.apply(tx); err != nil {
+			return nil, fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Description, err)
+		}
+	}
+
+	// PRAGMA does not take parameters; the version is always an int
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", report.To)); err != nil {
+		return nil, fmt.Errorf("failed to write schema version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// backup writes a copy of the database next to it, under BackupDirName, and
+// returns its path
+func (r *sqliteRepository) backup(version int) (string, error) {
+	dir := filepath.Join(filepath.Dir(r.path), BackupDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	name := fmt.Sprintf("schema-v%d-%s.db", version, time.Now().Format("20060102T150405"))
+	target := filepath.Join(dir, name)
+	if _, err := r.db.Exec("VACUUM INTO ?", target); err != nil {
+		return "", fmt.Errorf("failed to back up database: %w", err)
+	}
+
+	return target, nil
+}
+
+// planRevisionsTable reports whether migration 1 has to create the revisions
+// table; databases written by earlier builds may already have it
+func planRevisionsTable(r *sqliteRepository) ([]string, error) {
+	var count int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'revisions'").Scan(&count); err != nil {
+		return nil, err
+	}
+
+	if count > 0 {
+		return nil, nil
+	}
+	return []string{"create table revisions"}, nil
+}
+
+// applyRevisionsTable creates the revisions table
+func applyRevisionsTable(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+CREATE TABLE IF NOT EXISTS revisions (
+	note_id  TEXT NOT NULL,
+	number   INTEGER NOT NULL,
+	saved_at INTEGER NOT NULL,
+	data     TEXT NOT NULL,
+	PRIMARY KEY (note_id, number)
+);`)
+	return err
+}
+
+// legacyTextFile is a .txt day file waiting to be converted to JSON
+type legacyTextFile struct {
+	path  string
+	date  string
+	notes []*entities.Note
+}
+
+// legacyTextFiles returns the .txt day files that have no JSON day file yet,
+// with the notes they hold. Files with no readable notes are left in place.
+func (r *fileRepository) legacyTextFiles() ([]legacyTextFile, error) {
+	paths, err := filepath.Glob(filepath.Join(r.notesDir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+
+	var legacy []legacyTextFile
+	for _, path := range paths {
+		date := strings.TrimSuffix(filepath.Base(path), ".txt")
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			continue
+		}
+
+		// Text files next to a JSON day file are left to jtx doctor
+		if _, err := os.Stat(r.dayFilePath(date)); err == nil {
+			continue
+		}
+
+		notes, err := parseTextNotes(path, date)
+		if err != nil {
+			return nil, err
+		}
+		if len(notes) > 0 {
+			legacy = append(legacy, legacyTextFile{path: path, date: date, notes: notes})
+		}
+	}
+
+	return legacy, nil
+}
+
+// planTextMigration lists the legacy text files migration 1 converts
+func planTextMigration(r *fileRepository) ([]string, error) {
+	legacy, err := r.legacyTextFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]string, len(legacy))
+	for i, file := range legacy {
+		changes[i] = fmt.Sprintf("convert %s to JSON (%d notes)", filepath.Base(file.path), len(file.notes))
+	}
+
+	return changes, nil
+}
+
+// applyTextMigration converts every legacy text file to a JSON day file
+func applyTextMigration(r *fileRepository) error {
+	legacy, err := r.legacyTextFiles()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range legacy {
+		if err := r.writeNotesToFile(r.dayFilePath(file.date), file.notes); err != nil {
+			return err
+		}
+		if err := os.Remove(file.path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", file.path, err)
+		}
+	}
+
+	return nil
+}
+
+// legacyIDLayout is the layout of the IDs given to notes converted from .txt
+// day files: the date of the file and the time of the line
+const legacyIDLayout = "2006-01-02-15:04:05"
+
+// repairLegacyTimestamps rebuilds the timestamps of a note written by the
+// old .txt converter, which parsed only the time of day and so stored
+// CreatedAt and UpdatedAt in year 0. CreatedAt is taken from a legacy ID,
+// or else from date and the time of day that was kept. UpdatedAt is set to
+// CreatedAt when it is broken as well. It reports whether anything changed.
+func repairLegacyTimestamps(note *entities.Note, date string) bool {
+	if note.CreatedAt.Year() > 1 && note.UpdatedAt.Year() > 1 {
+		return false
+	}
+
+	if note.CreatedAt.Year() <= 1 {
+		createdAt, err := time.ParseInLocation(legacyIDLayout, note.ID, time.Local)
+		if err != nil {
+			day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+			if err != nil {
+				return false
+			}
+			clock := note.CreatedAt.UTC()
+			createdAt = time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, time.Local)
+		}
+		note.CreatedAt = createdAt
+	}
+	if note.UpdatedAt.Year() <= 1 || note.UpdatedAt.Before(note.CreatedAt) {
+		note.UpdatedAt = note.CreatedAt
+	}
+
+	return true
+}
+
+// repairedDayFile is a day file holding notes with year-0 timestamps,
+// with its notes already repaired
+type repairedDayFile struct {
+	dayFile
+	notes    []*entities.Note
+	repaired int
+}
+
+// brokenTimestamps returns the day files holding notes with year-0 timestamps
+func (r *fileRepository) brokenTimestamps() ([]repairedDayFile, error) {
+	files, err := r.listDayFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var broken []repairedDayFile
+	for _, file := range files {
+		notes, err := r.readNotesLocked(file.path, file.date)
+		if err != nil {
+			return nil, err
+		}
+
+		repaired := 0
+		for _, note := range notes {
+			if repairLegacyTimestamps(note, file.date) {
+				repaired++
+			}
+		}
+		if repaired > 0 {
+			broken = append(broken, repairedDayFile{dayFile: file, notes: notes, repaired: repaired})
+		}
+	}
+
+	return broken, nil
+}
+
+// planTimestampRepair lists the day files migration 2 repairs
+func planTimestampRepair(r *fileRepository) ([]string, error) {
+	broken, err := r.brokenTimestamps()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]string, len(broken))
+	for i, file := range broken {
+		changes[i] = fmt.Sprintf("repair timestamps in %s (%d notes)", filepath.Base(file.path), file.repaired)
+	}
+
+	return changes, nil
+}
+
+// applyTimestampRepair rewrites the day files holding notes with year-0
+// timestamps
+func applyTimestampRepair(r *fileRepository) error {
+	broken, err := r.brokenTimestamps()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range broken {
+		if err := r.writeNotesToFile(file.path, file.notes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sqliteQuerier is the part of a database and a transaction used to read notes
+type sqliteQuerier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// brokenSQLiteTimestamps returns the notes with year-0 timestamps, repaired
+func brokenSQLiteTimestamps(db sqliteQuerier) ([]*entities.Note, error) {
+	// The indexed columns cannot hold year 0 in nanoseconds, so every note is
+	// decoded from its data
+	rows, err := db.Query("SELECT data FROM notes ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var repaired []*entities.Note
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		var note entities.Note
+		if err := json.Unmarshal([]byte(data), &note); err != nil {
+			return nil, err
+		}
+		if repairLegacyTimestamps(&note, note.Date) {
+			repaired = append(repaired, &note)
+		}
+	}
+
+	return repaired, rows.Err()
+}
+
+// planSQLiteTimestampRepair lists the notes migration 2 repairs
+func planSQLiteTimestampRepair(r *sqliteRepository) ([]string, error) {
+	notes, err := brokenSQLiteTimestamps(r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]string, len(notes))
+	for i, note := range notes {
+		changes[i] = fmt.Sprintf("repair timestamps of note %s", note.ID)
+	}
+
+	return changes, nil
+}
+
+// applySQLiteTimestampRepair rewrites the notes with year-0 timestamps
+func applySQLiteTimestampRepair(tx *sql.Tx) error {
+	notes, err := brokenSQLiteTimestamps(tx)
+	if err != nil {
+		return err
+	}
+
+	for _, note := range notes {
+		if err := upsertNote(tx, note); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// local returns a local time on 16 October 2025
+func local(hour, min, sec int) time.Time {
+	return time.Date(2025, 10, 16, hour, min, sec, 0, time.Local)
+}
+
+func TestFileMigrationsConvertTextFiles(t *testing.T) {
+	dir := t.TempDir()
+	textPath := filepath.Join(dir, "2025-10-16.txt")
+	if err := os.WriteFile(textPath, []byte("[09:15:00] first note\n\n[12:34:56] second note\nnot a note\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewFileRepository(dir)
+
+	report, err := repo.Migrate(true)
+	if err != nil {
+		t.Fatalf("Migrate dry run failed: %v", err)
+	}
+	if report.From != 0 || report.To != repo.LatestSchemaVersion() || len(report.Steps[0].Changes) != 1 {
+		t.Errorf("dry run reports version %d to %d with changes %v, want one conversion", report.From, report.To, report.Steps[0].Changes)
+	}
+	if _, err := os.Stat(textPath); err != nil {
+		t.Errorf("dry run touched the text file: %v", err)
+	}
+	if version, _ := repo.SchemaVersion(); version != 0 {
+		t.Errorf("dry run stamped version %d", version)
+	}
+
+	report, err = repo.Migrate(false)
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if version, _ := repo.SchemaVersion(); version != repo.LatestSchemaVersion() {
+		t.Errorf("schema version is %d after migrating, want %d", version, repo.LatestSchemaVersion())
+	}
+	if _, err := os.Stat(filepath.Join(report.BackupPath, "2025-10-16.txt")); err != nil {
+		t.Errorf("backup does not hold the text file: %v", err)
+	}
+	if _, err := os.Stat(textPath); !os.IsNotExist(err) {
+		t.Errorf("text file was not removed: %v", err)
+	}
+
+	notes, err := repo.GetNotesByDate("2025-10-16")
+	if err != nil {
+		t.Fatalf("GetNotesByDate failed: %v", err)
+	}
+	if len(notes) != 2 {
+		t.Fatalf("got %d notes, want 2", len(notes))
+	}
+	for _, note := range notes {
+		want := map[string]time.Time{
+			"2025-10-16-09:15:00": local(9, 15, 0),
+			"2025-10-16-12:34:56": local(12, 34, 56),
+		}[note.ID]
+		if !note.CreatedAt.Equal(want) || !note.UpdatedAt.Equal(want) {
+			t.Errorf("note %s was created %s, want %s", note.ID, note.CreatedAt, want)
+		}
+	}
+
+	// Migrations run once
+	if report, err := repo.Migrate(false); err != nil || len(report.Steps) != 0 {
+		t.Errorf("second Migrate ran %d steps (%v), want none", len(report.Steps), err)
+	}
+}
+
+func TestFileMigrationRepairsYearZeroTimestamps(t *testing.T) {
+	dir := t.TempDir()
+	repo := NewFileRepository(dir)
+	if err := repo.writeSchemaVersion(1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Written by the old converter, then edited or left alone
+	dayFile := `[
+  {"id": "2025-10-16-12:34:56", "type": "text", "content": "legacy note", "created_at": "0000-01-01T12:34:56Z", "updated_at": "0000-01-01T12:34:56Z", "date": "2025-10-16", "metadata": {}},
+  {"id": "renamed", "type": "text", "content": "legacy note with a new ID", "created_at": "0000-01-01T08:15:00Z", "updated_at": "2025-10-17T10:00:00Z", "date": "2025-10-16", "metadata": {}},
+  {"id": "healthy", "type": "text", "content": "recent note", "created_at": "2025-10-16T18:00:00Z", "updated_at": "2025-10-16T18:00:00Z", "date": "2025-10-16", "metadata": {}}
+]`
+	if err := os.WriteFile(filepath.Join(dir, "2025-10-16.json"), []byte(dayFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := repo.Migrate(true)
+	if err != nil {
+		t.Fatalf("Migrate dry run failed: %v", err)
+	}
+	if len(report.Steps) != 1 || report.Steps[0].Version != 2 || len(report.Steps[0].Changes) != 1 {
+		t.Fatalf("dry run reports steps %+v, want migration 2 repairing one file", report.Steps)
+	}
+
+	if _, err := repo.Migrate(false); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	want := map[string][2]time.Time{
+		"2025-10-16-12:34:56": {local(12, 34, 56), local(12, 34, 56)},
+		"renamed":             {local(8, 15, 0), time.Date(2025, 10, 17, 10, 0, 0, 0, time.UTC)},
+		"healthy":             {time.Date(2025, 10, 16, 18, 0, 0, 0, time.UTC), time.Date(2025, 10, 16, 18, 0, 0, 0, time.UTC)},
+	}
+	for id, times := range want {
+		note, err := repo.GetNoteByID(id)
+		if err != nil {
+			t.Fatalf("GetNoteByID(%s) failed: %v", id, err)
+		}
+		if !note.CreatedAt.Equal(times[0]) || !note.UpdatedAt.Equal(times[1]) {
+			t.Errorf("note %s has times %s, %s, want %s, %s", id, note.CreatedAt, note.UpdatedAt, times[0], times[1])
+		}
+	}
+}
+
+func TestSQLiteMigrationRepairsYearZeroTimestamps(t *testing.T) {
+	repo, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "notes.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository failed: %v", err)
+	}
+	defer repo.Close()
+
+	if version, _ := repo.SchemaVersion(); version != repo.LatestSchemaVersion() {
+		t.Errorf("new database is at version %d, want %d", version, repo.LatestSchemaVersion())
+	}
+
+	// A note copied from files storage before the repair
+	note := entities.NewNote("legacy note")
+	note.ID = "2025-10-16-12:34:56"
+	note.Date = "2025-10-16"
+	note.CreatedAt = time.Date(0, 1, 1, 12, 34, 56, 0, time.UTC)
+	note.UpdatedAt = note.CreatedAt
+	if err := repo.Save(note); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := repo.db.Exec("PRAGMA user_version = 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := repo.Migrate(false)
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if len(report.Steps) != 1 || len(report.Steps[0].Changes) != 1 || report.BackupPath == "" {
+		t.Errorf("Migrate reports steps %+v and backup %q, want one repaired note and a backup", report.Steps, report.BackupPath)
+	}
+
+	got, err := repo.GetNoteByID(note.ID)
+	if err != nil {
+		t.Fatalf("GetNoteByID failed: %v", err)
+	}
+	if !got.CreatedAt.Equal(local(12, 34, 56)) || !got.UpdatedAt.Equal(local(12, 34, 56)) {
+		t.Errorf("note has times %s, %s, want %s", got.CreatedAt, got.UpdatedAt, local(12, 34, 56))
+	}
+}
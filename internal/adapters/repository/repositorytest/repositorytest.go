@@ -0,0 +1,556 @@
+// Package repositorytest checks that a ports.NoteRepository implementation
+// behaves like the backends that ship with jotterxpress.
+package repositorytest
+
+import (
+	"errors"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"sort"
+	"testing"
+	"time"
+)
+
+// TestNoteRepository runs the NoteRepository contract against an adapter.
+// newRepository must return a new, empty repository every time it is called;
+// it can use t.TempDir and t.Cleanup for anything it creates.
+func TestNoteRepository(t *testing.T, newRepository func(t *testing.T) ports.NoteRepository) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, repo ports.NoteRepository)
+	}{
+		{"SaveAndGetNoteByID", testSaveAndGetNoteByID},
+		{"GetNoteByIDMissing", testGetNoteByIDMissing},
+		{"ReturnedNotesAreCopies", testReturnedNotesAreCopies},
+		{"SaveUpdatesByID", testSaveUpdatesByID},
+		{"SaveWithNewDateMovesNote", testSaveWithNewDate},
+		{"FindNoteIDs", testFindNoteIDs},
+		{"GetNotesByDateSortsPendingRemindersFirst", testGetNotesByDateSorting},
+		{"GetNotesByDateRangeIncludesBounds", testGetNotesByDateRange},
+		{"GetNotesByDateRangeRejectsInvalidDates", testGetNotesByDateRangeInvalid},
+		{"GetNotesByMonth", testGetNotesByMonth},
+		{"GetTodayNotes", testGetTodayNotes},
+		{"GetAllNotes", testGetAllNotes},
+		{"MoveNote", testMoveNote},
+		{"GetRevisions", testGetRevisions},
+		{"DeleteNote", testDeleteNote},
+		{"GetTrashMostRecentFirst", testGetTrashOrder},
+		{"RestoreNote", testRestoreNote},
+		{"EmptyTrash", testEmptyTrash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newRepository(t))
+		})
+	}
+}
+
+// at returns a fixed time on 2025-03-10, hour:00 UTC
+func at(hour int) time.Time {
+	return time.Date(2025, 3, 10, hour, 0, 0, 0, time.UTC)
+}
+
+// newNote builds a text note with fixed timestamps
+func newNote(id, date string, updatedAt time.Time) *entities.Note {
+	return &entities.Note{
+		ID:        id,
+		Type:      entities.NoteTypeText,
+		Content:   "content of " + id,
+		CreatedAt: at(0),
+		UpdatedAt: updatedAt,
+		Date:      date,
+	}
+}
+
+// save saves notes, failing the test on error
+func save(t *testing.T, repo ports.NoteRepository, notes ...*entities.Note) {
+	t.Helper()
+
+	for _, note := range notes {
+		if err := repo.Save(note); err != nil {
+			t.Fatalf("Save(%s) failed: %v", note.ID, err)
+		}
+	}
+}
+
+// ids returns the IDs of notes in order
+func ids(notes []*entities.Note) []string {
+	result := []string{}
+	for _, note := range notes {
+		result = append(result, note.ID)
+	}
+	return result
+}
+
+// expectIDs fails the test unless notes have exactly the given IDs, in order
+func expectIDs(t *testing.T, what string, notes []*entities.Note, want ...string) {
+	t.Helper()
+
+	got := ids(notes)
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%s: got %v, want %v", what, got, want)
+		}
+	}
+}
+
+// expectNotFound fails the test unless err wraps ports.ErrNoteNotFound
+func expectNotFound(t *testing.T, what string, err error) {
+	t.Helper()
+
+	if !errors.Is(err, ports.ErrNoteNotFound) {
+		t.Fatalf("%s: got error %v, want ErrNoteNotFound", what, err)
+	}
+}
+
+func testSaveAndGetNoteByID(t *testing.T, repo ports.NoteRepository) {
+	note := newNote("n1", "2025-03-10", at(9))
+	note.Type = entities.NoteTypeTask
+	note.Metadata = entities.Metadata{
+		Priority: entities.PriorityHigh,
+		Status:   entities.StatusToDo,
+		Tags:     []string{"work", "urgent"},
+		Category: "project",
+	}
+	save(t, repo, note)
+
+	got, err := repo.GetNoteByID("n1")
+	if err != nil {
+		t.Fatalf("GetNoteByID failed: %v", err)
+	}
+
+	if got.ID != note.ID || got.Type != note.Type || got.Content != note.Content || got.Date != note.Date {
+		t.Errorf("got note %+v, want %+v", got, note)
+	}
+	if !got.CreatedAt.Equal(note.CreatedAt) || !got.UpdatedAt.Equal(note.UpdatedAt) {
+		t.Errorf("got times %v/%v, want %v/%v", got.CreatedAt, got.UpdatedAt, note.CreatedAt, note.UpdatedAt)
+	}
+	if got.Metadata.Priority != entities.PriorityHigh || got.Metadata.Status != entities.StatusToDo ||
+		got.Metadata.Category != "project" || len(got.Metadata.Tags) != 2 {
+		t.Errorf("got metadata %+v, want %+v", got.Metadata, note.Metadata)
+	}
+}
+
+func testGetNoteByIDMissing(t *testing.T, repo ports.NoteRepository) {
+	save(t, repo, newNote("n1", "2025-03-10", at(9)))
+
+	_, err := repo.GetNoteByID("missing")
+	expectNotFound(t, "GetNoteByID", err)
+}
+
+func testReturnedNotesAreCopies(t *testing.T, repo ports.NoteRepository) {
+	note := newNote("n1", "2025-03-10", at(9))
+	save(t, repo, note)
+
+	// Changing a note after saving or reading it must not change what is stored
+	note.Content = "changed after save"
+	got, err := repo.GetNoteByID("n1")
+	if err != nil {
+		t.Fatalf("GetNoteByID failed: %v", err)
+	}
+	got.Content = "changed after read"
+
+	again, err := repo.GetNoteByID("n1")
+	if err != nil {
+		t.Fatalf("GetNoteByID failed: %v", err)
+	}
+	if again.Content != "content of n1" {
+		t.Errorf("stored content is %q, want %q", again.Content, "content of n1")
+	}
+}
+
+func testSaveUpdatesByID(t *testing.T, repo ports.NoteRepository) {
+	note := newNote("n1", "2025-03-10", at(9))
+	save(t, repo, note)
+
+	note.Content = "updated"
+	note.UpdatedAt = at(10)
+	save(t, repo, note)
+
+	notes, err := repo.GetNotesByDate("2025-03-10")
+	if err != nil {
+		t.Fatalf("GetNotesByDate failed: %v", err)
+	}
+	expectIDs(t, "GetNotesByDate", notes, "n1")
+	if notes[0].Content != "updated" {
+		t.Errorf("content is %q, want %q", notes[0].Content, "updated")
+	}
+}
+
+func testSaveWithNewDate(t *testing.T, repo ports.NoteRepository) {
+	note := newNote("n1", "2025-03-10", at(9))
+	save(t, repo, note, newNote("n2", "2025-03-10", at(8)))
+
+	note.Date = "2025-03-12"
+	note.UpdatedAt = at(10)
+	save(t, repo, note)
+
+	all, err := repo.GetAllNotes()
+	if err != nil {
+		t.Fatalf("GetAllNotes failed: %v", err)
+	}
+	expectIDs(t, "GetAllNotes", all, "n1", "n2")
+
+	notes, err := repo.GetNotesByDate("2025-03-10")
+	if err != nil {
+		t.Fatalf("GetNotesByDate failed: %v", err)
+	}
+	expectIDs(t, "GetNotesByDate of the old date", notes, "n2")
+
+	got, err := repo.GetNoteByID("n1")
+	if err != nil {
+		t.Fatalf("GetNoteByID failed: %v", err)
+	}
+	if got.Date != "2025-03-12" {
+		t.Errorf("note is filed under %s, want 2025-03-12", got.Date)
+	}
+}
+
+func testFindNoteIDs(t *testing.T, repo ports.NoteRepository) {
+	save(t, repo,
+		newNote("abc1", "2025-03-10", at(9)),
+		newNote("abc2", "2025-03-11", at(9)),
+		newNote("abd", "2025-03-10", at(9)),
+	)
+
+	found, err := repo.FindNoteIDs("abc")
+	if err != nil {
+		t.Fatalf("FindNoteIDs failed: %v", err)
+	}
+	sort.Strings(found)
+	if len(found) != 2 || found[0] != "abc1" || found[1] != "abc2" {
+		t.Errorf("FindNoteIDs(abc) = %v, want [abc1 abc2]", found)
+	}
+
+	found, err = repo.FindNoteIDs("zzz")
+	if err != nil {
+		t.Fatalf("FindNoteIDs failed: %v", err)
+	}
+	if len(found) != 0 {
+		t.Errorf("FindNoteIDs(zzz) = %v, want none", found)
+	}
+}
+
+func testGetNotesByDateSorting(t *testing.T, repo ports.NoteRepository) {
+	text := newNote("text", "2025-03-10", at(12))
+
+	pending := newNote("pending", "2025-03-10", at(8))
+	pending.Type = entities.NoteTypeReminder
+	pending.Metadata.Status = entities.StatusToDo
+
+	// A reminder without a status is pending too
+	unset := newNote("unset", "2025-03-10", at(9))
+	unset.Type = entities.NoteTypeReminder
+
+	done := newNote("done", "2025-03-10", at(13))
+	done.Type = entities.NoteTypeReminder
+	done.Metadata.Status = entities.StatusCompleted
+
+	save(t, repo, text, pending, unset, done)
+
+	notes, err := repo.GetNotesByDate("2025-03-10")
+	if err != nil {
+		t.Fatalf("GetNotesByDate failed: %v", err)
+	}
+	expectIDs(t, "GetNotesByDate", notes, "unset", "pending", "done", "text")
+}
+
+func testGetNotesByDateRange(t *testing.T, repo ports.NoteRepository) {
+	save(t, repo,
+		newNote("before", "2025-03-09", at(20)),
+		newNote("start", "2025-03-10", at(9)),
+		newNote("middle", "2025-03-15", at(11)),
+		newNote("end", "2025-03-20", at(10)),
+		newNote("after", "2025-03-21", at(21)),
+	)
+
+	notes, err := repo.GetNotesByDateRange("2025-03-10", "2025-03-20")
+	if err != nil {
+		t.Fatalf("GetNotesByDateRange failed: %v", err)
+	}
+	expectIDs(t, "GetNotesByDateRange", notes, "middle", "end", "start")
+
+	notes, err = repo.GetNotesByDateRange("2025-03-15", "2025-03-15")
+	if err != nil {
+		t.Fatalf("GetNotesByDateRange failed: %v", err)
+	}
+	expectIDs(t, "single day range", notes, "middle")
+
+	notes, err = repo.GetNotesByDateRange("2024-01-01", "2024-12-31")
+	if err != nil {
+		t.Fatalf("GetNotesByDateRange failed: %v", err)
+	}
+	expectIDs(t, "empty range", notes)
+}
+
+func testGetNotesByDateRangeInvalid(t *testing.T, repo ports.NoteRepository) {
+	if _, err := repo.GetNotesByDateRange("2025-3-1", "2025-03-31"); err == nil {
+		t.Error("GetNotesByDateRange accepted an invalid start date")
+	}
+	if _, err := repo.GetNotesByDateRange("2025-03-01", "tomorrow"); err == nil {
+		t.Error("GetNotesByDateRange accepted an invalid end date")
+	}
+}
+
+func testGetNotesByMonth(t *testing.T, repo ports.NoteRepository) {
+	save(t, repo,
+		newNote("february", "2025-02-28", at(9)),
+		newNote("first", "2025-03-01", at(9)),
+		newNote("last", "2025-03-31", at(10)),
+		newNote("april", "2025-04-01", at(9)),
+	)
+
+	notes, err := repo.GetNotesByMonth("2025-03")
+	if err != nil {
+		t.Fatalf("GetNotesByMonth failed: %v", err)
+	}
+	expectIDs(t, "GetNotesByMonth", notes, "last", "first")
+
+	if _, err := repo.GetNotesByMonth("2025-13"); err == nil {
+		t.Error("GetNotesByMonth accepted an invalid month")
+	}
+}
+
+func testGetTodayNotes(t *testing.T, repo ports.NoteRepository) {
+	now := time.Now()
+	save(t, repo,
+		newNote("today", now.Format("2006-01-02"), at(9)),
+		newNote("yesterday", now.AddDate(0, 0, -1).Format("2006-01-02"), at(9)),
+	)
+
+	notes, err := repo.GetTodayNotes()
+	if err != nil {
+		t.Fatalf("GetTodayNotes failed: %v", err)
+	}
+	expectIDs(t, "GetTodayNotes", notes, "today")
+}
+
+func testGetAllNotes(t *testing.T, repo ports.NoteRepository) {
+	notes, err := repo.GetAllNotes()
+	if err != nil {
+		t.Fatalf("GetAllNotes failed: %v", err)
+	}
+	expectIDs(t, "GetAllNotes on an empty repository", notes)
+
+	save(t, repo,
+		newNote("old", "2020-01-01", at(11)),
+		newNote("new", "2030-12-31", at(9)),
+		newNote("now", "2025-03-10", at(10)),
+	)
+
+	notes, err = repo.GetAllNotes()
+	if err != nil {
+		t.Fatalf("GetAllNotes failed: %v", err)
+	}
+	expectIDs(t, "GetAllNotes", notes, "old", "now", "new")
+}
+
+func testMoveNote(t *testing.T, repo ports.NoteRepository) {
+	note := newNote("n1", "2025-03-10", at(9))
+	save(t, repo, note, newNote("n2", "2025-03-10", at(8)))
+
+	note.UpdatedAt = at(10)
+	if err := repo.MoveNote(note, "2025-03-12"); err != nil {
+		t.Fatalf("MoveNote failed: %v", err)
+	}
+	if note.Date != "2025-03-12" {
+		t.Errorf("note date is %s after the move, want 2025-03-12", note.Date)
+	}
+
+	notes, err := repo.GetNotesByDate("2025-03-10")
+	if err != nil {
+		t.Fatalf("GetNotesByDate failed: %v", err)
+	}
+	expectIDs(t, "old date", notes, "n2")
+
+	notes, err = repo.GetNotesByDate("2025-03-12")
+	if err != nil {
+		t.Fatalf("GetNotesByDate failed: %v", err)
+	}
+	expectIDs(t, "new date", notes, "n1")
+
+	// The note is listed once over a range covering both dates
+	notes, err = repo.GetNotesByDateRange("2025-03-01", "2025-03-31")
+	if err != nil {
+		t.Fatalf("GetNotesByDateRange failed: %v", err)
+	}
+	expectIDs(t, "range", notes, "n1", "n2")
+
+	got, err := repo.GetNoteByID("n1")
+	if err != nil {
+		t.Fatalf("GetNoteByID failed: %v", err)
+	}
+	if got.Date != "2025-03-12" {
+		t.Errorf("stored date is %s, want 2025-03-12", got.Date)
+	}
+
+	err = repo.MoveNote(newNote("missing", "2025-03-10", at(9)), "2025-03-12")
+	expectNotFound(t, "MoveNote", err)
+}
+
+func testGetRevisions(t *testing.T, repo ports.NoteRepository) {
+	note := newNote("n1", "2025-03-10", at(9))
+	save(t, repo, note)
+
+	history, err := repo.GetRevisions("n1")
+	if err != nil {
+		t.Fatalf("GetRevisions failed: %v", err)
+	}
+	if len(history) != 1 || history[0].Number != 1 || history[0].Note.Content != "content of n1" {
+		t.Fatalf("got %d revisions, want the saved note as revision 1", len(history))
+	}
+
+	note.Content = "second version"
+	note.UpdatedAt = at(10)
+	save(t, repo, note)
+
+	// Saving again with only a new update time adds no revision
+	note.UpdatedAt = at(11)
+	save(t, repo, note)
+
+	history, err = repo.GetRevisions("n1")
+	if err != nil {
+		t.Fatalf("GetRevisions failed: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("got %d revisions, want 2", len(history))
+	}
+	if history[0].Number != 1 || history[0].Note.Content != "content of n1" {
+		t.Errorf("revision 1 is %d %q, want the first version", history[0].Number, history[0].Note.Content)
+	}
+	if history[1].Number != 2 || history[1].Note.Content != "second version" {
+		t.Errorf("revision 2 is %d %q, want the current version", history[1].Number, history[1].Note.Content)
+	}
+
+	_, err = repo.GetRevisions("missing")
+	expectNotFound(t, "GetRevisions", err)
+}
+
+func testDeleteNote(t *testing.T, repo ports.NoteRepository) {
+	save(t, repo, newNote("n1", "2025-03-10", at(9)), newNote("n2", "2025-03-10", at(8)))
+
+	if err := repo.DeleteNote("n1"); err != nil {
+		t.Fatalf("DeleteNote failed: %v", err)
+	}
+
+	_, err := repo.GetNoteByID("n1")
+	expectNotFound(t, "GetNoteByID after DeleteNote", err)
+
+	notes, err := repo.GetNotesByDate("2025-03-10")
+	if err != nil {
+		t.Fatalf("GetNotesByDate failed: %v", err)
+	}
+	expectIDs(t, "GetNotesByDate", notes, "n2")
+
+	trash, err := repo.GetTrash()
+	if err != nil {
+		t.Fatalf("GetTrash failed: %v", err)
+	}
+	if len(trash) != 1 || trash[0].Note.ID != "n1" || trash[0].Note.Content != "content of n1" {
+		t.Fatalf("trash holds %d notes, want n1", len(trash))
+	}
+	if trash[0].DeletedAt.IsZero() {
+		t.Error("trashed note has no deletion time")
+	}
+
+	expectNotFound(t, "DeleteNote", repo.DeleteNote("missing"))
+}
+
+func testGetTrashOrder(t *testing.T, repo ports.NoteRepository) {
+	trash, err := repo.GetTrash()
+	if err != nil {
+		t.Fatalf("GetTrash failed: %v", err)
+	}
+	if len(trash) != 0 {
+		t.Fatalf("new repository has %d trashed notes", len(trash))
+	}
+
+	save(t, repo, newNote("first", "2025-03-10", at(9)), newNote("second", "2025-03-11", at(9)))
+	for _, id := range []string{"first", "second"} {
+		if err := repo.DeleteNote(id); err != nil {
+			t.Fatalf("DeleteNote failed: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	trash, err = repo.GetTrash()
+	if err != nil {
+		t.Fatalf("GetTrash failed: %v", err)
+	}
+	if len(trash) != 2 || trash[0].Note.ID != "second" || trash[1].Note.ID != "first" {
+		t.Errorf("trash is not ordered most recently deleted first")
+	}
+}
+
+func testRestoreNote(t *testing.T, repo ports.NoteRepository) {
+	save(t, repo, newNote("n1", "2025-03-10", at(9)))
+	if err := repo.DeleteNote("n1"); err != nil {
+		t.Fatalf("DeleteNote failed: %v", err)
+	}
+
+	restored, err := repo.RestoreNote("n1")
+	if err != nil {
+		t.Fatalf("RestoreNote failed: %v", err)
+	}
+	if restored.ID != "n1" || restored.Content != "content of n1" {
+		t.Errorf("restored %+v, want n1", restored)
+	}
+
+	notes, err := repo.GetNotesByDate("2025-03-10")
+	if err != nil {
+		t.Fatalf("GetNotesByDate failed: %v", err)
+	}
+	expectIDs(t, "GetNotesByDate after RestoreNote", notes, "n1")
+
+	trash, err := repo.GetTrash()
+	if err != nil {
+		t.Fatalf("GetTrash failed: %v", err)
+	}
+	if len(trash) != 0 {
+		t.Errorf("trash holds %d notes after restoring, want 0", len(trash))
+	}
+
+	_, err = repo.RestoreNote("n1")
+	expectNotFound(t, "RestoreNote", err)
+}
+
+func testEmptyTrash(t *testing.T, repo ports.NoteRepository) {
+	save(t, repo, newNote("n1", "2025-03-10", at(9)), newNote("n2", "2025-03-10", at(8)))
+	for _, id := range []string{"n1", "n2"} {
+		if err := repo.DeleteNote(id); err != nil {
+			t.Fatalf("DeleteNote failed: %v", err)
+		}
+	}
+
+	removed, err := repo.EmptyTrash(time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("EmptyTrash failed: %v", err)
+	}
+	if removed != 0 {
+		t.Errorf("EmptyTrash removed %d notes deleted after the cutoff", removed)
+	}
+
+	removed, err = repo.EmptyTrash(time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatalf("EmptyTrash failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("EmptyTrash removed %d notes, want 2", removed)
+	}
+
+	trash, err := repo.GetTrash()
+	if err != nil {
+		t.Fatalf("GetTrash failed: %v", err)
+	}
+	if len(trash) != 0 {
+		t.Errorf("trash holds %d notes after emptying, want 0", len(trash))
+	}
+
+	// Nothing of a permanently removed note is left, not even its history
+	_, err = repo.GetRevisions("n1")
+	expectNotFound(t, "GetRevisions after EmptyTrash", err)
+}
@@ -0,0 +1,368 @@
+package repository
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"jotterxpress/internal/domain/ports"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// snapshotFormat is the version of the snapshot archive layout
+	snapshotFormat = 1
+
+	// snapshotManifestName is the archive entry holding the manifest
+	snapshotManifestName = "manifest.json"
+
+	// snapshotNotesDir is the archive directory holding the notes directory
+	snapshotNotesDir = "notes"
+)
+
+// SnapshotFile describes one file of the notes directory in a snapshot
+type SnapshotFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// SnapshotManifest describes the content of a snapshot archive
+type SnapshotManifest struct {
+	Format    int            `json:"format"`
+	CreatedAt time.Time      `json:"created_at"`
+	Notebook  string         `json:"notebook"`
+	Storage   string         `json:"storage"`
+	Notes     int            `json:"notes"`
+	Trashed   int            `json:"trashed"`
+	Files     []SnapshotFile `json:"files"`
+
+	// Checksum is the SHA-256 of the file list, see checksum
+	Checksum string `json:"checksum"`
+}
+
+// databaseSnapshotter is implemented by repositories whose files cannot be
+// copied while they are open
+type databaseSnapshotter interface {
+	GetDatabasePath() string
+	Snapshot(target string) error
+}
+
+// Snapshot writes a consistent copy of the database to target, which must
+// not exist
+func (r *sqliteRepository) Snapshot(target string) error {
+	if _, err := r.db.Exec("VACUUM INTO ?", target); err != nil {
+		return fmt.Errorf("failed to copy database: %w", err)
+	}
+	return nil
+}
+
+// checksum returns the SHA-256 of the file list, one "sha256  path" line per file
+func (m *SnapshotManifest) checksum() string {
+	sum := sha256.New()
+	for _, file := range m.Files {
+		fmt.Fprintf(sum, "%s  %s\n", file.SHA256, file.Path)
+	}
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// snapshotSkipped reports whether a path of the notes directory is left out
+// of snapshots: earlier backups, git data, lock and temporary files, and the
+// caches that are rebuilt on demand
+func snapshotSkipped(rel string) bool {
+	name := filepath.Base(rel)
+	return rel == BackupDirName || rel == ".git" ||
+		rel == ".index.json" || rel == ".search-index.json" ||
+		strings.HasSuffix(name, ".lock") || strings.Contains(name, ".tmp-")
+}
+
+// CreateSnapshot writes a gzip-compressed tar archive of the notes directory
+// to target and returns its manifest. repo is the open repository of the
+// directory: it provides the note counts and, for SQLite, a consistent copy
+// of the database. The archive is written to a temporary file first, so
+// target is either complete or absent.
+func CreateSnapshot(target, notesDir string, repo ports.NoteRepository, notebook, storage string) (*SnapshotManifest, error) {
+	notes, err := repo.GetAllNotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count notes: %w", err)
+	}
+	trash, err := repo.GetTrash()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count trashed notes: %w", err)
+	}
+
+	manifest := &SnapshotManifest{
+		Format:    snapshotFormat,
+		CreatedAt: time.Now(),
+		Notebook:  notebook,
+		Storage:   storage,
+		Notes:     len(notes),
+		Trashed:   len(trash),
+	}
+
+	// An open database is archived from a consistent copy, without its journal
+	var database, databaseCopy string
+	if snapshotter, ok := repo.(databaseSnapshotter); ok {
+		database = snapshotter.GetDatabasePath()
+
+		tmpDir, err := os.MkdirTemp("", "jtx-snapshot-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+
+		databaseCopy = filepath.Join(tmpDir, filepath.Base(database))
+		if err := snapshotter.Snapshot(databaseCopy); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	tmpPath := target + ".tmp"
+	out, err := os.Create(tmpPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", target, err)
+	}
+	defer os.Remove(tmpPath)
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	err = filepath.WalkDir(notesDir, func(p string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(notesDir, p)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case rel == ".":
+			return nil
+		case snapshotSkipped(rel):
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		case entry.IsDir() || !entry.Type().IsRegular():
+			return nil
+		}
+
+		source := p
+		if database != "" && sameFile(p, database) {
+			source = databaseCopy
+		} else if database != "" && (sameFile(p, database+"-wal") || sameFile(p, database+"-shm")) {
+			return nil
+		}
+
+		file, err := addSnapshotFile(tw, source, path.Join(snapshotNotesDir, filepath.ToSlash(rel)))
+		if err != nil {
+			return err
+		}
+		file.Path = filepath.ToSlash(rel)
+		manifest.Files = append(manifest.Files, *file)
+		return nil
+	})
+	if err != nil {
+		out.Close()
+		return nil, fmt.Errorf("failed to archive notes: %w", err)
+	}
+
+	manifest.Checksum = manifest.checksum()
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		out.Close()
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	// The manifest comes last: it is only known once every file was hashed
+	err = tw.WriteHeader(&tar.Header{
+		Name:    snapshotManifestName,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: manifest.CreatedAt,
+	})
+	if err == nil {
+		_, err = tw.Write(data)
+	}
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gz.Close()
+	}
+	if err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", target, err)
+	}
+
+	if err := os.Rename(tmpPath, target); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", target, err)
+	}
+
+	return manifest, nil
+}
+
+// addSnapshotFile adds a file to the archive under name and returns its size
+// and checksum
+func addSnapshotFile(tw *tar.Writer, source, name string) (*SnapshotFile, error) {
+	in, err := os.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tw, sum), in); err != nil {
+		return nil, err
+	}
+
+	return &SnapshotFile{Size: info.Size(), SHA256: hex.EncodeToString(sum.Sum(nil))}, nil
+}
+
+// ExtractSnapshot verifies a snapshot archive and unpacks its notes
+// directory into dir, which must be empty or absent. Every file is checked
+// against the manifest, and the manifest against its checksum.
+func ExtractSnapshot(archive, dir string) (*SnapshotManifest, error) {
+	in, err := os.Open(archive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", archive, err)
+	}
+	defer in.Close()
+
+	gz, err := gzip.NewReader(in)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a jtx backup: %w", archive, err)
+	}
+	defer gz.Close()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	var manifest *SnapshotManifest
+	extracted := make(map[string]SnapshotFile)
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s is damaged: %w", archive, err)
+		}
+
+		if header.Name == snapshotManifestName {
+			manifest = &SnapshotManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("%s has an invalid manifest: %w", archive, err)
+			}
+			continue
+		}
+
+		// Archives repacked with other tools may list directories too
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+
+		rel, ok := strings.CutPrefix(header.Name, snapshotNotesDir+"/")
+		if !ok || header.Typeflag != tar.TypeReg || rel == "" || rel != path.Clean(rel) || strings.HasPrefix(rel, "../") {
+			return nil, fmt.Errorf("%s has an unexpected entry %q", archive, header.Name)
+		}
+
+		file, err := extractSnapshotFile(tr, filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract %s: %w", rel, err)
+		}
+		file.Path = rel
+		extracted[rel] = *file
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("%s has no manifest, it is not a jtx backup", archive)
+	}
+	if manifest.Format > snapshotFormat {
+		return nil, fmt.Errorf("%s was written by a newer jtx (format %d)", archive, manifest.Format)
+	}
+	if manifest.checksum() != manifest.Checksum {
+		return nil, fmt.Errorf("%s failed verification: manifest checksum mismatch", archive)
+	}
+
+	for _, file := range manifest.Files {
+		got, ok := extracted[file.Path]
+		if !ok {
+			return nil, fmt.Errorf("%s failed verification: %s is missing", archive, file.Path)
+		}
+		if got.Size != file.Size || got.SHA256 != file.SHA256 {
+			return nil, fmt.Errorf("%s failed verification: %s does not match its checksum", archive, file.Path)
+		}
+		delete(extracted, file.Path)
+	}
+	for rel := range extracted {
+		return nil, fmt.Errorf("%s failed verification: %s is not in the manifest", archive, rel)
+	}
+
+	return manifest, nil
+}
+
+// extractSnapshotFile writes an archive entry to target and returns its size
+// and checksum
+func extractSnapshotFile(r io.Reader, target string) (*SnapshotFile, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return nil, err
+	}
+
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.New()
+	size, err := io.Copy(io.MultiWriter(out, sum), r)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &SnapshotFile{Size: size, SHA256: hex.EncodeToString(sum.Sum(nil))}, nil
+}
+
+// sameFile reports whether two paths name the same file
+func sameFile(a, b string) bool {
+	ai, errA := os.Stat(a)
+	bi, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(ai, bi)
+}
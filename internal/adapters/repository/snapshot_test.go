@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	notesDir := t.TempDir()
+	repo := NewFileRepository(notesDir)
+	for _, content := range []string{"first", "second", "third"} {
+		if err := repo.Save(entities.NewNote(content)); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	archive := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+	manifest, err := CreateSnapshot(archive, notesDir, repo, "default", "files")
+	if err != nil {
+		t.Fatalf("CreateSnapshot failed: %v", err)
+	}
+	if manifest.Notes != 3 {
+		t.Errorf("manifest lists %d notes, want 3", manifest.Notes)
+	}
+	for _, file := range manifest.Files {
+		if file.Path == ".index.json" || file.Path == ".lock" {
+			t.Errorf("snapshot contains %s", file.Path)
+		}
+	}
+
+	dir := filepath.Join(t.TempDir(), "restored")
+	extracted, err := ExtractSnapshot(archive, dir)
+	if err != nil {
+		t.Fatalf("ExtractSnapshot failed: %v", err)
+	}
+	if extracted.Checksum != manifest.Checksum {
+		t.Errorf("extracted checksum %s, want %s", extracted.Checksum, manifest.Checksum)
+	}
+
+	notes, err := NewFileRepository(dir).GetAllNotes()
+	if err != nil {
+		t.Fatalf("GetAllNotes failed: %v", err)
+	}
+	if len(notes) != 3 {
+		t.Errorf("restored %d notes, want 3", len(notes))
+	}
+}
+
+func TestExtractSnapshotRejectsTamperedFiles(t *testing.T) {
+	notesDir := t.TempDir()
+	repo := NewFileRepository(notesDir)
+	if err := repo.Save(entities.NewNote("original")); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+	if _, err := CreateSnapshot(archive, notesDir, repo, "default", "files"); err != nil {
+		t.Fatalf("CreateSnapshot failed: %v", err)
+	}
+
+	// Rewrite the archive with the note content changed but the manifest kept
+	tampered := filepath.Join(t.TempDir(), "tampered.tar.gz")
+	rewriteArchive(t, archive, tampered, func(name string, data []byte) []byte {
+		if strings.HasSuffix(name, ".json") && name != snapshotManifestName {
+			return []byte(strings.ReplaceAll(string(data), "original", "modified"))
+		}
+		return data
+	})
+
+	if _, err := ExtractSnapshot(tampered, filepath.Join(t.TempDir(), "restored")); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("ExtractSnapshot error is %v, want a checksum mismatch", err)
+	}
+}
+
+func TestMergeKeepsLatestVersion(t *testing.T) {
+	dst := NewMemoryRepository()
+	src := NewMemoryRepository()
+	base := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	newer := &entities.Note{ID: "both-newer", Type: entities.NoteTypeText, Content: "src wins", Date: "2025-03-10", UpdatedAt: base.Add(time.Hour)}
+	older := &entities.Note{ID: "both-older", Type: entities.NoteTypeText, Content: "src loses", Date: "2025-03-10", UpdatedAt: base}
+	onlySrc := &entities.Note{ID: "only-src", Type: entities.NoteTypeText, Content: "added", Date: "2025-03-11", UpdatedAt: base}
+	for _, note := range []*entities.Note{newer, older, onlySrc} {
+		if err := src.Save(note); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	for _, note := range []*entities.Note{
+		{ID: "both-newer", Type: entities.NoteTypeText, Content: "dst loses", Date: "2025-03-10", UpdatedAt: base},
+		{ID: "both-older", Type: entities.NoteTypeText, Content: "dst wins", Date: "2025-03-10", UpdatedAt: base.Add(time.Hour)},
+	} {
+		if err := dst.Save(note); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	stats, err := Merge(dst, src)
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if stats.Added != 1 || stats.Updated != 1 || stats.Kept != 1 {
+		t.Errorf("got %+v, want 1 added, 1 updated, 1 kept", stats)
+	}
+
+	want := map[string]string{"both-newer": "src wins", "both-older": "dst wins", "only-src": "added"}
+	for id, content := range want {
+		note, err := dst.GetNoteByID(id)
+		if err != nil {
+			t.Fatalf("GetNoteByID(%s) failed: %v", id, err)
+		}
+		if note.Content != content {
+			t.Errorf("note %s has content %q, want %q", id, note.Content, content)
+		}
+	}
+}
+
+// rewriteArchive copies a tar.gz archive, passing every file through change
+func rewriteArchive(t *testing.T, from, to string, change func(name string, data []byte) []byte) {
+	t.Helper()
+
+	in, err := os.Open(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+	gr, err := gzip.NewReader(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := os.Create(to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data = change(header.Name, data)
+		header.Size = int64(len(data))
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,587 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema creates the tables and indexes of schema version 0; later
+// versions are reached through sqliteMigrations. The full note is kept as
+// JSON in the data column so nothing is lost on a round trip; the other
+// columns are indexed copies used for querying.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS notes (
+	id         TEXT PRIMARY KEY,
+	type       TEXT NOT NULL,
+	status     TEXT NOT NULL DEFAULT '',
+	priority   TEXT NOT NULL DEFAULT '',
+	date       TEXT NOT NULL,
+	content    TEXT NOT NULL,
+	created_at INTEGER NOT NULL,
+	updated_at INTEGER NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS notes_type ON notes(type);
+CREATE INDEX IF NOT EXISTS notes_status ON notes(status);
+CREATE INDEX IF NOT EXISTS notes_date ON notes(date);
+CREATE INDEX IF NOT EXISTS notes_priority ON notes(priority);
+
+CREATE TABLE IF NOT EXISTS note_tags (
+	note_id TEXT NOT NULL,
+	tag     TEXT NOT NULL,
+	PRIMARY KEY (note_id, tag)
+);
+CREATE INDEX IF NOT EXISTS note_tags_tag ON note_tags(tag);
+
+CREATE TABLE IF NOT EXISTS trash (
+	id         TEXT PRIMARY KEY,
+	deleted_at INTEGER NOT NULL,
+	data       TEXT NOT NULL
+);
+`
+
+// sqliteRepository implements the NoteRepository interface using SQLite
+type sqliteRepository struct {
+	db   *sql.DB
+	path string
+}
+
+// sqliteDSN returns the connection URI of the database at path. The path is
+// escaped, so a '?', '#' or '%' in a directory name cannot end it early.
+func sqliteDSN(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	// A Windows path such as C:\notes.db becomes /C:/notes.db
+	uriPath := filepath.ToSlash(abs)
+	if !strings.HasPrefix(uriPath, "/") {
+		uriPath = "/" + uriPath
+	}
+
+	dsn := url.URL{
+		Scheme:   "file",
+		Path:     uriPath,
+		RawQuery: "_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)",
+	}
+	return dsn.String(), nil
+}
+
+// NewSQLiteRepository opens (and creates if needed) a SQLite-backed repository
+func NewSQLiteRepository(path string) (*sqliteRepository, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	dsn, err := sqliteDSN(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table'").Scan(&tables); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create database schema: %w", err)
+	}
+
+	repo := &sqliteRepository{
+		db:   db,
+		path: path,
+	}
+
+	// A new database has nothing to back up and starts at the latest schema
+	if tables == 0 {
+		if _, err := repo.migrate(false, false); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	return repo, nil
+}
+
+// GetDatabasePath returns the database file path
+func (r *sqliteRepository) GetDatabasePath() string {
+	return r.path
+}
+
+// Close closes the database
+func (r *sqliteRepository) Close() error {
+	return r.db.Close()
+}
+
+// Save inserts or updates a note
+func (r *sqliteRepository) Save(note *entities.Note) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := recordRevisionTx(tx, note); err != nil {
+		return err
+	}
+
+	if err := upsertNote(tx, note); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MoveNote stores a note under a new date. Notes are keyed by ID, so saving
+// the note replaces its old row.
+func (r *sqliteRepository) MoveNote(note *entities.Note, date string) error {
+	if _, err := r.GetNoteByID(note.ID); err != nil {
+		return err
+	}
+
+	note.Date = date
+	return r.Save(note)
+}
+
+// GetNoteByID retrieves a note by its exact ID
+func (r *sqliteRepository) GetNoteByID(id string) (*entities.Note, error) {
+	notes, err := r.queryNotes("SELECT data FROM notes WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(notes) == 0 {
+		return nil, fmt.Errorf("note %s: %w", id, ports.ErrNoteNotFound)
+	}
+
+	return notes[0], nil
+}
+
+// FindNoteIDs returns the IDs of all notes whose ID starts with prefix
+func (r *sqliteRepository) FindNoteIDs(prefix string) ([]string, error) {
+	rows, err := r.db.Query(
+		"SELECT id FROM notes WHERE substr(id, 1, ?) = ? ORDER BY id",
+		len(prefix), prefix,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query note IDs: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to read note ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetNotesByDate retrieves all notes for a specific date
+func (r *sqliteRepository) GetNotesByDate(date string) ([]*entities.Note, error) {
+	notes, err := r.queryNotes("SELECT data FROM notes WHERE date = ?", date)
+	if err != nil {
+		return nil, err
+	}
+
+	sortNotes(notes)
+	return notes, nil
+}
+
+// GetNotesByDateRange retrieves notes within a date range
+func (r *sqliteRepository) GetNotesByDateRange(startDate, endDate string) ([]*entities.Note, error) {
+	if _, err := time.Parse("2006-01-02", startDate); err != nil {
+		return nil, fmt.Errorf("invalid start date format: %w", err)
+	}
+	if _, err := time.Parse("2006-01-02", endDate); err != nil {
+		return nil, fmt.Errorf("invalid end date format: %w", err)
+	}
+
+	return r.queryNotes(
+		"SELECT data FROM notes WHERE date >= ? AND date <= ? ORDER BY updated_at DESC",
+		startDate, endDate,
+	)
+}
+
+// GetTodayNotes retrieves all notes for today
+func (r *sqliteRepository) GetTodayNotes() ([]*entities.Note, error) {
+	return r.GetNotesByDate(time.Now().Format("2006-01-02"))
+}
+
+// GetNotesByMonth retrieves notes for a specific month
+// monthStr format: "2025-10" (YYYY-MM)
+func (r *sqliteRepository) GetNotesByMonth(monthStr string) ([]*entities.Note, error) {
+	if _, err := time.Parse("2006-01", monthStr); err != nil {
+		return nil, fmt.Errorf("invalid month format, expected YYYY-MM: %w", err)
+	}
+
+	return r.queryNotes(
+		"SELECT data FROM notes WHERE date >= ? AND date <= ? ORDER BY updated_at DESC",
+		monthStr+"-01", monthStr+"-31",
+	)
+}
+
+// GetAllNotes retrieves every note in the repository
+func (r *sqliteRepository) GetAllNotes() ([]*entities.Note, error) {
+	return r.queryNotes("SELECT data FROM notes ORDER BY updated_at DESC")
+}
+
+// DeleteNote moves a note to the trash
+func (r *sqliteRepository) DeleteNote(id string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var data string
+	err = tx.QueryRow("SELECT data FROM notes WHERE id = ?", id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("note %s: %w", id, ports.ErrNoteNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read note %s: %w", id, err)
+	}
+
+	if _, err := tx.Exec(
+		"INSERT OR REPLACE INTO trash (id, deleted_at, data) VALUES (?, ?, ?)",
+		id, time.Now().UnixNano(), data,
+	); err != nil {
+		return fmt.Errorf("failed to move note to trash: %w", err)
+	}
+
+	if err := deleteNoteRows(tx, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetTrash retrieves all trashed notes, most recently deleted first
+func (r *sqliteRepository) GetTrash() ([]*entities.TrashedNote, error) {
+	rows, err := r.db.Query("SELECT deleted_at, data FROM trash ORDER BY deleted_at DESC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trash: %w", err)
+	}
+	defer rows.Close()
+
+	trash := []*entities.TrashedNote{}
+	for rows.Next() {
+		var deletedAt int64
+		var data string
+		if err := rows.Scan(&deletedAt, &data); err != nil {
+			return nil, fmt.Errorf("failed to read trash: %w", err)
+		}
+
+		note, err := entities.FromJSON([]byte(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode trashed note: %w", err)
+		}
+
+		trash = append(trash, &entities.TrashedNote{Note: note, DeletedAt: time.Unix(0, deletedAt)})
+	}
+
+	return trash, rows.Err()
+}
+
+// RestoreNote moves a note from the trash back into the notes table
+func (r *sqliteRepository) RestoreNote(id string) (*entities.Note, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var data string
+	err = tx.QueryRow("SELECT data FROM trash WHERE id = ?", id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("trashed note %s: %w", id, ports.ErrNoteNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trashed note %s: %w", id, err)
+	}
+
+	note, err := entities.FromJSON([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode trashed note: %w", err)
+	}
+
+	if err := upsertNote(tx, note); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM trash WHERE id = ?", id); err != nil {
+		return nil, fmt.Errorf("failed to remove note from trash: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// EmptyTrash permanently removes trashed notes deleted before the given
+// time, together with their history
+func (r *sqliteRepository) EmptyTrash(before time.Time) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(
+		"DELETE FROM revisions WHERE note_id IN (SELECT id FROM trash WHERE deleted_at < ?)",
+		before.UnixNano(),
+	); err != nil {
+		return 0, fmt.Errorf("failed to remove history: %w", err)
+	}
+
+	result, err := tx.Exec("DELETE FROM trash WHERE deleted_at < ?", before.UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("failed to empty trash: %w", err)
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return int(removed), nil
+}
+
+// AddToTrash stores an already trashed note, keeping its deletion time
+func (r *sqliteRepository) AddToTrash(entry *entities.TrashedNote) error {
+	data, err := json.Marshal(entry.Note)
+	if err != nil {
+		return fmt.Errorf("failed to encode note: %w", err)
+	}
+
+	if _, err := r.db.Exec(
+		"INSERT OR REPLACE INTO trash (id, deleted_at, data) VALUES (?, ?, ?)",
+		entry.Note.ID, entry.DeletedAt.UnixNano(), string(data),
+	); err != nil {
+		return fmt.Errorf("failed to add note to trash: %w", err)
+	}
+
+	return nil
+}
+
+// queryNotes runs a query selecting the data column and decodes the notes
+func (r *sqliteRepository) queryNotes(query string, args ...any) ([]*entities.Note, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []*entities.Note{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read note: %w", err)
+		}
+
+		note, err := entities.FromJSON([]byte(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode note: %w", err)
+		}
+		notes = append(notes, note)
+	}
+
+	return notes, rows.Err()
+}
+
+// upsertNote writes a note and its tags inside a transaction
+func upsertNote(tx *sql.Tx, note *entities.Note) error {
+	data, err := json.Marshal(note)
+	if err != nil {
+		return fmt.Errorf("failed to encode note: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO notes (id, type, status, priority, date, content, created_at, updated_at, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			type = excluded.type,
+			status = excluded.status,
+			priority = excluded.priority,
+			date = excluded.date,
+			content = excluded.content,
+			created_at = excluded.created_at,
+			updated_at = excluded.updated_at,
+			data = excluded.data`,
+		note.ID,
+		string(note.Type),
+		string(note.Metadata.Status),
+		string(note.Metadata.Priority),
+		note.Date,
+		note.Content,
+		note.CreatedAt.UnixNano(),
+		note.UpdatedAt.UnixNano(),
+		string(data),
+	); err != nil {
+		return fmt.Errorf("failed to save note: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM note_tags WHERE note_id = ?", note.ID); err != nil {
+		return fmt.Errorf("failed to update tags: %w", err)
+	}
+	for _, tag := range note.Metadata.Tags {
+		if _, err := tx.Exec(
+			"INSERT OR IGNORE INTO note_tags (note_id, tag) VALUES (?, ?)",
+			note.ID, tag,
+		); err != nil {
+			return fmt.Errorf("failed to update tags: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// deleteNoteRows removes a note and its tags inside a transaction
+func deleteNoteRows(tx *sql.Tx, id string) error {
+	if _, err := tx.Exec("DELETE FROM note_tags WHERE note_id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete tags: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM notes WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete note: %w", err)
+	}
+	return nil
+}
+
+// GetRevisions retrieves every saved version of a note, oldest first
+func (r *sqliteRepository) GetRevisions(id string) ([]*entities.Revision, error) {
+	history, err := queryRevisions(r.db, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) > 0 {
+		return history, nil
+	}
+
+	note, err := r.GetNoteByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return currentRevision(note), nil
+}
+
+// SetRevisions replaces the history of a note
+func (r *sqliteRepository) SetRevisions(id string, revisions []*entities.Revision) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM revisions WHERE note_id = ?", id); err != nil {
+		return fmt.Errorf("failed to replace history of note %s: %w", id, err)
+	}
+	if err := insertRevisions(tx, id, revisions); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// recordRevisionTx adds note to its history inside a transaction; see appendRevision
+func recordRevisionTx(tx *sql.Tx, note *entities.Note) error {
+	var previous *entities.Note
+	var data string
+	err := tx.QueryRow("SELECT data FROM notes WHERE id = ?", note.ID).Scan(&data)
+	switch {
+	case err == nil:
+		if previous, err = entities.FromJSON([]byte(data)); err != nil {
+			return fmt.Errorf("failed to decode note %s: %w", note.ID, err)
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return fmt.Errorf("failed to read note %s: %w", note.ID, err)
+	}
+
+	history, err := queryRevisions(tx, note.ID)
+	if err != nil {
+		return err
+	}
+
+	updated, added := appendRevision(history, previous, note)
+	if !added {
+		return nil
+	}
+
+	return insertRevisions(tx, note.ID, updated[len(history):])
+}
+
+// queryRevisions reads the revisions of a note, oldest first
+func queryRevisions(q interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}, id string) ([]*entities.Revision, error) {
+	rows, err := q.Query("SELECT number, saved_at, data FROM revisions WHERE note_id = ? ORDER BY number", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history of note %s: %w", id, err)
+	}
+	defer rows.Close()
+
+	var history []*entities.Revision
+	for rows.Next() {
+		var number int
+		var savedAt int64
+		var data string
+		if err := rows.Scan(&number, &savedAt, &data); err != nil {
+			return nil, fmt.Errorf("failed to read history of note %s: %w", id, err)
+		}
+
+		note, err := entities.FromJSON([]byte(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode history of note %s: %w", id, err)
+		}
+
+		history = append(history, &entities.Revision{Number: number, SavedAt: time.Unix(0, savedAt), Note: note})
+	}
+
+	return history, rows.Err()
+}
+
+// insertRevisions stores revisions of a note inside a transaction
+func insertRevisions(tx *sql.Tx, id string, revisions []*entities.Revision) error {
+	for _, revision := range revisions {
+		data, err := json.Marshal(revision.Note)
+		if err != nil {
+			return fmt.Errorf("failed to encode note: %w", err)
+		}
+
+		if _, err := tx.Exec(
+			"INSERT OR REPLACE INTO revisions (note_id, number, saved_at, data) VALUES (?, ?, ?, ?)",
+			id, revision.Number, revision.SavedAt.UnixNano(), string(data),
+		); err != nil {
+			return fmt.Errorf("failed to save history of note %s: %w", id, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteRepositoryOpensPathsWithURISyntax(t *testing.T) {
+	for _, name := range []string{"notes?mode=ro", "notes #1", "100% notes", "notes%3Fx"} {
+		dir := filepath.Join(t.TempDir(), name)
+		path := filepath.Join(dir, "notes.db")
+
+		repo, err := NewSQLiteRepository(path)
+		if err != nil {
+			t.Errorf("NewSQLiteRepository(%q) failed: %v", path, err)
+			continue
+		}
+
+		note := entities.NewNote("stored in " + name)
+		if err := repo.Save(note); err != nil {
+			t.Errorf("Save in %q failed: %v", name, err)
+		}
+		repo.Close()
+
+		// The database is the file that was asked for, and nothing else was created
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, entry := range entries {
+			if entry.Name() == "notes.db" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("no notes.db in %q, got %v", dir, entries)
+		}
+
+		reopened, err := NewSQLiteRepository(path)
+		if err != nil {
+			t.Fatalf("reopening %q failed: %v", path, err)
+		}
+		if _, err := reopened.GetNoteByID(note.ID); err != nil {
+			t.Errorf("note saved in %q is missing after reopening: %v", name, err)
+		}
+		reopened.Close()
+	}
+}
+
+func TestSQLiteRepositoryOpensRelativePath(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	repo, err := NewSQLiteRepository(filepath.Join("data", "notes.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository failed: %v", err)
+	}
+	repo.Close()
+
+	if _, err := os.Stat(filepath.Join("data", "notes.db")); err != nil {
+		t.Errorf("database was not created at the relative path: %v", err)
+	}
+}
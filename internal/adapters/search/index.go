@@ -0,0 +1,415 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"jotterxpress/internal/adapters/fsutil"
+	"jotterxpress/internal/domain/entities"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	// indexVersion is bumped whenever the on-disk format or tokenizer changes
+	indexVersion = 1
+
+	// fieldGap separates the positions of different fields so that a
+	// phrase never matches across two fields
+	fieldGap = 100
+
+	// BM25 ranking parameters
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// document holds the positions of every term of one note
+type document struct {
+	Length int              `json:"length"`
+	Terms  map[string][]int `json:"terms"`
+}
+
+// indexFile is the on-disk representation of the index
+type indexFile struct {
+	Version int                  `json:"version"`
+	Docs    map[string]*document `json:"docs"`
+}
+
+// Hit is a single search result
+type Hit struct {
+	ID    string
+	Score float64
+}
+
+// Index is a persistent inverted index over note content and metadata
+type Index struct {
+	path string
+
+	docs     map[string]*document
+	postings map[string]map[string][]int // term -> note ID -> positions
+	terms    []string                    // sorted, for prefix lookups
+}
+
+// NewIndex creates an index stored at the given path. An empty path keeps
+// the index in memory only, so it never writes note text to disk.
+func NewIndex(path string) *Index {
+	return &Index{path: path}
+}
+
+// Exists reports whether the index has been built on disk
+func (idx *Index) Exists() bool {
+	if idx.path == "" {
+		return false
+	}
+
+	_, err := os.Stat(idx.path)
+	return err == nil
+}
+
+// Rebuild replaces the whole index with the given notes
+func (idx *Index) Rebuild(notes []*entities.Note) error {
+	docs := make(map[string]*document, len(notes))
+	for _, note := range notes {
+		docs[note.ID] = buildDocument(note)
+	}
+
+	if idx.path != "" {
+		unlock, err := fsutil.LockFile(idx.path + ".lock")
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if err := idx.write(docs); err != nil {
+			return fmt.Errorf("failed to write search index: %w", err)
+		}
+	}
+
+	idx.setDocs(docs)
+	return nil
+}
+
+// Add indexes a new note or re-indexes a changed one. If the index cannot be
+// updated it is invalidated, and rebuilt by the next search.
+func (idx *Index) Add(note *entities.Note) {
+	idx.update(func(docs map[string]*document) {
+		docs[note.ID] = buildDocument(note)
+	})
+}
+
+// Remove drops a note from the index. If the index cannot be updated it is
+// invalidated, and rebuilt by the next search.
+func (idx *Index) Remove(id string) {
+	idx.update(func(docs map[string]*document) {
+		delete(docs, id)
+	})
+}
+
+// Search returns the notes matching every clause of the query, best first.
+// Bare words must all match, "quoted phrases" must match in order and a
+// trailing * turns a word into a prefix. A limit of 0 returns every hit.
+func (idx *Index) Search(query string, limit int) ([]Hit, error) {
+	clauses := parseQuery(query)
+	if len(clauses) == 0 {
+		return nil, fmt.Errorf("search query cannot be empty")
+	}
+
+	if idx.docs == nil {
+		docs, err := idx.read()
+		if err != nil {
+			return nil, err
+		}
+		idx.setDocs(docs)
+	}
+
+	if len(idx.docs) == 0 {
+		return nil, nil
+	}
+
+	totalLength := 0
+	for _, doc := range idx.docs {
+		totalLength += doc.Length
+	}
+	avgLength := float64(totalLength) / float64(len(idx.docs))
+	if avgLength == 0 {
+		avgLength = 1
+	}
+
+	var scores map[string]float64
+	for i, c := range clauses {
+		matches := idx.matchClause(c)
+		df := float64(len(matches))
+		idf := math.Log(1 + (float64(len(idx.docs))-df+0.5)/(df+0.5))
+
+		next := make(map[string]float64, len(matches))
+		for id, count := range matches {
+			if i > 0 {
+				if _, ok := scores[id]; !ok {
+					continue
+				}
+			}
+			tf := float64(count)
+			norm := 1 - bm25B + bm25B*float64(idx.docs[id].Length)/avgLength
+			next[id] = scores[id] + idf*tf*(bm25K1+1)/(tf+bm25K1*norm)
+		}
+		scores = next
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits, nil
+}
+
+// matchClause returns, for every note matching the clause, how many times it matches
+func (idx *Index) matchClause(c clause) map[string]int {
+	// Expand each token into the index terms it stands for
+	expansions := make([][]string, len(c.tokens))
+	for i, token := range c.tokens {
+		if c.prefix && i == len(c.tokens)-1 {
+			expansions[i] = idx.expandPrefix(token)
+		} else if _, ok := idx.postings[token]; ok {
+			expansions[i] = []string{token}
+		}
+		if len(expansions[i]) == 0 {
+			return nil
+		}
+	}
+
+	matches := make(map[string]int)
+	for _, term := range expansions[0] {
+		for id, positions := range idx.postings[term] {
+			doc := idx.docs[id]
+			for _, start := range positions {
+				if phraseAt(doc, expansions, start) {
+					matches[id]++
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+// expandPrefix returns all indexed terms starting with prefix
+func (idx *Index) expandPrefix(prefix string) []string {
+	var terms []string
+	for i := sort.SearchStrings(idx.terms, prefix); i < len(idx.terms); i++ {
+		if !strings.HasPrefix(idx.terms[i], prefix) {
+			break
+		}
+		terms = append(terms, idx.terms[i])
+	}
+	return terms
+}
+
+// phraseAt reports whether the remaining phrase tokens follow the first one
+// at position start
+func phraseAt(doc *document, expansions [][]string, start int) bool {
+	for k := 1; k < len(expansions); k++ {
+		found := false
+		for _, term := range expansions[k] {
+			positions := doc.Terms[term]
+			i := sort.SearchInts(positions, start+k)
+			if i < len(positions) && positions[i] == start+k {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// update applies a change to the on-disk index while holding its lock, so
+// concurrent jtx processes never drop each other's changes
+func (idx *Index) update(change func(docs map[string]*document)) {
+	if idx.path == "" {
+		return
+	}
+
+	unlock, err := fsutil.LockFile(idx.path + ".lock")
+	if err != nil {
+		idx.invalidate()
+		return
+	}
+	defer unlock()
+
+	// An index that was never built is created in full by the next search
+	if !idx.Exists() {
+		return
+	}
+
+	docs, err := idx.read()
+	if err != nil {
+		idx.invalidate()
+		return
+	}
+
+	change(docs)
+
+	if err := idx.write(docs); err != nil {
+		idx.invalidate()
+		return
+	}
+
+	idx.setDocs(docs)
+}
+
+// invalidate removes the index so that the next search rebuilds it
+func (idx *Index) invalidate() {
+	os.Remove(idx.path)
+	idx.docs = nil
+}
+
+// RemoveIndex deletes the index stored at path, if any
+func RemoveIndex(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// read loads the documents from disk; a missing index is empty
+func (idx *Index) read() (map[string]*document, error) {
+	if idx.path == "" {
+		return make(map[string]*document), nil
+	}
+
+	data, err := os.ReadFile(idx.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]*document), nil
+		}
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	}
+
+	var file indexFile
+	if err := json.Unmarshal(data, &file); err != nil || file.Version != indexVersion || file.Docs == nil {
+		return nil, fmt.Errorf("search index is outdated or damaged, run 'jtx search --reindex'")
+	}
+
+	return file.Docs, nil
+}
+
+// write stores the documents on disk
+func (idx *Index) write(docs map[string]*document) error {
+	data, err := json.Marshal(indexFile{Version: indexVersion, Docs: docs})
+	if err != nil {
+		return err
+	}
+
+	return fsutil.WriteFileAtomic(idx.path, data)
+}
+
+// setDocs replaces the in-memory documents and rebuilds the postings
+func (idx *Index) setDocs(docs map[string]*document) {
+	idx.docs = docs
+	idx.postings = make(map[string]map[string][]int)
+
+	for id, doc := range docs {
+		for term, positions := range doc.Terms {
+			if idx.postings[term] == nil {
+				idx.postings[term] = make(map[string][]int)
+			}
+			idx.postings[term][id] = positions
+		}
+	}
+
+	idx.terms = make([]string, 0, len(idx.postings))
+	for term := range idx.postings {
+		idx.terms = append(idx.terms, term)
+	}
+	sort.Strings(idx.terms)
+}
+
+// buildDocument tokenizes the searchable fields of a note
+func buildDocument(note *entities.Note) *document {
+	fields := []string{
+		note.Content,
+		note.Metadata.Body,
+		note.Metadata.Assignee,
+		note.Metadata.Email,
+		note.Metadata.Phone,
+		note.Metadata.Address,
+		note.Metadata.Category,
+	}
+	fields = append(fields, note.Metadata.Tags...)
+
+	doc := &document{Terms: make(map[string][]int)}
+	position := 0
+	for _, field := range fields {
+		tokens := tokenize(field)
+		if len(tokens) == 0 {
+			continue
+		}
+		for _, token := range tokens {
+			doc.Terms[token] = append(doc.Terms[token], position)
+			position++
+		}
+		doc.Length += len(tokens)
+		position += fieldGap
+	}
+
+	return doc
+}
+
+// clause is one part of a query: a single term or a phrase, where the last
+// token may be a prefix
+type clause struct {
+	tokens []string
+	prefix bool
+}
+
+// parseQuery splits a query into clauses
+func parseQuery(query string) []clause {
+	var clauses []clause
+
+	for {
+		query = strings.TrimSpace(query)
+		if query == "" {
+			return clauses
+		}
+
+		var text string
+		if query[0] == '"' {
+			end := strings.IndexByte(query[1:], '"')
+			if end < 0 {
+				text, query = query[1:], ""
+			} else {
+				text, query = query[1:end+1], query[end+2:]
+			}
+		} else {
+			end := strings.IndexAny(query, " \t")
+			if end < 0 {
+				text, query = query, ""
+			} else {
+				text, query = query[:end], query[end:]
+			}
+		}
+
+		text = strings.TrimSpace(text)
+		prefix := strings.HasSuffix(text, "*")
+
+		// A word such as an email address that splits into several tokens
+		// is matched as a phrase
+		if tokens := tokenize(text); len(tokens) > 0 {
+			clauses = append(clauses, clause{tokens: tokens, prefix: prefix})
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package search
+
+import (
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newNote returns a note with a fixed ID
+func newNote(id, content string, tags ...string) *entities.Note {
+	note := entities.NewNote(content)
+	note.ID = id
+	note.Metadata.Tags = tags
+	return note
+}
+
+// hitIDs returns the IDs of hits in order
+func hitIDs(hits []Hit) string {
+	var ids []string
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+// testNotes are indexed by the search tests
+var testNotes = []*entities.Note{
+	newNote("milk", "Buy milk and eggs"),
+	newNote("milk-often", "milk milk milk"),
+	newNote("cafe", "Meet Ana at the café", "meeting"),
+	newNote("plan", "Project planning for the launch", "planning"),
+	newNote("planet", "Read about the planets"),
+	newNote("email", "Mail ana@example.com about the plan"),
+	newNote("split", "Eggs are in the fridge, buy bread"),
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"single word", "eggs", "milk,split"},
+		{"case and accents fold", "CAFE", "cafe"},
+		{"all words must match", "buy eggs", "milk,split"},
+		{"missing word matches nothing", "buy eggs cheese", ""},
+		{"phrase keeps word order", `"buy milk"`, "milk"},
+		{"phrase does not match scattered words", `"eggs buy"`, ""},
+		{"phrase and word", `"buy milk" eggs`, "milk"},
+		{"prefix", "plan*", "plan,planet,email"},
+		{"prefix inside a phrase", `"project plan*"`, "plan"},
+		{"word is not a prefix", "plan", "email"},
+		{"address matches as a phrase", "ana@example.com", "email"},
+		{"tags are searched", "meeting", "cafe"},
+		{"phrase never spans two fields", `"cafe meeting"`, ""},
+		{"unknown word", "zebra", ""},
+	}
+
+	idx := NewIndex(filepath.Join(t.TempDir(), ".search-index.json"))
+	if err := idx.Rebuild(testNotes); err != nil {
+		t.Fatalf("Rebuild failed: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits, err := idx.Search(tt.query, 0)
+			if err != nil {
+				t.Fatalf("Search(%q) failed: %v", tt.query, err)
+			}
+
+			// Compare as sets; ranking has its own test
+			got := strings.Split(hitIDs(hits), ",")
+			want := strings.Split(tt.want, ",")
+			if len(got) != len(want) {
+				t.Fatalf("Search(%q) = %s, want %s", tt.query, hitIDs(hits), tt.want)
+			}
+			for _, id := range want {
+				if !strings.Contains(","+hitIDs(hits)+",", ","+id+",") {
+					t.Errorf("Search(%q) = %s, want %s", tt.query, hitIDs(hits), tt.want)
+				}
+			}
+		})
+	}
+
+	if _, err := idx.Search(`  "" `, 0); err == nil {
+		t.Error("Search accepted an empty query")
+	}
+}
+
+func TestSearchRanksByBM25(t *testing.T) {
+	idx := NewIndex("")
+	if err := idx.Rebuild(testNotes); err != nil {
+		t.Fatalf("Rebuild failed: %v", err)
+	}
+
+	tests := []struct {
+		query string
+		limit int
+		want  string
+	}{
+		// More occurrences rank higher
+		{"milk", 0, "milk-often,milk"},
+		// Shorter notes rank higher for the same number of occurrences
+		{"eggs", 0, "milk,split"},
+		{"buy", 0, "milk,split"},
+		// The limit keeps the best hits
+		{"milk", 1, "milk-often"},
+	}
+
+	for _, tt := range tests {
+		hits, err := idx.Search(tt.query, tt.limit)
+		if err != nil {
+			t.Fatalf("Search(%q) failed: %v", tt.query, err)
+		}
+		if hitIDs(hits) != tt.want {
+			t.Errorf("Search(%q, %d) = %s, want %s", tt.query, tt.limit, hitIDs(hits), tt.want)
+		}
+		for i := 1; i < len(hits); i++ {
+			if hits[i].Score > hits[i-1].Score {
+				t.Errorf("Search(%q) hits are not ordered by score: %+v", tt.query, hits)
+			}
+		}
+	}
+
+	// A rare word counts for more than a common one
+	rare, _ := idx.Search("fridge", 0)
+	common, _ := idx.Search("eggs", 0)
+	if len(rare) != 1 || len(common) != 2 || rare[0].Score <= common[1].Score {
+		t.Errorf("rare word scores %+v, common word scores %+v, want the rare word higher", rare, common)
+	}
+}
+
+func TestIndexPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".search-index.json")
+
+	idx := NewIndex(path)
+	if idx.Exists() {
+		t.Fatal("index exists before it was built")
+	}
+
+	// Changes to an index that was never built are left to the first rebuild
+	idx.Add(newNote("early", "early note"))
+	if idx.Exists() {
+		t.Fatal("Add created a partial index")
+	}
+
+	if err := idx.Rebuild(testNotes); err != nil {
+		t.Fatalf("Rebuild failed: %v", err)
+	}
+	idx.Add(newNote("late", "late note"))
+	idx.Remove("cafe")
+
+	// Another process reads the changes from disk
+	other := NewIndex(path)
+	if !other.Exists() {
+		t.Fatal("index was not written")
+	}
+	if hits, _ := other.Search("note", 0); hitIDs(hits) != "late" {
+		t.Errorf("Search(note) = %s, want late", hitIDs(hits))
+	}
+	if hits, _ := other.Search("cafe", 0); len(hits) != 0 {
+		t.Errorf("Search(cafe) = %s after Remove, want nothing", hitIDs(hits))
+	}
+
+	if err := RemoveIndex(path); err != nil || other.Exists() {
+		t.Errorf("RemoveIndex left the index (%v)", err)
+	}
+	if err := RemoveIndex(path); err != nil {
+		t.Errorf("RemoveIndex of a missing index failed: %v", err)
+	}
+}
+
+func TestIndexRecoversFromDamagedFile(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"corrupt", `{"version": 1, "docs": {`},
+		{"outdated", `{"version": 0, "docs": {}}`},
+		{"empty", ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".search-index.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			idx := NewIndex(path)
+			if _, err := idx.Search("milk", 0); err == nil || !strings.Contains(err.Error(), "--reindex") {
+				t.Errorf("Search over a damaged index returned %v, want a hint to reindex", err)
+			}
+
+			// A change that cannot be applied drops the index, so the next
+			// search rebuilds it
+			idx.Add(newNote("new", "new note"))
+			if idx.Exists() {
+				t.Fatal("damaged index was kept after a failed update")
+			}
+
+			if err := idx.Rebuild(testNotes); err != nil {
+				t.Fatalf("Rebuild failed: %v", err)
+			}
+			if hits, err := NewIndex(path).Search("milk", 0); err != nil || len(hits) != 2 {
+				t.Errorf("Search after rebuilding = %s (%v), want 2 hits", hitIDs(hits), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package search
+
+import (
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+)
+
+// indexedRepository keeps the search index in step with every write to the
+// wrapped repository
+type indexedRepository struct {
+	ports.NoteRepository
+	index *Index
+}
+
+// NewIndexedRepository wraps a repository so that saving, moving, deleting
+// and restoring notes also updates the search index
+func NewIndexedRepository(repository ports.NoteRepository, index *Index) ports.NoteRepository {
+	return &indexedRepository{NoteRepository: repository, index: index}
+}
+
+// Save saves a note and indexes it
+func (r *indexedRepository) Save(note *entities.Note) error {
+	if err := r.NoteRepository.Save(note); err != nil {
+		return err
+	}
+
+	r.index.Add(note)
+	return nil
+}
+
+// MoveNote stores a note under a new date and indexes it again
+func (r *indexedRepository) MoveNote(note *entities.Note, date string) error {
+	if err := r.NoteRepository.MoveNote(note, date); err != nil {
+		return err
+	}
+
+	r.index.Add(note)
+	return nil
+}
+
+// DeleteNote moves a note to the trash and removes it from the index
+func (r *indexedRepository) DeleteNote(id string) error {
+	if err := r.NoteRepository.DeleteNote(id); err != nil {
+		return err
+	}
+
+	r.index.Remove(id)
+	return nil
+}
+
+// RestoreNote restores a note from the trash and indexes it again
+func (r *indexedRepository) RestoreNote(id string) (*entities.Note, error) {
+	note, err := r.NoteRepository.RestoreNote(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.index.Add(note)
+	return note, nil
+}
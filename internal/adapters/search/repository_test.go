@@ -0,0 +1,70 @@
+package search
+
+import (
+	"jotterxpress/internal/adapters/repository"
+	"path/filepath"
+	"testing"
+)
+
+func TestIndexedRepositoryKeepsIndexInStep(t *testing.T) {
+	idx := NewIndex(filepath.Join(t.TempDir(), ".search-index.json"))
+	if err := idx.Rebuild(nil); err != nil {
+		t.Fatalf("Rebuild failed: %v", err)
+	}
+	repo := NewIndexedRepository(repository.NewMemoryRepository(), idx)
+
+	expect := func(query, want string) {
+		t.Helper()
+		// Read from disk, as the next jtx process would
+		hits, err := NewIndex(idx.path).Search(query, 0)
+		if err != nil {
+			t.Fatalf("Search(%q) failed: %v", query, err)
+		}
+		if hitIDs(hits) != want {
+			t.Errorf("Search(%q) = %s, want %s", query, hitIDs(hits), want)
+		}
+	}
+
+	note := newNote("n1", "buy milk")
+	if err := repo.Save(note); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	expect("milk", "n1")
+
+	note.Content = "buy bread"
+	if err := repo.Save(note); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	expect("milk", "")
+	expect("bread", "n1")
+
+	// A moved note is indexed with the changes it was moved with
+	note.Content = "buy bread and eggs"
+	if err := repo.MoveNote(note, "2025-03-12"); err != nil {
+		t.Fatalf("MoveNote failed: %v", err)
+	}
+	expect("eggs", "n1")
+
+	if err := repo.DeleteNote(note.ID); err != nil {
+		t.Fatalf("DeleteNote failed: %v", err)
+	}
+	expect("bread", "")
+
+	if _, err := repo.RestoreNote(note.ID); err != nil {
+		t.Fatalf("RestoreNote failed: %v", err)
+	}
+	expect("bread", "n1")
+
+	// Failed writes leave the index alone
+	if err := repo.DeleteNote("missing"); err == nil {
+		t.Error("DeleteNote of a missing note succeeded")
+	}
+	if err := repo.MoveNote(newNote("missing", "buy milk"), "2025-03-12"); err == nil {
+		t.Error("MoveNote of a missing note succeeded")
+	}
+	if _, err := repo.RestoreNote("missing"); err == nil {
+		t.Error("RestoreNote of a missing note succeeded")
+	}
+	expect("bread", "n1")
+	expect("milk", "")
+}
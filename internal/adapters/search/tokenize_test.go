@@ -0,0 +1,57 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"Buy MILK", []string{"buy", "milk"}},
+		{"  call   mom, then\tdad!", []string{"call", "mom", "then", "dad"}},
+		{"ana@example.com", []string{"ana", "example", "com"}},
+		{"Meet at 10:30 on 2025-10-16", []string{"meet", "at", "10", "30", "on", "2025", "10", "16"}},
+		{"Aquí, café and Ñandú", []string{"aqui", "cafe", "and", "nandu"}},
+		{"naïve — déjà vu", []string{"naive", "deja", "vu"}},
+	}
+
+	for _, tt := range tests {
+		got := tokenize(tt.text)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []clause
+	}{
+		{"   ", nil},
+		{"milk eggs", []clause{{tokens: []string{"milk"}}, {tokens: []string{"eggs"}}}},
+		{`"buy milk" today`, []clause{{tokens: []string{"buy", "milk"}}, {tokens: []string{"today"}}}},
+		{`"unterminated phrase`, []clause{{tokens: []string{"unterminated", "phrase"}}}},
+		{"meet*", []clause{{tokens: []string{"meet"}, prefix: true}}},
+		{`"project pla*"`, []clause{{tokens: []string{"project", "pla"}, prefix: true}}},
+		{"ana@example.com", []clause{{tokens: []string{"ana", "example", "com"}}}},
+		{`* "" ,`, nil},
+	}
+
+	for _, tt := range tests {
+		got := parseQuery(tt.query)
+		if len(got) != len(tt.want) {
+			t.Errorf("parseQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if strings.Join(got[i].tokens, "|") != strings.Join(tt.want[i].tokens, "|") || got[i].prefix != tt.want[i].prefix {
+				t.Errorf("parseQuery(%q) clause %d = %+v, want %+v", tt.query, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
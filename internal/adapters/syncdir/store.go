@@ -0,0 +1,314 @@
+package syncdir
+
+import (
+	"encoding/json"
+	"fmt"
+	"jotterxpress/internal/adapters/fsutil"
+	"jotterxpress/internal/adapters/repository"
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	// markerFileName marks a directory as a sync folder
+	markerFileName = ".jtx-sync.json"
+
+	// notesDirName holds one file per note
+	notesDirName = "notes"
+
+	// tombstonesDirName holds one file per deleted note
+	tombstonesDirName = "tombstones"
+
+	// AttachmentsDirName holds the attachment store of the sync folder
+	AttachmentsDirName = "attachments"
+
+	// syncFormat is the version of the sync folder layout
+	syncFormat = 1
+)
+
+// marker is the content of the marker file
+type marker struct {
+	Format int `json:"format"`
+}
+
+// Store is a sync folder: a directory shared between machines, with one file
+// per note and per tombstone. Two machines that change different notes never
+// write the same file, so file sync tools such as Syncthing pass both
+// changes on.
+type Store struct {
+	dir string
+
+	// paths records every file read for a note, so copies left by file
+	// sync tools are removed when the note is written
+	mu    sync.Mutex
+	paths map[string][]string
+
+	unlock     func() (*repository.Cipher, error)
+	unlockOnce sync.Once
+	cipher     *repository.Cipher
+	unlockErr  error
+}
+
+// NewStore opens the sync folder at dir, creating it when it does not exist.
+// A directory that already holds other files is refused.
+func NewStore(dir string) (*Store, error) {
+	if err := prepare(dir); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir, paths: make(map[string][]string)}, nil
+}
+
+// NewEncryptedStore opens the sync folder at dir like NewStore, sealing
+// notes before they are written. unlock is called once, the first time a
+// note has to be sealed or opened.
+func NewEncryptedStore(dir string, unlock func() (*repository.Cipher, error)) (*Store, error) {
+	store, err := NewStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	store.unlock = unlock
+	return store, nil
+}
+
+// prepare creates a sync folder or checks that dir is one
+func prepare(dir string) error {
+	markerPath := filepath.Join(dir, markerFileName)
+	data, err := os.ReadFile(markerPath)
+	if err == nil {
+		var m marker
+		if err := json.Unmarshal(data, &m); err != nil {
+			return fmt.Errorf("%s has an invalid sync marker: %w", dir, err)
+		}
+		if m.Format > syncFormat {
+			return fmt.Errorf("%s was written by a newer jtx (format %d)", dir, m.Format)
+		}
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", markerPath, err)
+	}
+
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("%s is not empty and is not a jtx sync folder", dir)
+	}
+
+	data, err = json.Marshal(marker{Format: syncFormat})
+	if err != nil {
+		return err
+	}
+	if err := fsutil.WriteFileAtomic(markerPath, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to create sync folder: %w", err)
+	}
+
+	return nil
+}
+
+// Dir returns the directory of the sync folder
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// getCipher unlocks the store on first use
+func (s *Store) getCipher() (*repository.Cipher, error) {
+	s.unlockOnce.Do(func() {
+		s.cipher, s.unlockErr = s.unlock()
+		if s.unlockErr != nil {
+			s.unlockErr = fmt.Errorf("failed to unlock notes: %w", s.unlockErr)
+		}
+	})
+	return s.cipher, s.unlockErr
+}
+
+// fileName returns the file name for a note ID, see fsutil.SafeName
+func fileName(id string) string {
+	return fsutil.SafeName(id) + ".json"
+}
+
+// readDir decodes every JSON file of a subdirectory into a new value of T.
+// Hidden and temporary files are skipped.
+func readDir[T any](dir string, visit func(path string, value *T) error) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		value := new(T)
+		if err := json.Unmarshal(data, value); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", path, err)
+		}
+		if err := visit(path, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetNotes retrieves every note in the sync folder. When a file sync tool
+// left several files for one note, the one updated last is used.
+func (s *Store) GetNotes() ([]*entities.Note, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byID := make(map[string]*entities.Note)
+	var order []string
+	s.paths = make(map[string][]string)
+
+	err := readDir(filepath.Join(s.dir, notesDirName), func(path string, note *entities.Note) error {
+		if note.ID == "" {
+			return fmt.Errorf("%s holds a note without an ID", path)
+		}
+
+		opened, err := s.open(note)
+		if err != nil {
+			return err
+		}
+
+		s.paths[note.ID] = append(s.paths[note.ID], path)
+		existing, ok := byID[note.ID]
+		if !ok {
+			order = append(order, note.ID)
+		}
+		if !ok || opened.UpdatedAt.After(existing.UpdatedAt) {
+			byID[note.ID] = opened
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync folder: %w", err)
+	}
+
+	notes := make([]*entities.Note, 0, len(order))
+	for _, id := range order {
+		notes = append(notes, byID[id])
+	}
+
+	return notes, nil
+}
+
+// GetTombstones retrieves the deletions recorded in the sync folder
+func (s *Store) GetTombstones() ([]*entities.Tombstone, error) {
+	var tombstones []*entities.Tombstone
+	err := readDir(filepath.Join(s.dir, tombstonesDirName), func(path string, tombstone *entities.Tombstone) error {
+		if tombstone.ID != "" {
+			tombstones = append(tombstones, tombstone)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync folder: %w", err)
+	}
+
+	return tombstones, nil
+}
+
+// SaveNote writes a note to the sync folder and removes its tombstone
+func (s *Store) SaveNote(note *entities.Note) error {
+	sealed, err := s.seal(note)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(sealed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode note %s: %w", note.ID, err)
+	}
+
+	path := filepath.Join(s.dir, notesDirName, fileName(note.ID))
+	if err := fsutil.WriteFileAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write note %s: %w", note.ID, err)
+	}
+
+	if err := s.removeOthers(note.ID, path); err != nil {
+		return err
+	}
+	return removeFile(filepath.Join(s.dir, tombstonesDirName, fileName(note.ID)))
+}
+
+// DeleteNote removes a note from the sync folder and records its tombstone
+func (s *Store) DeleteNote(tombstone *entities.Tombstone) error {
+	data, err := json.MarshalIndent(tombstone, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tombstone of %s: %w", tombstone.ID, err)
+	}
+
+	path := filepath.Join(s.dir, tombstonesDirName, fileName(tombstone.ID))
+	if err := fsutil.WriteFileAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write tombstone of %s: %w", tombstone.ID, err)
+	}
+
+	if err := s.removeOthers(tombstone.ID, ""); err != nil {
+		return err
+	}
+	return removeFile(filepath.Join(s.dir, notesDirName, fileName(tombstone.ID)))
+}
+
+// removeOthers removes the files read for a note other than keep
+func (s *Store) removeOthers(id, keep string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, path := range s.paths[id] {
+		if path == keep {
+			continue
+		}
+		if err := removeFile(path); err != nil {
+			return err
+		}
+	}
+	delete(s.paths, id)
+
+	return nil
+}
+
+// removeFile removes a file that may not exist
+func removeFile(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	return nil
+}
+
+// seal encrypts a note for an encrypted sync folder
+func (s *Store) seal(note *entities.Note) (*entities.Note, error) {
+	if s.unlock == nil {
+		return note, nil
+	}
+
+	c, err := s.getCipher()
+	if err != nil {
+		return nil, err
+	}
+	return c.SealNote(note)
+}
+
+// open decrypts a note of an encrypted sync folder
+func (s *Store) open(note *entities.Note) (*entities.Note, error) {
+	if s.unlock == nil {
+		return note, nil
+	}
+
+	c, err := s.getCipher()
+	if err != nil {
+		return nil, err
+	}
+	return c.OpenNote(note)
+}
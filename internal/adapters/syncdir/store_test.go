@@ -0,0 +1,71 @@
+package syncdir
+
+import (
+	"jotterxpress/internal/domain/entities"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStoreKeepsNewestCopy(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+
+	// IDs of notes migrated from text files are not safe file names
+	note := entities.NewNote("first")
+	note.ID = "2025-10-16-09:30:00"
+	if err := store.SaveNote(note); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+
+	// A file sync tool keeps the version of another machine next to it
+	newer := *note
+	newer.Content = "second"
+	newer.UpdatedAt = note.UpdatedAt.Add(time.Minute)
+	other, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+	if err := other.SaveNote(&newer); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+	path := filepath.Join(dir, notesDirName, fileName(note.ID))
+	conflict := filepath.Join(dir, notesDirName, "note.sync-conflict-20251016.json")
+	if err := os.Rename(path, conflict); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveNote(note); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+
+	notes, err := store.GetNotes()
+	if err != nil {
+		t.Fatalf("GetNotes failed: %v", err)
+	}
+	if len(notes) != 1 || notes[0].Content != "second" {
+		t.Fatalf("got %d notes, want only the newest copy", len(notes))
+	}
+
+	// Writing the note again leaves a single file
+	if err := store.SaveNote(notes[0]); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+	if _, err := os.Stat(conflict); !os.IsNotExist(err) {
+		t.Errorf("the copy of the file sync tool was not removed")
+	}
+}
+
+func TestNewStoreRefusesOtherDirectories(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "report.txt"), []byte("not notes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewStore(dir); err == nil {
+		t.Errorf("NewStore accepted a directory that is not a sync folder")
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// attachmentService implements the AttachmentService interface
+type attachmentService struct {
+	notes      ports.NoteService
+	repository ports.NoteRepository
+	store      ports.AttachmentStore
+}
+
+// NewAttachmentService creates a new attachment service. Notes are saved
+// through notes, so attaching a file is a change like any other; repository
+// is read to find the content that is still referenced.
+func NewAttachmentService(notes ports.NoteService, repository ports.NoteRepository, store ports.AttachmentStore) ports.AttachmentService {
+	return &attachmentService{
+		notes:      notes,
+		repository: repository,
+		store:      store,
+	}
+}
+
+// Attach stores content and records it on the note under name. A name the
+// note already uses gets a numbered suffix, so every attachment can be
+// opened by name.
+func (s *attachmentService) Attach(note *entities.Note, name string, content io.Reader) (*entities.Attachment, error) {
+	name = strings.TrimSpace(filepath.Base(name))
+	if name == "" || name == "." || name == string(filepath.Separator) {
+		return nil, fmt.Errorf("attachment name cannot be empty")
+	}
+
+	hash, size, err := s.store.Put(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	attachment := entities.Attachment{
+		Name:    uniqueAttachmentName(note.Metadata.Attachments, name),
+		SHA256:  hash,
+		Size:    size,
+		AddedAt: time.Now(),
+	}
+	note.Metadata.Attachments = append(note.Metadata.Attachments, attachment)
+
+	if err := s.notes.SaveNote(note); err != nil {
+		note.Metadata.Attachments = note.Metadata.Attachments[:len(note.Metadata.Attachments)-1]
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// OpenAttachment returns an attachment of a note and its content
+func (s *attachmentService) OpenAttachment(note *entities.Note, ref string) (*entities.Attachment, io.ReadCloser, error) {
+	attachments := note.Metadata.Attachments
+	if len(attachments) == 0 {
+		return nil, nil, fmt.Errorf("note %s has no attachments", note.ID)
+	}
+
+	var attachment *entities.Attachment
+	if n, err := strconv.Atoi(ref); err == nil && n >= 1 && n <= len(attachments) {
+		attachment = &attachments[n-1]
+	}
+	for i := range attachments {
+		if attachment == nil && attachments[i].Name == ref {
+			attachment = &attachments[i]
+		}
+	}
+	if attachment == nil {
+		return nil, nil, fmt.Errorf("note %s has no attachment %q", note.ID, ref)
+	}
+
+	content, err := s.store.Open(attachment.SHA256)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment %s: %w", attachment.Name, err)
+	}
+
+	return attachment, content, nil
+}
+
+// CollectGarbage removes stored content that nothing refers to anymore
+func (s *attachmentService) CollectGarbage() (int, error) {
+	return collectAttachments(s.repository, s.store)
+}
+
+// collectAttachments removes the content of store that no note, trashed note
+// or revision in repository refers to. Trashed notes and revisions keep their
+// attachments, so restoring or reverting a note never loses one.
+func collectAttachments(repository ports.NoteRepository, store ports.AttachmentStore) (int, error) {
+	stored, err := store.List()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	if len(stored) == 0 {
+		return 0, nil
+	}
+
+	notes, err := repository.GetAllNotes()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get notes: %w", err)
+	}
+	trash, err := repository.GetTrash()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get trash: %w", err)
+	}
+	for _, entry := range trash {
+		notes = append(notes, entry.Note)
+	}
+
+	referenced := make(map[string]bool)
+	for _, note := range notes {
+		for _, attachment := range note.Metadata.Attachments {
+			referenced[attachment.SHA256] = true
+		}
+
+		// Trashed notes only have history when they were edited
+		revisions, err := repository.GetRevisions(note.ID)
+		if err != nil && !errors.Is(err, ports.ErrNoteNotFound) {
+			return 0, fmt.Errorf("failed to get history of note %s: %w", note.ID, err)
+		}
+		for _, revision := range revisions {
+			for _, attachment := range revision.Note.Metadata.Attachments {
+				referenced[attachment.SHA256] = true
+			}
+		}
+	}
+
+	removed := 0
+	for _, hash := range stored {
+		if referenced[hash] {
+			continue
+		}
+		if err := store.Remove(hash); err != nil {
+			return removed, fmt.Errorf("failed to remove attachment %s: %w", hash, err)
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// uniqueAttachmentName returns name, or name with a number before its
+// extension when an attachment already uses it
+func uniqueAttachmentName(attachments []entities.Attachment, name string) string {
+	taken := make(map[string]bool, len(attachments))
+	for _, attachment := range attachments {
+		taken[attachment.Name] = true
+	}
+
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for n := 2; taken[name]; n++ {
+		name = fmt.Sprintf("%s-%d%s", base, n, ext)
+	}
+
+	return name
+}
+
+// attachmentCollector removes unreferenced attachment content after notes
+// are deleted
+type attachmentCollector struct {
+	repository ports.NoteRepository
+	store      ports.AttachmentStore
+	onError    func(error)
+}
+
+// NewAttachmentCollector creates a NoteListener that collects attachment
+// content nothing refers to whenever a note is deleted or the trash is
+// emptied. Failures never undo the change; they are passed to onError.
+func NewAttachmentCollector(repository ports.NoteRepository, store ports.AttachmentStore, onError func(error)) ports.NoteListener {
+	return &attachmentCollector{repository: repository, store: store, onError: onError}
+}
+
+// NoteChanged collects garbage after deletions
+func (c *attachmentCollector) NoteChanged(event ports.NoteEvent) {
+	if event.Kind != ports.NoteDeleted && event.Kind != ports.TrashEmptied {
+		return
+	}
+
+	if _, err := collectAttachments(c.repository, c.store); err != nil && c.onError != nil {
+		c.onError(err)
+	}
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"io"
+	"jotterxpress/internal/adapters/attachments"
+	"jotterxpress/internal/adapters/repository"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestAttachmentService returns a note service and an attachment service
+// over an empty in-memory repository and a store in a temporary directory
+func newTestAttachmentService(t *testing.T) (ports.NoteService, ports.AttachmentService, *attachments.Store) {
+	repo := repository.NewMemoryRepository()
+	store := attachments.NewStore(t.TempDir())
+	collector := NewAttachmentCollector(repo, store, func(err error) {
+		t.Errorf("collecting attachments failed: %v", err)
+	})
+
+	notes := NewNoteService(repo, collector)
+	return notes, NewAttachmentService(notes, repo, store), store
+}
+
+func TestAttachAndOpen(t *testing.T) {
+	notes, service, store := newTestAttachmentService(t)
+
+	note, err := notes.CreateNote("incident report")
+	if err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+
+	for _, content := range []string{"first log", "second log"} {
+		if _, err := service.Attach(note, "logs/app.log", strings.NewReader(content)); err != nil {
+			t.Fatalf("Attach failed: %v", err)
+		}
+	}
+
+	stored, err := notes.GetNoteByID(note.ID)
+	if err != nil {
+		t.Fatalf("GetNoteByID failed: %v", err)
+	}
+	if got := len(stored.Metadata.Attachments); got != 2 {
+		t.Fatalf("note has %d attachments, want 2", got)
+	}
+	if names := stored.Metadata.Attachments[0].Name + " " + stored.Metadata.Attachments[1].Name; names != "app.log app-2.log" {
+		t.Errorf("attachments are named %q, want %q", names, "app.log app-2.log")
+	}
+
+	for ref, want := range map[string]string{"1": "first log", "app-2.log": "second log"} {
+		_, content, err := service.OpenAttachment(stored, ref)
+		if err != nil {
+			t.Fatalf("OpenAttachment(%s) failed: %v", ref, err)
+		}
+		data, _ := io.ReadAll(content)
+		content.Close()
+		if string(data) != want {
+			t.Errorf("attachment %s holds %q, want %q", ref, data, want)
+		}
+	}
+
+	if _, _, err := service.OpenAttachment(stored, "3"); err == nil {
+		t.Error("OpenAttachment accepted a number past the last attachment")
+	}
+
+	// Attaching the same content again keeps a single copy
+	other, _ := notes.CreateNote("follow-up")
+	if _, err := service.Attach(other, "copy.log", strings.NewReader("first log")); err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+	if hashes, _ := store.List(); len(hashes) != 2 {
+		t.Errorf("store holds %d files, want 2", len(hashes))
+	}
+}
+
+func TestDeletedAttachmentsAreCollected(t *testing.T) {
+	notes, service, store := newTestAttachmentService(t)
+
+	kept, _ := notes.CreateNote("kept")
+	deleted, _ := notes.CreateNote("deleted")
+	if _, err := service.Attach(kept, "shared.txt", strings.NewReader("shared")); err != nil {
+		t.Fatalf("Attach failed: %v", err)
+	}
+	for _, content := range []string{"shared", "only deleted"} {
+		if _, err := service.Attach(deleted, "file.txt", strings.NewReader(content)); err != nil {
+			t.Fatalf("Attach failed: %v", err)
+		}
+	}
+
+	// The trashed note can still be restored with its attachments
+	if err := notes.DeleteNote(deleted.ID); err != nil {
+		t.Fatalf("DeleteNote failed: %v", err)
+	}
+	if hashes, _ := store.List(); len(hashes) != 2 {
+		t.Errorf("store holds %d files after deleting, want 2", len(hashes))
+	}
+
+	if _, err := notes.EmptyTrash(0); err != nil {
+		t.Fatalf("EmptyTrash failed: %v", err)
+	}
+	if hashes, _ := store.List(); len(hashes) != 1 {
+		t.Errorf("store holds %d files after emptying the trash, want 1", len(hashes))
+	}
+
+	_, content, err := service.OpenAttachment(kept, "shared.txt")
+	if err != nil {
+		t.Fatalf("shared attachment was collected: %v", err)
+	}
+	content.Close()
+}
+
+func TestTrashedNoteWithoutHistoryKeepsAttachments(t *testing.T) {
+	repos := map[string]interface {
+		ports.NoteRepository
+		AddToTrash(entry *entities.TrashedNote) error
+	}{
+		"memory": repository.NewMemoryRepository(),
+		"file":   repository.NewFileRepository(t.TempDir()),
+	}
+
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			store := attachments.NewStore(t.TempDir())
+			notes := NewNoteService(repo)
+			service := NewAttachmentService(notes, repo, store)
+
+			hash, size, err := store.Put(strings.NewReader("synced log"))
+			if err != nil {
+				t.Fatalf("Put failed: %v", err)
+			}
+			if _, _, err := store.Put(strings.NewReader("unused")); err != nil {
+				t.Fatalf("Put failed: %v", err)
+			}
+
+			// A note trashed on another machine arrives without history
+			note := entities.NewNote("synced")
+			note.Metadata.Attachments = []entities.Attachment{{Name: "app.log", SHA256: hash, Size: size}}
+			if err := repo.AddToTrash(&entities.TrashedNote{Note: note, DeletedAt: time.Now()}); err != nil {
+				t.Fatalf("AddToTrash failed: %v", err)
+			}
+
+			removed, err := service.CollectGarbage()
+			if err != nil {
+				t.Fatalf("CollectGarbage failed: %v", err)
+			}
+			if removed != 1 {
+				t.Errorf("CollectGarbage removed %d files, want 1", removed)
+			}
+			if hashes, _ := store.List(); len(hashes) != 1 || hashes[0] != hash {
+				t.Errorf("store holds %v, want only the trashed note's attachment", hashes)
+			}
+		})
+	}
+}
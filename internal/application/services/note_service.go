@@ -1,9 +1,11 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"jotterxpress/internal/domain/entities"
 	"jotterxpress/internal/domain/ports"
+	"sort"
 	"strings"
 	"time"
 )
@@ -11,15 +13,38 @@ import (
 // noteService implements the NoteService interface
 type noteService struct {
 	repository ports.NoteRepository
+	listeners  []ports.NoteListener
 }
 
-// NewNoteService creates a new note service
-func NewNoteService(repository ports.NoteRepository) ports.NoteService {
+// NewNoteService creates a new note service. Listeners are notified after
+// every change the service stores, and those that implement NoteGuard are
+// asked before it.
+func NewNoteService(repository ports.NoteRepository, listeners ...ports.NoteListener) ports.NoteService {
 	return &noteService{
 		repository: repository,
+		listeners:  listeners,
 	}
 }
 
+// notify tells every listener about a stored change
+func (s *noteService) notify(event ports.NoteEvent) {
+	for _, listener := range s.listeners {
+		listener.NoteChanged(event)
+	}
+}
+
+// check asks every guard whether a change may be stored
+func (s *noteService) check(event ports.NoteEvent) error {
+	for _, listener := range s.listeners {
+		if guard, ok := listener.(ports.NoteGuard); ok {
+			if err := guard.BeforeChange(event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // CreateNote creates a new note with the given content
 func (s *noteService) CreateNote(content string) (*entities.Note, error) {
 	if strings.TrimSpace(content) == "" {
@@ -27,30 +52,277 @@ func (s *noteService) CreateNote(content string) (*entities.Note, error) {
 	}
 
 	note := entities.NewNote(content)
+	note.CollectTags()
 
+	if err := s.check(ports.NoteEvent{Kind: ports.NoteCreated, NoteID: note.ID, Note: note}); err != nil {
+		return nil, err
+	}
 	if err := s.repository.Save(note); err != nil {
 		return nil, fmt.Errorf("failed to save note: %w", err)
 	}
 
+	s.notify(ports.NoteEvent{Kind: ports.NoteCreated, NoteID: note.ID, Note: note})
 	return note, nil
 }
 
-// SaveNote saves an existing note
+// SaveNote saves a new or existing note
 func (s *noteService) SaveNote(note *entities.Note) error {
 	if strings.TrimSpace(note.Content) == "" {
 		return fmt.Errorf("note content cannot be empty")
 	}
 
-	// Update the updated_at timestamp
-	note.UpdatedAt = time.Now()
+	kind := ports.NoteUpdated
+	if _, err := s.repository.GetNoteByID(note.ID); errors.Is(err, ports.ErrNoteNotFound) {
+		kind = ports.NoteCreated
+	}
 
-	if err := s.repository.Save(note); err != nil {
+	return s.save(note, kind)
+}
+
+// save stores a note and notifies listeners with the given kind of change.
+// The note only changes once it is stored, so a refused or failed save
+// leaves it as it was.
+func (s *noteService) save(note *entities.Note, kind ports.NoteEventKind) error {
+	// Update the updated_at timestamp, pick up #hashtags and tidy the project
+	updated := draft(note)
+	updated.UpdatedAt = time.Now()
+	updated.CollectTags()
+	updated.Metadata.Category = entities.NormalizeProject(updated.Metadata.Category)
+
+	if err := s.check(ports.NoteEvent{Kind: kind, NoteID: note.ID, Note: updated}); err != nil {
+		return err
+	}
+	if err := s.repository.Save(updated); err != nil {
 		return fmt.Errorf("failed to save note: %w", err)
 	}
 
+	*note = *updated
+	s.notify(ports.NoteEvent{Kind: kind, NoteID: note.ID, Note: note})
 	return nil
 }
 
+// draft returns a copy of a note to change before storing it; the tags are
+// copied as they are the only part changed in place
+func draft(note *entities.Note) *entities.Note {
+	copied := *note
+	copied.Metadata.Tags = append([]string(nil), note.Metadata.Tags...)
+	return &copied
+}
+
+// GetNoteByID retrieves a note by its full ID, a unique ID prefix or its
+// ID alias
+func (s *noteService) GetNoteByID(ref string) (*entities.Note, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("note ID cannot be empty")
+	}
+
+	note, err := s.repository.GetNoteByID(ref)
+	if err == nil {
+		return note, nil
+	}
+	if !errors.Is(err, ports.ErrNoteNotFound) {
+		return nil, fmt.Errorf("failed to get note %s: %w", ref, err)
+	}
+
+	// Fall back to a unique prefix match, then to an alias
+	ids, err := s.findNoteIDs(ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find note %s: %w", ref, err)
+	}
+
+	switch len(ids) {
+	case 0:
+		return nil, fmt.Errorf("no note with ID %s: %w", ref, ports.ErrNoteNotFound)
+	case 1:
+		note, err := s.repository.GetNoteByID(ids[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get note %s: %w", ids[0], err)
+		}
+		return note, nil
+	default:
+		return nil, &ports.AmbiguousIDError{Prefix: ref, Matches: ids}
+	}
+}
+
+// findNoteIDs returns the IDs of the notes a reference may mean: the IDs it
+// is a prefix of, in any case, or else the IDs whose alias it is
+func (s *noteService) findNoteIDs(ref string) ([]string, error) {
+	ids, err := s.repository.FindNoteIDs(ref)
+	if err != nil || len(ids) > 0 {
+		return ids, err
+	}
+
+	// Note IDs are upper case, but are easier to type in lower case
+	if upper := strings.ToUpper(ref); upper != ref {
+		if ids, err := s.repository.FindNoteIDs(upper); err != nil || len(ids) > 0 {
+			return ids, err
+		}
+	}
+
+	if !entities.IsIDAlias(ref) {
+		return nil, nil
+	}
+
+	all, err := s.repository.FindNoteIDs("")
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range all {
+		if entities.IDAlias(id) == ref {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// GetHistory retrieves every saved version of a note, oldest first
+func (s *noteService) GetHistory(ref string) ([]*entities.Revision, error) {
+	note, err := s.GetNoteByID(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.repository.GetRevisions(note.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history of note %s: %w", note.ID, err)
+	}
+
+	return history, nil
+}
+
+// RevertNote restores the content and metadata of a note from one of its
+// revisions. The note keeps its ID, date and creation time, and the reverted
+// version is saved as a new revision.
+func (s *noteService) RevertNote(note *entities.Note, number int) error {
+	history, err := s.repository.GetRevisions(note.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get history of note %s: %w", note.ID, err)
+	}
+
+	for _, revision := range history {
+		if revision.Number != number {
+			continue
+		}
+
+		reverted := draft(note)
+		reverted.Type = revision.Note.Type
+		reverted.Content = revision.Note.Content
+		reverted.Metadata = revision.Note.Metadata
+
+		if err := s.save(reverted, ports.NoteReverted); err != nil {
+			return err
+		}
+		*note = *reverted
+		return nil
+	}
+
+	return fmt.Errorf("note %s has no revision %d", note.ID, number)
+}
+
+// MoveNote reschedules a note to another date
+func (s *noteService) MoveNote(note *entities.Note, date string) error {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
+	}
+
+	if note.Date == date {
+		return nil
+	}
+
+	moved := draft(note)
+	moved.UpdatedAt = time.Now()
+	if err := s.check(ports.NoteEvent{Kind: ports.NoteMoved, NoteID: note.ID, Note: moved}); err != nil {
+		return err
+	}
+	if err := s.repository.MoveNote(moved, date); err != nil {
+		return fmt.Errorf("failed to move note: %w", err)
+	}
+
+	*note = *moved
+	s.notify(ports.NoteEvent{Kind: ports.NoteMoved, NoteID: note.ID, Note: note})
+	return nil
+}
+
+// CompleteNote marks a task or reminder as completed
+func (s *noteService) CompleteNote(note *entities.Note) (*entities.Note, error) {
+	if note.Type != entities.NoteTypeTask && note.Type != entities.NoteTypeReminder {
+		return nil, fmt.Errorf("only tasks and reminders can be completed")
+	}
+
+	// Only the first completion of a recurring item creates the next one
+	wasOpen := note.Metadata.Status != entities.StatusCompleted
+	completed := draft(note)
+	completed.Metadata.Status = entities.StatusCompleted
+	if err := s.save(completed, ports.NoteCompleted); err != nil {
+		return nil, err
+	}
+	*note = *completed
+
+	if !wasOpen {
+		return nil, nil
+	}
+	next := note.NextOccurrence(time.Now())
+	if next == nil {
+		return nil, nil
+	}
+	if err := s.save(next, ports.NoteCreated); err != nil {
+		return nil, fmt.Errorf("completed, but failed to create the next occurrence: %w", err)
+	}
+
+	return next, nil
+}
+
+// GetRecurring retrieves the open tasks and reminders of every date that
+// have a repeat rule, the earliest scheduled first
+func (s *noteService) GetRecurring() ([]*entities.Note, error) {
+	notes, err := s.repository.GetAllNotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notes: %w", err)
+	}
+
+	var recurring []*entities.Note
+	for _, note := range notes {
+		if note.Metadata.Recurrence != nil && note.Metadata.Status != entities.StatusCompleted {
+			recurring = append(recurring, note)
+		}
+	}
+
+	sort.SliceStable(recurring, func(i, j int) bool {
+		return recurring[i].ScheduledDate().Before(recurring[j].ScheduledDate())
+	})
+
+	return recurring, nil
+}
+
+// PromoteIdea creates a task from an idea and links the two; the idea
+// becomes promoted
+func (s *noteService) PromoteIdea(idea *entities.Note, priority entities.Priority) (*entities.Note, error) {
+	if idea.Type != entities.NoteTypeIdea {
+		return nil, fmt.Errorf("only ideas can be promoted")
+	}
+	if idea.Metadata.LinkedNoteID != "" {
+		return nil, fmt.Errorf("idea was already promoted to task %s", entities.IDAlias(idea.Metadata.LinkedNoteID))
+	}
+
+	task := entities.NewTask(idea.Content, priority)
+	task.Metadata.LinkedNoteID = idea.ID
+	if err := s.save(task, ports.NoteCreated); err != nil {
+		return nil, err
+	}
+
+	promoted := draft(idea)
+	promoted.Metadata.Maturity = entities.MaturityPromoted
+	promoted.Metadata.LinkedNoteID = task.ID
+	if err := s.save(promoted, ports.NotePromoted); err != nil {
+		return nil, err
+	}
+	*idea = *promoted
+
+	return task, nil
+}
+
 // GetTodayNotes retrieves all notes for today
 func (s *noteService) GetTodayNotes() ([]*entities.Note, error) {
 	notes, err := s.repository.GetTodayNotes()
@@ -91,6 +363,128 @@ func (s *noteService) GetNotesByMonth(monthStr string) ([]*entities.Note, error)
 	return notes, nil
 }
 
+// GetDueTasks retrieves the open tasks of every date due at most days from
+// today, earliest first
+func (s *noteService) GetDueTasks(days int) ([]*entities.Note, error) {
+	notes, err := s.repository.GetAllNotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notes: %w", err)
+	}
+
+	now := time.Now()
+	var due []*entities.Note
+	for _, note := range notes {
+		if until, ok := note.DaysUntilDue(now); ok && until <= days && note.IsOpenTask() {
+			due = append(due, note)
+		}
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].Metadata.DueDate.Before(*due[j].Metadata.DueDate)
+	})
+
+	return due, nil
+}
+
+// DeleteNote moves a note to the trash
+func (s *noteService) DeleteNote(id string) error {
+	if strings.TrimSpace(id) == "" {
+		return fmt.Errorf("note ID cannot be empty")
+	}
+
+	// Keep the note for listeners; it is gone from the repository afterwards
+	note, _ := s.repository.GetNoteByID(id)
+
+	if err := s.check(ports.NoteEvent{Kind: ports.NoteDeleted, NoteID: id, Note: note}); err != nil {
+		return err
+	}
+	if err := s.repository.DeleteNote(id); err != nil {
+		return fmt.Errorf("failed to delete note: %w", err)
+	}
+
+	s.notify(ports.NoteEvent{Kind: ports.NoteDeleted, NoteID: id, Note: note})
+	return nil
+}
+
+// GetTrash retrieves all notes currently in the trash
+func (s *noteService) GetTrash() ([]*entities.TrashedNote, error) {
+	trash, err := s.repository.GetTrash()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trash: %w", err)
+	}
+
+	return trash, nil
+}
+
+// RestoreNote restores a note from the trash by its ID or ID alias
+func (s *noteService) RestoreNote(id string) (*entities.Note, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, fmt.Errorf("note ID cannot be empty")
+	}
+
+	// A trashed note can also be restored by its alias
+	var trashed *entities.Note
+	if trash, err := s.repository.GetTrash(); err == nil {
+		for _, entry := range trash {
+			if entry.Note.ID == id || entities.IsIDAlias(id) && entry.Note.Alias() == id {
+				id = entry.Note.ID
+				trashed = entry.Note
+				break
+			}
+		}
+	}
+
+	if err := s.check(ports.NoteEvent{Kind: ports.NoteRestored, NoteID: id, Note: trashed}); err != nil {
+		return nil, err
+	}
+
+	note, err := s.repository.RestoreNote(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore note: %w", err)
+	}
+
+	s.notify(ports.NoteEvent{Kind: ports.NoteRestored, NoteID: note.ID, Note: note})
+	return note, nil
+}
+
+// EmptyTrash permanently removes trashed notes older than the given age.
+// A zero age empties the whole trash.
+func (s *noteService) EmptyTrash(olderThan time.Duration) (int, error) {
+	if olderThan < 0 {
+		return 0, fmt.Errorf("age cannot be negative")
+	}
+
+	if err := s.check(ports.NoteEvent{Kind: ports.TrashEmptied}); err != nil {
+		return 0, err
+	}
+	removed, err := s.repository.EmptyTrash(time.Now().Add(-olderThan))
+	if err != nil {
+		return 0, fmt.Errorf("failed to empty trash: %w", err)
+	}
+
+	if removed > 0 {
+		s.notify(ports.NoteEvent{Kind: ports.TrashEmptied, Count: removed})
+	}
+	return removed, nil
+}
+
+// formatTags formats tags for a listing, such as " #infra #deploy"
+func formatTags(tags []string) string {
+	var result strings.Builder
+	for _, tag := range tags {
+		result.WriteString(" #" + tag)
+	}
+	return result.String()
+}
+
+// formatProject formats a project for a listing, such as " [infra/k8s]"
+func formatProject(project string) string {
+	if project == "" {
+		return ""
+	}
+	return " [" + project + "]"
+}
+
 // ListNotes formats and returns notes for display
 func (s *noteService) ListNotes(notes []*entities.Note) string {
 	if len(notes) == 0 {
@@ -101,7 +495,7 @@ func (s *noteService) ListNotes(notes []*entities.Note) string {
 	result.WriteString(fmt.Sprintf("📝 Notes (%d found):\n\n", len(notes)))
 
 	for i, note := range notes {
-		result.WriteString(fmt.Sprintf("%d. %s\n", i+1, note.String()))
+		result.WriteString(fmt.Sprintf("%d. %s (%s)%s%s\n", i+1, note.String(), note.Alias(), formatProject(note.Metadata.Category), formatTags(note.Metadata.Tags)))
 	}
 
 	return result.String()
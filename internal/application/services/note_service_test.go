@@ -0,0 +1,607 @@
+package services
+
+import (
+	"errors"
+	"jotterxpress/internal/adapters/repository"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recorder is a listener that keeps every event it is sent
+type recorder struct {
+	events []ports.NoteEvent
+}
+
+func (r *recorder) NoteChanged(event ports.NoteEvent) {
+	r.events = append(r.events, event)
+}
+
+// kinds returns the kinds of the recorded events in order
+func (r *recorder) kinds() []ports.NoteEventKind {
+	var kinds []ports.NoteEventKind
+	for _, event := range r.events {
+		kinds = append(kinds, event.Kind)
+	}
+	return kinds
+}
+
+// newTestService returns a service over an empty in-memory repository
+func newTestService() (ports.NoteService, *recorder) {
+	events := &recorder{}
+	return NewNoteService(repository.NewMemoryRepository(), events), events
+}
+
+// expectKinds fails the test unless the recorded events have the given kinds
+func expectKinds(t *testing.T, events *recorder, want ...ports.NoteEventKind) {
+	t.Helper()
+
+	got := events.kinds()
+	if len(got) != len(want) {
+		t.Fatalf("got events %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("got events %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCreateNote(t *testing.T) {
+	service, events := newTestService()
+
+	if _, err := service.CreateNote("   "); err == nil {
+		t.Error("CreateNote accepted empty content")
+	}
+
+	note, err := service.CreateNote("buy milk")
+	if err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+
+	today, err := service.GetTodayNotes()
+	if err != nil {
+		t.Fatalf("GetTodayNotes failed: %v", err)
+	}
+	if len(today) != 1 || today[0].ID != note.ID || today[0].Content != "buy milk" {
+		t.Errorf("today's notes are %v, want the new note", today)
+	}
+
+	expectKinds(t, events, ports.NoteCreated)
+	if events.events[0].NoteID != note.ID {
+		t.Errorf("event is for note %s, want %s", events.events[0].NoteID, note.ID)
+	}
+}
+
+func TestSavingCollectsHashtags(t *testing.T) {
+	service, _ := newTestService()
+
+	note, err := service.CreateNote("restart the #Infra nodes")
+	if err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+	if strings.Join(note.Metadata.Tags, ",") != "infra" {
+		t.Errorf("tags after create are %q, want [infra]", note.Metadata.Tags)
+	}
+
+	note.Content += " before the #deploy"
+	note.Metadata.Tags = append(note.Metadata.Tags, "Ops")
+	if err := service.SaveNote(note); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+	if strings.Join(note.Metadata.Tags, ",") != "infra,ops,deploy" {
+		t.Errorf("tags after save are %q, want [infra ops deploy]", note.Metadata.Tags)
+	}
+	// Removing a hashtag removes its tag, but not the explicit ones
+	note.Content = "restart the nodes"
+	if err := service.SaveNote(note); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+	if strings.Join(note.Metadata.Tags, ",") != "ops" {
+		t.Errorf("tags after removing the hashtags are %q, want [ops]", note.Metadata.Tags)
+	}
+}
+
+func TestSaveNoteReportsCreateOrUpdate(t *testing.T) {
+	service, events := newTestService()
+
+	note := entities.NewTask("write tests", entities.PriorityHigh)
+	if err := service.SaveNote(note); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+
+	note.Content = "write more tests"
+	if err := service.SaveNote(note); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+
+	note.Content = ""
+	if err := service.SaveNote(note); err == nil {
+		t.Error("SaveNote accepted empty content")
+	}
+
+	expectKinds(t, events, ports.NoteCreated, ports.NoteUpdated)
+}
+
+func TestSaveNoteWithNewDateKeepsOneCopy(t *testing.T) {
+	repo := repository.NewFileRepository(t.TempDir())
+	service := NewNoteService(repo)
+
+	note := entities.NewTask("renew the passport", entities.PriorityHigh)
+	note.Date = "2025-10-16"
+	if err := service.SaveNote(note); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+
+	note.Date = "2025-10-20"
+	if err := service.SaveNote(note); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+
+	all, err := repo.GetAllNotes()
+	if err != nil {
+		t.Fatalf("GetAllNotes failed: %v", err)
+	}
+	if len(all) != 1 || all[0].Date != "2025-10-20" {
+		t.Fatalf("repository holds %d notes, want the note once under 2025-10-20", len(all))
+	}
+	if old, _ := repo.GetNotesByDate("2025-10-16"); len(old) != 0 {
+		t.Errorf("old date still lists %d notes", len(old))
+	}
+}
+
+func TestGetNoteByIDResolvesPrefixes(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	service := NewNoteService(repo)
+
+	for _, id := range []string{"abc1", "abc2", "xyz"} {
+		note := entities.NewNote("note " + id)
+		note.ID = id
+		if err := repo.Save(note); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+
+	note, err := service.GetNoteByID("xy")
+	if err != nil || note.ID != "xyz" {
+		t.Errorf("GetNoteByID(xy) = %v, %v; want xyz", note, err)
+	}
+
+	note, err = service.GetNoteByID("abc1")
+	if err != nil || note.ID != "abc1" {
+		t.Errorf("GetNoteByID(abc1) = %v, %v; want abc1", note, err)
+	}
+
+	var ambiguous *ports.AmbiguousIDError
+	if _, err := service.GetNoteByID("abc"); !errors.As(err, &ambiguous) || len(ambiguous.Matches) != 2 {
+		t.Errorf("GetNoteByID(abc) error is %v, want an ambiguous ID error", err)
+	}
+
+	if _, err := service.GetNoteByID("nope"); !errors.Is(err, ports.ErrNoteNotFound) {
+		t.Errorf("GetNoteByID(nope) error is %v, want ErrNoteNotFound", err)
+	}
+
+	if _, err := service.GetNoteByID(" "); err == nil {
+		t.Error("GetNoteByID accepted an empty ID")
+	}
+}
+
+func TestGetNoteByIDResolvesAliases(t *testing.T) {
+	service, _ := newTestService()
+
+	// A legacy numeric ID and a new one both resolve by alias
+	legacy := entities.NewNote("legacy")
+	legacy.ID = "1760601600000000000"
+	if err := service.SaveNote(legacy); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+	current, err := service.CreateNote("current")
+	if err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+
+	for _, note := range []*entities.Note{legacy, current} {
+		got, err := service.GetNoteByID(note.Alias())
+		if err != nil || got.ID != note.ID {
+			t.Errorf("GetNoteByID(%s) = %v, %v; want %s", note.Alias(), got, err, note.ID)
+		}
+	}
+
+	got, err := service.GetNoteByID(strings.ToLower(current.ID[:20]))
+	if err != nil || got.ID != current.ID {
+		t.Errorf("lower-case prefix resolved to %v, %v; want %s", got, err, current.ID)
+	}
+
+	if err := service.DeleteNote(legacy.ID); err != nil {
+		t.Fatalf("DeleteNote failed: %v", err)
+	}
+	if restored, err := service.RestoreNote(legacy.Alias()); err != nil || restored.ID != legacy.ID {
+		t.Errorf("RestoreNote(%s) = %v, %v; want %s", legacy.Alias(), restored, err, legacy.ID)
+	}
+}
+
+func TestCompleteNote(t *testing.T) {
+	service, events := newTestService()
+
+	text, err := service.CreateNote("just text")
+	if err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+	if _, err := service.CompleteNote(text); err == nil {
+		t.Error("CompleteNote completed a text note")
+	}
+
+	task := entities.NewTask("finish report", entities.PriorityLow)
+	if err := service.SaveNote(task); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+	if _, err := service.CompleteNote(task); err != nil {
+		t.Fatalf("CompleteNote failed: %v", err)
+	}
+
+	stored, err := service.GetNoteByID(task.ID)
+	if err != nil {
+		t.Fatalf("GetNoteByID failed: %v", err)
+	}
+	if stored.Metadata.Status != entities.StatusCompleted {
+		t.Errorf("status is %q, want completed", stored.Metadata.Status)
+	}
+
+	expectKinds(t, events, ports.NoteCreated, ports.NoteCreated, ports.NoteCompleted)
+}
+
+func TestCompleteRecurringTask(t *testing.T) {
+	service, events := newTestService()
+
+	task := entities.NewTask("water the plants", entities.PriorityLow)
+	due := time.Now().AddDate(0, 0, -2)
+	due = time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.Local)
+	task.Metadata.DueDate = &due
+	task.Metadata.Recurrence = &entities.Recurrence{Rule: entities.RecurAfter, Days: 3}
+	if err := service.SaveNote(task); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+
+	next, err := service.CompleteNote(task)
+	if err != nil {
+		t.Fatalf("CompleteNote failed: %v", err)
+	}
+	if next == nil {
+		t.Fatal("completing a recurring task created no next occurrence")
+	}
+
+	// after:3d counts from the completion, not the overdue due date
+	want := time.Now().AddDate(0, 0, 3).Format("2006-01-02")
+	if next.Date != want || next.Metadata.DueDate.Format("2006-01-02") != want {
+		t.Errorf("next occurrence is filed under %s due %s, want %s", next.Date, next.Metadata.DueDate.Format("2006-01-02"), want)
+	}
+	if _, err := service.GetNoteByID(next.ID); err != nil {
+		t.Errorf("next occurrence was not saved: %v", err)
+	}
+
+	recurring, err := service.GetRecurring()
+	if err != nil {
+		t.Fatalf("GetRecurring failed: %v", err)
+	}
+	if len(recurring) != 1 || recurring[0].ID != next.ID {
+		t.Errorf("got %d recurring items, want only the open next occurrence", len(recurring))
+	}
+
+	// Completing the same item again does not create another occurrence
+	if again, err := service.CompleteNote(task); err != nil || again != nil {
+		t.Errorf("completing twice returned %v, %v, want no next occurrence", again, err)
+	}
+
+	expectKinds(t, events, ports.NoteCreated, ports.NoteCompleted, ports.NoteCreated, ports.NoteCompleted)
+}
+
+func TestRevertNote(t *testing.T) {
+	service, events := newTestService()
+
+	note, err := service.CreateNote("first draft")
+	if err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+	note.Content = "second draft"
+	if err := service.SaveNote(note); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+
+	if err := service.RevertNote(note, 1); err != nil {
+		t.Fatalf("RevertNote failed: %v", err)
+	}
+	if note.Content != "first draft" {
+		t.Errorf("content is %q after revert, want %q", note.Content, "first draft")
+	}
+
+	history, err := service.GetHistory(note.ID)
+	if err != nil {
+		t.Fatalf("GetHistory failed: %v", err)
+	}
+	if len(history) != 3 || history[2].Note.Content != "first draft" {
+		t.Errorf("got %d revisions, want the revert saved as revision 3", len(history))
+	}
+
+	if err := service.RevertNote(note, 9); err == nil {
+		t.Error("RevertNote accepted a missing revision")
+	}
+
+	expectKinds(t, events, ports.NoteCreated, ports.NoteUpdated, ports.NoteReverted)
+}
+
+func TestPromoteIdea(t *testing.T) {
+	service, events := newTestService()
+
+	idea := entities.NewIdea("offline mode", "cache the last week of notes", entities.MaturityExplored)
+	if err := service.SaveNote(idea); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+
+	if _, err := service.PromoteIdea(entities.NewNote("not an idea"), entities.PriorityLow); err == nil {
+		t.Error("PromoteIdea accepted a text note")
+	}
+
+	task, err := service.PromoteIdea(idea, entities.PriorityHigh)
+	if err != nil {
+		t.Fatalf("PromoteIdea failed: %v", err)
+	}
+	if task.Type != entities.NoteTypeTask || task.Content != "offline mode" || task.Metadata.Priority != entities.PriorityHigh {
+		t.Errorf("got task %+v, want a high priority task with the idea title", task)
+	}
+	if task.Metadata.LinkedNoteID != idea.ID {
+		t.Errorf("task links to %q, want the idea %q", task.Metadata.LinkedNoteID, idea.ID)
+	}
+
+	stored, err := service.GetNoteByID(idea.ID)
+	if err != nil {
+		t.Fatalf("GetNoteByID failed: %v", err)
+	}
+	if stored.Metadata.Maturity != entities.MaturityPromoted || stored.Metadata.LinkedNoteID != task.ID {
+		t.Errorf("idea is %s linked to %q, want promoted and linked to %q", stored.Metadata.Maturity, stored.Metadata.LinkedNoteID, task.ID)
+	}
+
+	if _, err := service.PromoteIdea(stored, entities.PriorityLow); err == nil {
+		t.Error("PromoteIdea promoted an idea twice")
+	}
+	expectKinds(t, events, ports.NoteCreated, ports.NoteCreated, ports.NotePromoted)
+}
+
+func TestGetDueTasks(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	service := NewNoteService(repo)
+
+	add := func(content string, days int, status entities.Status) *entities.Note {
+		task := entities.NewTask(content, entities.PriorityLow)
+		task.Metadata.Status = status
+		// File tasks under an old date, as they would be after a while
+		task.Date = "2025-01-06"
+		due := time.Now().AddDate(0, 0, days)
+		task.Metadata.DueDate = &due
+		if err := repo.Save(task); err != nil {
+			t.Fatal(err)
+		}
+		return task
+	}
+	add("due in a week", 7, entities.StatusToDo)
+	add("due in two days", 2, entities.StatusToDo)
+	add("overdue", -3, entities.StatusToDo)
+	add("done and overdue", -1, entities.StatusCompleted)
+	add("due in a month", 30, entities.StatusToDo)
+
+	undated := add("no due date", 0, entities.StatusToDo)
+	undated.Metadata.DueDate = nil
+	if err := repo.Save(undated); err != nil {
+		t.Fatal(err)
+	}
+
+	contents := func(days int) string {
+		t.Helper()
+		tasks, err := service.GetDueTasks(days)
+		if err != nil {
+			t.Fatalf("GetDueTasks failed: %v", err)
+		}
+		var result []string
+		for _, task := range tasks {
+			result = append(result, task.Content)
+		}
+		return strings.Join(result, ", ")
+	}
+
+	if got, want := contents(-1), "overdue"; got != want {
+		t.Errorf("overdue tasks are %q, want %q", got, want)
+	}
+	if got, want := contents(7), "overdue, due in two days, due in a week"; got != want {
+		t.Errorf("tasks due within a week are %q, want %q", got, want)
+	}
+}
+
+func TestMoveNote(t *testing.T) {
+	service, events := newTestService()
+
+	note, err := service.CreateNote("call the bank")
+	if err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+
+	if err := service.MoveNote(note, "next week"); err == nil {
+		t.Error("MoveNote accepted an invalid date")
+	}
+
+	// Moving a note to its own date changes nothing
+	if err := service.MoveNote(note, note.Date); err != nil {
+		t.Fatalf("MoveNote failed: %v", err)
+	}
+
+	to := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	if err := service.MoveNote(note, to); err != nil {
+		t.Fatalf("MoveNote failed: %v", err)
+	}
+	if note.Date != to {
+		t.Errorf("date is %s after the move, want %s", note.Date, to)
+	}
+
+	today, err := service.GetTodayNotes()
+	if err != nil {
+		t.Fatalf("GetTodayNotes failed: %v", err)
+	}
+	if len(today) != 0 {
+		t.Errorf("%d notes left today, want 0", len(today))
+	}
+
+	moved, err := service.GetNotesByDate(to)
+	if err != nil {
+		t.Fatalf("GetNotesByDate failed: %v", err)
+	}
+	if len(moved) != 1 || moved[0].ID != note.ID {
+		t.Errorf("notes on %s are %v, want the moved note", to, moved)
+	}
+
+	expectKinds(t, events, ports.NoteCreated, ports.NoteMoved)
+}
+
+func TestDeleteRestoreAndEmptyTrash(t *testing.T) {
+	service, events := newTestService()
+
+	note, err := service.CreateNote("temporary")
+	if err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+
+	if err := service.DeleteNote(note.ID); err != nil {
+		t.Fatalf("DeleteNote failed: %v", err)
+	}
+	if events.events[1].Note == nil || events.events[1].Note.Content != "temporary" {
+		t.Error("delete event does not carry the deleted note")
+	}
+
+	if _, err := service.RestoreNote(note.ID); err != nil {
+		t.Fatalf("RestoreNote failed: %v", err)
+	}
+	if err := service.DeleteNote(note.ID); err != nil {
+		t.Fatalf("DeleteNote failed: %v", err)
+	}
+
+	if _, err := service.EmptyTrash(-time.Hour); err == nil {
+		t.Error("EmptyTrash accepted a negative age")
+	}
+
+	// Notes deleted just now are younger than an hour
+	removed, err := service.EmptyTrash(time.Hour)
+	if err != nil || removed != 0 {
+		t.Errorf("EmptyTrash(1h) = %d, %v; want nothing removed", removed, err)
+	}
+
+	removed, err = service.EmptyTrash(0)
+	if err != nil || removed != 1 {
+		t.Errorf("EmptyTrash(0) = %d, %v; want 1 removed", removed, err)
+	}
+
+	expectKinds(t, events, ports.NoteCreated, ports.NoteDeleted, ports.NoteRestored, ports.NoteDeleted, ports.TrashEmptied)
+	if events.events[4].Count != 1 {
+		t.Errorf("empty-trash event counts %d notes, want 1", events.events[4].Count)
+	}
+}
+
+func TestDateValidation(t *testing.T) {
+	service, _ := newTestService()
+
+	if _, err := service.GetNotesByDate("10/03/2025"); err == nil {
+		t.Error("GetNotesByDate accepted an invalid date")
+	}
+	if _, err := service.GetNotesByMonth("2025"); err == nil {
+		t.Error("GetNotesByMonth accepted an invalid month")
+	}
+}
+
+// guard is a listener that refuses changes of one kind
+type guard struct {
+	recorder
+	refuse ports.NoteEventKind
+}
+
+func (g *guard) BeforeChange(event ports.NoteEvent) error {
+	if event.Kind == g.refuse {
+		return errors.New("refused")
+	}
+	return nil
+}
+
+func TestGuardRefusesChange(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	events := &guard{refuse: ports.NoteDeleted}
+	service := NewNoteService(repo, events)
+
+	note, err := service.CreateNote("keep me")
+	if err != nil {
+		t.Fatalf("CreateNote failed: %v", err)
+	}
+
+	if err := service.DeleteNote(note.ID); err == nil {
+		t.Fatal("DeleteNote succeeded although the guard refused it")
+	}
+	if _, err := repo.GetNoteByID(note.ID); err != nil {
+		t.Errorf("refused deletion removed the note: %v", err)
+	}
+	expectKinds(t, &events.recorder, ports.NoteCreated)
+
+	events.refuse = ports.NoteCreated
+	if _, err := service.CreateNote("secret"); err == nil {
+		t.Error("CreateNote succeeded although the guard refused it")
+	}
+	if notes, _ := repo.GetAllNotes(); len(notes) != 1 {
+		t.Errorf("repository holds %d notes, want 1", len(notes))
+	}
+}
+
+func TestRefusedChangeLeavesNoteAlone(t *testing.T) {
+	repo := repository.NewMemoryRepository()
+	events := &guard{}
+	service := NewNoteService(repo, events)
+
+	task := entities.NewTask("call the bank", entities.PriorityHigh)
+	if err := service.SaveNote(task); err != nil {
+		t.Fatalf("SaveNote failed: %v", err)
+	}
+	before := *task
+
+	events.refuse = ports.NoteUpdated
+	task.Content = "call the bank #finance"
+	task.Metadata.Category = " Home / Bills "
+	if err := service.SaveNote(task); err == nil {
+		t.Fatal("SaveNote succeeded although the guard refused it")
+	}
+	if !task.UpdatedAt.Equal(before.UpdatedAt) || len(task.Metadata.Tags) != 0 || task.Metadata.Category != " Home / Bills " {
+		t.Errorf("refused save changed the note: updated %s, tags %v, project %q",
+			task.UpdatedAt, task.Metadata.Tags, task.Metadata.Category)
+	}
+
+	events.refuse = ports.NoteCompleted
+	if _, err := service.CompleteNote(task); err == nil {
+		t.Fatal("CompleteNote succeeded although the guard refused it")
+	}
+	if task.Metadata.Status == entities.StatusCompleted {
+		t.Error("refused completion marked the task completed")
+	}
+	if stored, _ := repo.GetNoteByID(task.ID); stored.Metadata.Status == entities.StatusCompleted {
+		t.Error("refused completion was stored")
+	}
+
+	events.refuse = ports.NoteMoved
+	if err := service.MoveNote(task, "2030-01-01"); err == nil {
+		t.Fatal("MoveNote succeeded although the guard refused it")
+	}
+	if task.Date != before.Date || !task.UpdatedAt.Equal(before.UpdatedAt) {
+		t.Errorf("refused move changed the note to %s, updated %s", task.Date, task.UpdatedAt)
+	}
+
+	// Once allowed, the changes are applied to the note
+	events.refuse = ""
+	if _, err := service.CompleteNote(task); err != nil {
+		t.Fatalf("CompleteNote failed: %v", err)
+	}
+	if task.Metadata.Status != entities.StatusCompleted || !task.HasTag("finance") || task.Metadata.Category != "home/bills" {
+		t.Errorf("completed task has status %q, tags %v, project %q", task.Metadata.Status, task.Metadata.Tags, task.Metadata.Category)
+	}
+}
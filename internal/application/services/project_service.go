@@ -0,0 +1,97 @@
+package services
+
+import (
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"sort"
+)
+
+// projectService implements the ProjectService interface
+type projectService struct {
+	repository ports.NoteRepository
+}
+
+// NewProjectService creates a new project service. Projects are not stored
+// on their own: a project exists while a note has it as its category.
+func NewProjectService(repository ports.NoteRepository) ports.ProjectService {
+	return &projectService{repository: repository}
+}
+
+// GetProjects retrieves every project in use, and the projects above them,
+// sorted by name
+func (s *projectService) GetProjects() ([]ports.Project, error) {
+	notes, err := s.repository.GetAllNotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notes: %w", err)
+	}
+
+	projects := make(map[string]*ports.Project)
+	for _, note := range notes {
+		for _, name := range entities.ProjectPath(note.Metadata.Category) {
+			project, ok := projects[name]
+			if !ok {
+				project = &ports.Project{Name: name}
+				projects[name] = project
+			}
+			count(project, note)
+		}
+	}
+
+	result := make([]ports.Project, 0, len(projects))
+	for _, project := range projects {
+		result = append(result, *project)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// GetProject retrieves a project and the notes in it or its subprojects,
+// most recently updated first
+func (s *projectService) GetProject(name string) (*ports.Project, []*entities.Note, error) {
+	name = entities.NormalizeProject(name)
+	if name == "" {
+		return nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+
+	notes, err := s.repository.GetAllNotes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get notes: %w", err)
+	}
+
+	project := &ports.Project{Name: name}
+	var inProject []*entities.Note
+	for _, note := range notes {
+		if note.InProject(name) {
+			count(project, note)
+			inProject = append(inProject, note)
+		}
+	}
+	if len(inProject) == 0 {
+		return nil, nil, fmt.Errorf("no notes in project %s", name)
+	}
+
+	sort.SliceStable(inProject, func(i, j int) bool {
+		return inProject[i].UpdatedAt.After(inProject[j].UpdatedAt)
+	})
+
+	return project, inProject, nil
+}
+
+// count adds a note to the progress of a project
+func count(project *ports.Project, note *entities.Note) {
+	project.Notes++
+	if note.Type == entities.NoteTypeTask {
+		if note.Metadata.Status == entities.StatusCompleted {
+			project.CompletedTasks++
+		} else {
+			project.OpenTasks++
+		}
+	}
+	if note.UpdatedAt.After(project.LastActivity) {
+		project.LastActivity = note.UpdatedAt
+	}
+}
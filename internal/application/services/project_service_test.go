@@ -0,0 +1,84 @@
+package services
+
+import (
+	"jotterxpress/internal/adapters/repository"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"testing"
+	"time"
+)
+
+// newTestProjectService returns a project service over a repository with
+// notes in a few nested projects
+func newTestProjectService(t *testing.T) ports.ProjectService {
+	t.Helper()
+
+	repo := repository.NewMemoryRepository()
+	add := func(note *entities.Note, project string, age time.Duration) {
+		note.Metadata.Category = project
+		note.UpdatedAt = time.Now().Add(-age)
+		if err := repo.Save(note); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	done := entities.NewTask("drain the old nodes", entities.PriorityHigh)
+	done.Metadata.Status = entities.StatusCompleted
+
+	add(entities.NewTask("upgrade the cluster", entities.PriorityHigh), "infra/k8s", time.Hour)
+	add(done, "infra/k8s", 2*time.Hour)
+	add(entities.NewNote("ingress notes from the call"), "infra/k8s/ingress", time.Minute)
+	add(entities.NewTask("renew certificates", entities.PriorityLow), "infra", 3*time.Hour)
+	add(entities.NewNote("outline of the guide"), "docs", time.Hour)
+	add(entities.NewNote("not in any project"), "", time.Hour)
+
+	return NewProjectService(repo)
+}
+
+func TestGetProjects(t *testing.T) {
+	projects, err := newTestProjectService(t).GetProjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ports.Project{
+		{Name: "docs", Notes: 1},
+		{Name: "infra", Notes: 4, OpenTasks: 2, CompletedTasks: 1},
+		{Name: "infra/k8s", Notes: 3, OpenTasks: 1, CompletedTasks: 1},
+		{Name: "infra/k8s/ingress", Notes: 1},
+	}
+	if len(projects) != len(want) {
+		t.Fatalf("got %d projects, want %d: %v", len(projects), len(want), projects)
+	}
+	for i, project := range projects {
+		project.LastActivity = time.Time{}
+		if project != want[i] {
+			t.Errorf("project %d = %+v, want %+v", i, project, want[i])
+		}
+	}
+}
+
+func TestGetProject(t *testing.T) {
+	service := newTestProjectService(t)
+
+	project, notes, err := service.GetProject(" Infra/K8s ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.Name != "infra/k8s" || project.Notes != 3 || project.OpenTasks != 1 || project.CompletedTasks != 1 {
+		t.Errorf("project = %+v", project)
+	}
+
+	// Most recently updated first, subprojects included
+	var contents []string
+	for _, note := range notes {
+		contents = append(contents, note.Content)
+	}
+	if len(contents) != 3 || contents[0] != "ingress notes from the call" || contents[2] != "drain the old nodes" {
+		t.Errorf("notes = %q", contents)
+	}
+
+	if _, _, err := service.GetProject("marketing"); err == nil {
+		t.Error("GetProject found a project without notes")
+	}
+}
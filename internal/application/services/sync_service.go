@@ -0,0 +1,246 @@
+package services
+
+import (
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"sort"
+	"time"
+)
+
+// conflictPrefix starts the content of the conflict copy of a note
+const conflictPrefix = "(conflict copy) "
+
+// syncService implements the SyncService interface
+type syncService struct {
+	repository ports.NoteRepository
+}
+
+// NewSyncService creates a new sync service. Notes are stored in repository
+// as they come from the other side, keeping their timestamps, so changes are
+// not reported to note listeners.
+func NewSyncService(repository ports.NoteRepository) ports.SyncService {
+	return &syncService{repository: repository}
+}
+
+// syncState holds both sides of a sync
+type syncState struct {
+	local      map[string]*entities.Note
+	trash      map[string]*entities.TrashedNote
+	remote     map[string]*entities.Note
+	tombstones map[string]*entities.Tombstone
+}
+
+// Sync merges the notes of the repository and remote by ID
+func (s *syncService) Sync(remote ports.SyncStore, base map[string]time.Time, resolve ports.ConflictResolver) (*ports.SyncReport, error) {
+	state, err := s.load(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool)
+	for id := range state.local {
+		ids[id] = true
+	}
+	for id := range state.remote {
+		ids[id] = true
+	}
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+
+	report := &ports.SyncReport{Synced: make(map[string]time.Time)}
+	for _, id := range sorted {
+		local, remoteNote := state.local[id], state.remote[id]
+
+		var err error
+		switch {
+		case local != nil && remoteNote != nil:
+			err = s.syncChanged(remote, local, remoteNote, base, resolve, report)
+
+		case local != nil:
+			// Deleted remotely, unless it was edited here after that
+			if tombstone := state.tombstones[id]; tombstone != nil && !local.UpdatedAt.After(tombstone.DeletedAt) {
+				err = s.repository.DeleteNote(id)
+				report.DeletedLocal++
+				break
+			}
+			err = s.push(remote, local, report)
+
+		default:
+			// Deleted here, unless it was edited remotely after that
+			if trashed := state.trash[id]; trashed != nil {
+				if !remoteNote.UpdatedAt.After(trashed.DeletedAt) {
+					err = remote.DeleteNote(&entities.Tombstone{ID: id, DeletedAt: trashed.DeletedAt})
+					report.DeletedRemote++
+					break
+				}
+				if _, err = s.repository.RestoreNote(id); err != nil {
+					break
+				}
+			} else if synced, known := base[id]; known && !remoteNote.UpdatedAt.After(synced) {
+				// Deleted here and purged from the trash since the last sync.
+				// The deletion time is gone with it; the last sync is the
+				// earliest it can have been, so edits made elsewhere after
+				// that still win.
+				err = remote.DeleteNote(&entities.Tombstone{ID: id, DeletedAt: synced})
+				report.DeletedRemote++
+				break
+			}
+			err = s.pull(remoteNote, report)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to sync note %s: %w", id, err)
+		}
+	}
+
+	return report, nil
+}
+
+// load reads both sides of a sync
+func (s *syncService) load(remote ports.SyncStore) (*syncState, error) {
+	state := &syncState{
+		local:      make(map[string]*entities.Note),
+		trash:      make(map[string]*entities.TrashedNote),
+		remote:     make(map[string]*entities.Note),
+		tombstones: make(map[string]*entities.Tombstone),
+	}
+
+	notes, err := s.repository.GetAllNotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notes: %w", err)
+	}
+	for _, note := range notes {
+		state.local[note.ID] = note
+	}
+
+	trash, err := s.repository.GetTrash()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trash: %w", err)
+	}
+	for _, entry := range trash {
+		state.trash[entry.Note.ID] = entry
+	}
+
+	remoteNotes, err := remote.GetNotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote notes: %w", err)
+	}
+	for _, note := range remoteNotes {
+		state.remote[note.ID] = note
+	}
+
+	tombstones, err := remote.GetTombstones()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote deletions: %w", err)
+	}
+	for _, tombstone := range tombstones {
+		state.tombstones[tombstone.ID] = tombstone
+	}
+
+	return state, nil
+}
+
+// syncChanged syncs a note present on both sides. When one side changed it
+// since the last sync, that side wins; when both did, it is a conflict.
+// Without a previous sync the version updated last wins.
+func (s *syncService) syncChanged(remote ports.SyncStore, local, remoteNote *entities.Note, base map[string]time.Time, resolve ports.ConflictResolver, report *ports.SyncReport) error {
+	if local.UpdatedAt.Equal(remoteNote.UpdatedAt) {
+		report.Synced[local.ID] = local.UpdatedAt
+		return nil
+	}
+
+	synced, known := base[local.ID]
+	localChanged := !known || !local.UpdatedAt.Equal(synced)
+	remoteChanged := !known || !remoteNote.UpdatedAt.Equal(synced)
+
+	switch {
+	case known && !localChanged:
+		return s.pull(remoteNote, report)
+	case known && !remoteChanged:
+		return s.push(remote, local, report)
+	case !known:
+		if local.UpdatedAt.After(remoteNote.UpdatedAt) {
+			return s.push(remote, local, report)
+		}
+		return s.pull(remoteNote, report)
+	}
+
+	report.Conflicts++
+	resolution := ports.KeepBoth
+	if resolve != nil {
+		resolution = resolve(ports.SyncConflict{Local: local, Remote: remoteNote})
+	}
+
+	switch resolution {
+	case ports.KeepLocal:
+		return s.push(remote, local, report)
+	case ports.KeepRemote:
+		return s.pull(remoteNote, report)
+	}
+
+	// Keep both: the version updated last stays the note
+	winner, loser := local, remoteNote
+	if remoteNote.UpdatedAt.After(local.UpdatedAt) {
+		winner, loser = remoteNote, local
+	}
+
+	conflictCopy, err := newConflictCopy(loser)
+	if err != nil {
+		return err
+	}
+	if err := s.repository.Save(conflictCopy); err != nil {
+		return err
+	}
+	if err := remote.SaveNote(conflictCopy); err != nil {
+		return err
+	}
+	report.Synced[conflictCopy.ID] = conflictCopy.UpdatedAt
+	report.ConflictCopies = append(report.ConflictCopies, conflictCopy)
+
+	if winner == local {
+		return s.push(remote, local, report)
+	}
+	return s.pull(remoteNote, report)
+}
+
+// push stores a local note in the remote store
+func (s *syncService) push(remote ports.SyncStore, note *entities.Note, report *ports.SyncReport) error {
+	if err := remote.SaveNote(note); err != nil {
+		return err
+	}
+	report.Pushed++
+	report.Synced[note.ID] = note.UpdatedAt
+	return nil
+}
+
+// pull stores a remote note in the repository
+func (s *syncService) pull(note *entities.Note, report *ports.SyncReport) error {
+	if err := s.repository.Save(note); err != nil {
+		return err
+	}
+	report.Pulled++
+	report.Synced[note.ID] = note.UpdatedAt
+	return nil
+}
+
+// newConflictCopy returns a new note holding the content and metadata of
+// note, marked as a conflict copy
+func newConflictCopy(note *entities.Note) (*entities.Note, error) {
+	data, err := note.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy note %s: %w", note.ID, err)
+	}
+	conflictCopy, err := entities.FromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy note %s: %w", note.ID, err)
+	}
+
+	conflictCopy.ID = entities.NewID()
+	conflictCopy.Content = conflictPrefix + note.Content
+	conflictCopy.UpdatedAt = time.Now()
+
+	return conflictCopy, nil
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"jotterxpress/internal/adapters/repository"
+	"jotterxpress/internal/adapters/syncdir"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"strings"
+	"testing"
+	"time"
+)
+
+// syncMachine is one side of a folder sync: its notes and the state of its
+// last sync
+type syncMachine struct {
+	repo ports.NoteRepository
+	base map[string]time.Time
+}
+
+// sync syncs a machine with the shared folder and records the new state
+func (m *syncMachine) sync(t *testing.T, store ports.SyncStore, resolve ports.ConflictResolver) *ports.SyncReport {
+	t.Helper()
+
+	report, err := NewSyncService(m.repo).Sync(store, m.base, resolve)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	m.base = report.Synced
+	return report
+}
+
+// newSyncTest returns two machines with no notes and a shared folder
+func newSyncTest(t *testing.T) (*syncMachine, *syncMachine, ports.SyncStore) {
+	store, err := syncdir.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore failed: %v", err)
+	}
+
+	return &syncMachine{repo: repository.NewMemoryRepository()},
+		&syncMachine{repo: repository.NewMemoryRepository()},
+		store
+}
+
+// saveAt saves a note with the given content and UpdatedAt
+func saveAt(t *testing.T, repo ports.NoteRepository, id, content string, updatedAt time.Time) {
+	t.Helper()
+
+	note := entities.NewNote(content)
+	note.ID = id
+	note.UpdatedAt = updatedAt
+	if err := repo.Save(note); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+}
+
+// contentOf returns the content of a note, or "" when it does not exist
+func contentOf(t *testing.T, repo ports.NoteRepository, id string) string {
+	t.Helper()
+
+	note, err := repo.GetNoteByID(id)
+	if err != nil {
+		return ""
+	}
+	return note.Content
+}
+
+func TestSyncPushesAndPulls(t *testing.T) {
+	laptop, desktop, store := newSyncTest(t)
+	now := time.Now()
+
+	saveAt(t, laptop.repo, "laptop-note", "written on the laptop", now)
+	saveAt(t, desktop.repo, "desktop-note", "written on the desktop", now)
+
+	if report := laptop.sync(t, store, nil); report.Pushed != 1 || report.Pulled != 0 {
+		t.Errorf("first sync pushed %d and pulled %d, want 1 and 0", report.Pushed, report.Pulled)
+	}
+	if report := desktop.sync(t, store, nil); report.Pushed != 1 || report.Pulled != 1 {
+		t.Errorf("second sync pushed %d and pulled %d, want 1 and 1", report.Pushed, report.Pulled)
+	}
+	laptop.sync(t, store, nil)
+
+	for _, machine := range []*syncMachine{laptop, desktop} {
+		notes, err := machine.repo.GetAllNotes()
+		if err != nil {
+			t.Fatalf("GetAllNotes failed: %v", err)
+		}
+		if len(notes) != 2 {
+			t.Errorf("machine has %d notes after syncing, want 2", len(notes))
+		}
+	}
+
+	if report := laptop.sync(t, store, nil); report.Pushed+report.Pulled != 0 {
+		t.Errorf("sync without changes moved %d notes", report.Pushed+report.Pulled)
+	}
+}
+
+func TestSyncKeepsNewerVersion(t *testing.T) {
+	laptop, desktop, store := newSyncTest(t)
+	now := time.Now()
+
+	saveAt(t, laptop.repo, "shared", "older", now)
+	saveAt(t, desktop.repo, "shared", "newer", now.Add(time.Minute))
+
+	laptop.sync(t, store, nil)
+	report := desktop.sync(t, store, nil)
+	laptop.sync(t, store, nil)
+
+	if report.Conflicts != 0 {
+		t.Errorf("got %d conflicts without an earlier sync, want 0", report.Conflicts)
+	}
+	for _, machine := range []*syncMachine{laptop, desktop} {
+		if content := contentOf(t, machine.repo, "shared"); content != "newer" {
+			t.Errorf("note has content %q, want %q", content, "newer")
+		}
+	}
+}
+
+func TestSyncCarriesMoves(t *testing.T) {
+	laptop, desktop, store := newSyncTest(t)
+	// Day files hold one date each, so a pulled move must leave the old one
+	laptop.repo = repository.NewFileRepository(t.TempDir())
+
+	note := entities.NewNote("plan the offsite")
+	note.Date = "2025-03-10"
+	if err := laptop.repo.Save(note); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	laptop.sync(t, store, nil)
+	desktop.sync(t, store, nil)
+
+	moved, err := desktop.repo.GetNoteByID(note.ID)
+	if err != nil {
+		t.Fatalf("GetNoteByID failed: %v", err)
+	}
+	moved.UpdatedAt = moved.UpdatedAt.Add(time.Minute)
+	if err := desktop.repo.MoveNote(moved, "2025-03-12"); err != nil {
+		t.Fatalf("MoveNote failed: %v", err)
+	}
+	desktop.sync(t, store, nil)
+	laptop.sync(t, store, nil)
+
+	old, err := laptop.repo.GetNotesByDate("2025-03-10")
+	if err != nil {
+		t.Fatalf("GetNotesByDate failed: %v", err)
+	}
+	if len(old) != 0 {
+		t.Errorf("old date still holds %d notes after pulling the move", len(old))
+	}
+	if got, err := laptop.repo.GetNoteByID(note.ID); err != nil || got.Date != "2025-03-12" {
+		t.Errorf("pulled note is %v, %v, want it under 2025-03-12", got, err)
+	}
+}
+
+func TestSyncCarriesDeletions(t *testing.T) {
+	laptop, desktop, store := newSyncTest(t)
+
+	saveAt(t, laptop.repo, "gone", "delete me", time.Now())
+	laptop.sync(t, store, nil)
+	desktop.sync(t, store, nil)
+
+	if err := laptop.repo.DeleteNote("gone"); err != nil {
+		t.Fatalf("DeleteNote failed: %v", err)
+	}
+	if report := laptop.sync(t, store, nil); report.DeletedRemote != 1 {
+		t.Errorf("deleted %d notes in the folder, want 1", report.DeletedRemote)
+	}
+	if report := desktop.sync(t, store, nil); report.DeletedLocal != 1 {
+		t.Errorf("deleted %d notes on the desktop, want 1", report.DeletedLocal)
+	}
+
+	if content := contentOf(t, desktop.repo, "gone"); content != "" {
+		t.Errorf("deleted note is still on the desktop")
+	}
+	trash, err := desktop.repo.GetTrash()
+	if err != nil {
+		t.Fatalf("GetTrash failed: %v", err)
+	}
+	if len(trash) != 1 {
+		t.Errorf("desktop trash holds %d notes, want 1", len(trash))
+	}
+}
+
+func TestSyncCarriesDeletionsPurgedFromTrash(t *testing.T) {
+	laptop, desktop, store := newSyncTest(t)
+
+	saveAt(t, laptop.repo, "gone", "delete me", time.Now())
+	laptop.sync(t, store, nil)
+	desktop.sync(t, store, nil)
+
+	if err := laptop.repo.DeleteNote("gone"); err != nil {
+		t.Fatalf("DeleteNote failed: %v", err)
+	}
+	if _, err := laptop.repo.EmptyTrash(time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("EmptyTrash failed: %v", err)
+	}
+
+	if report := laptop.sync(t, store, nil); report.DeletedRemote != 1 || report.Pulled != 0 {
+		t.Errorf("sync deleted %d and pulled %d notes, want 1 and 0", report.DeletedRemote, report.Pulled)
+	}
+	if content := contentOf(t, laptop.repo, "gone"); content != "" {
+		t.Errorf("purged note came back on the laptop")
+	}
+
+	if report := desktop.sync(t, store, nil); report.DeletedLocal != 1 {
+		t.Errorf("deleted %d notes on the desktop, want 1", report.DeletedLocal)
+	}
+	if content := contentOf(t, desktop.repo, "gone"); content != "" {
+		t.Errorf("deleted note is still on the desktop")
+	}
+}
+
+func TestSyncKeepsBothSidesOfConflict(t *testing.T) {
+	laptop, desktop, store := newSyncTest(t)
+	now := time.Now()
+
+	saveAt(t, laptop.repo, "shared", "original", now)
+	laptop.sync(t, store, nil)
+	desktop.sync(t, store, nil)
+
+	saveAt(t, laptop.repo, "shared", "laptop edit", now.Add(time.Minute))
+	saveAt(t, desktop.repo, "shared", "desktop edit", now.Add(2*time.Minute))
+	laptop.sync(t, store, nil)
+
+	var conflicts []ports.SyncConflict
+	report := desktop.sync(t, store, func(conflict ports.SyncConflict) ports.SyncResolution {
+		conflicts = append(conflicts, conflict)
+		return ports.KeepBoth
+	})
+	laptop.sync(t, store, nil)
+
+	if len(conflicts) != 1 || report.Conflicts != 1 || len(report.ConflictCopies) != 1 {
+		t.Fatalf("got %d conflicts and %d copies, want 1 and 1", len(conflicts), len(report.ConflictCopies))
+	}
+
+	copyID := report.ConflictCopies[0].ID
+	for _, machine := range []*syncMachine{laptop, desktop} {
+		if content := contentOf(t, machine.repo, "shared"); content != "desktop edit" {
+			t.Errorf("note has content %q, want the newer %q", content, "desktop edit")
+		}
+		if content := contentOf(t, machine.repo, copyID); !strings.HasSuffix(content, "laptop edit") {
+			t.Errorf("conflict copy has content %q, want the laptop edit", content)
+		}
+	}
+}
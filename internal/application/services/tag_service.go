@@ -0,0 +1,141 @@
+package services
+
+import (
+	"fmt"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"sort"
+	"time"
+)
+
+// tagService implements the TagService interface
+type tagService struct {
+	repository ports.NoteRepository
+}
+
+// NewTagService creates a new tag service. Renaming a tag can change
+// hundreds of notes, so they are written to repository directly as one
+// change rather than reported to note listeners one by one.
+func NewTagService(repository ports.NoteRepository) ports.TagService {
+	return &tagService{repository: repository}
+}
+
+// GetTags retrieves every tag in use, most used first, then by name
+func (s *tagService) GetTags() ([]ports.TagCount, error) {
+	notes, err := s.repository.GetAllNotes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notes: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, note := range notes {
+		seen := make(map[string]bool)
+		for _, tag := range note.Metadata.Tags {
+			if tag = entities.NormalizeTag(tag); tag != "" && !seen[tag] {
+				seen[tag] = true
+				counts[tag]++
+			}
+		}
+	}
+
+	tags := make([]ports.TagCount, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, ports.TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+
+	return tags, nil
+}
+
+// RenameTag renames a tag on every note, including its #hashtags
+func (s *tagService) RenameTag(from, to string) (int, error) {
+	return s.MergeTags([]string{from}, to)
+}
+
+// MergeTags replaces several tags with one on every note. Hashtags in the
+// content, and in the body of ideas, are rewritten too, so the old tags do
+// not come back the next time the note is saved.
+func (s *tagService) MergeTags(from []string, into string) (int, error) {
+	into = entities.NormalizeTag(into)
+	if into == "" {
+		return 0, fmt.Errorf("tag cannot be empty")
+	}
+
+	var sources []string
+	for _, tag := range from {
+		if tag = entities.NormalizeTag(tag); tag == "" {
+			return 0, fmt.Errorf("tag cannot be empty")
+		}
+		if tag != into {
+			sources = append(sources, tag)
+		}
+	}
+	if len(sources) == 0 {
+		return 0, nil
+	}
+
+	notes, err := s.repository.GetAllNotes()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get notes: %w", err)
+	}
+
+	changed := 0
+	for _, note := range notes {
+		if !retag(note, sources, into) {
+			continue
+		}
+
+		note.UpdatedAt = time.Now()
+		if err := s.repository.Save(note); err != nil {
+			return changed, fmt.Errorf("failed to save note %s: %w", note.ID, err)
+		}
+		changed++
+	}
+
+	return changed, nil
+}
+
+// retag replaces the tags in sources with into on a note and reports
+// whether the note had any of them
+func retag(note *entities.Note, sources []string, into string) bool {
+	found := false
+	for _, tag := range sources {
+		if note.HasTag(tag) {
+			found = true
+		}
+	}
+	if !found {
+		return false
+	}
+
+	note.Metadata.Tags = renameTags(note.Metadata.Tags, sources, into)
+	note.Metadata.Hashtags = renameTags(note.Metadata.Hashtags, sources, into)
+
+	for _, source := range sources {
+		note.Content = entities.ReplaceHashtag(note.Content, source, into)
+		note.Metadata.Body = entities.ReplaceHashtag(note.Metadata.Body, source, into)
+	}
+	note.CollectTags()
+
+	return true
+}
+
+// renameTags returns tags with every tag in sources replaced by into
+func renameTags(tags, sources []string, into string) []string {
+	var renamed []string
+	for _, tag := range tags {
+		tag = entities.NormalizeTag(tag)
+		for _, source := range sources {
+			if tag == source {
+				tag = into
+			}
+		}
+		renamed = append(renamed, tag)
+	}
+	return renamed
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"jotterxpress/internal/adapters/repository"
+	"jotterxpress/internal/domain/entities"
+	"jotterxpress/internal/domain/ports"
+	"reflect"
+	"testing"
+)
+
+// newTestTagService returns a tag service over a repository holding a note
+// for each of contents
+func newTestTagService(t *testing.T, contents ...string) (ports.TagService, ports.NoteRepository, []*entities.Note) {
+	t.Helper()
+
+	repo := repository.NewMemoryRepository()
+	notes := make([]*entities.Note, len(contents))
+	for i, content := range contents {
+		notes[i] = entities.NewNote(content)
+		notes[i].CollectTags()
+		if err := repo.Save(notes[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return NewTagService(repo), repo, notes
+}
+
+func TestGetTagsCountsNotes(t *testing.T) {
+	service, _, _ := newTestTagService(t,
+		"restart the #infra nodes",
+		"#deploy the #infra change",
+		"plan the #Deploy window",
+		"write the #docs",
+	)
+
+	tags, err := service.GetTags()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ports.TagCount{{Tag: "deploy", Count: 2}, {Tag: "infra", Count: 2}, {Tag: "docs", Count: 1}}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags = %v, want %v", tags, want)
+	}
+}
+
+func TestRenameTagRewritesHashtags(t *testing.T) {
+	service, repo, notes := newTestTagService(t, "restart the #Infra nodes", "unrelated note text")
+
+	changed, err := service.RenameTag("#infra", "ops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed != 1 {
+		t.Errorf("changed %d notes, want 1", changed)
+	}
+
+	note, err := repo.GetNoteByID(notes[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Content != "restart the #ops nodes" {
+		t.Errorf("content = %q", note.Content)
+	}
+
+	// Saving the note again must not bring the old tag back
+	note.CollectTags()
+	if !reflect.DeepEqual(note.Metadata.Tags, []string{"ops"}) {
+		t.Errorf("tags = %q, want [ops]", note.Metadata.Tags)
+	}
+
+	// The renamed tag still comes from the hashtag
+	note.Content = "restart the nodes"
+	note.CollectTags()
+	if len(note.Metadata.Tags) != 0 {
+		t.Errorf("tags after removing the hashtag = %q, want none", note.Metadata.Tags)
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	service, _, _ := newTestTagService(t, "#k8s and #kubernetes upgrade", "#kube cluster notes", "#docs only here")
+
+	changed, err := service.MergeTags([]string{"k8s", "kube"}, "kubernetes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed != 2 {
+		t.Errorf("changed %d notes, want 2", changed)
+	}
+
+	tags, err := service.GetTags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ports.TagCount{{Tag: "kubernetes", Count: 2}, {Tag: "docs", Count: 1}}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("tags = %v, want %v", tags, want)
+	}
+
+	if _, err := service.MergeTags([]string{"docs"}, "#"); err == nil {
+		t.Error("merging into an empty tag succeeded")
+	}
+}
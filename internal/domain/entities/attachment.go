@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// Attachment is a file kept with a note. Its content lives in the
+// attachment store under its SHA-256, so notes attaching the same file
+// share one copy.
+type Attachment struct {
+	Name    string    `json:"name"`
+	SHA256  string    `json:"sha256"`
+	Size    int64     `json:"size"`
+	AddedAt time.Time `json:"added_at"`
+}
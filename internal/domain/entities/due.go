@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+// DueDateLayout is the layout due dates are shown in
+const DueDateLayout = "2006-01-02"
+
+// IsOpenTask reports whether the note is a task that is not completed yet
+func (n *Note) IsOpenTask() bool {
+	return n.Type == NoteTypeTask && n.Metadata.Status != StatusCompleted
+}
+
+// DaysUntilDue returns the number of calendar days from now until the note
+// is due: 0 when it is due today and negative once it is overdue. ok is
+// false for notes without a due date.
+func (n *Note) DaysUntilDue(now time.Time) (days int, ok bool) {
+	if n.Metadata.DueDate == nil {
+		return 0, false
+	}
+	return calendarDays(now, *n.Metadata.DueDate), true
+}
+
+// IsOverdue reports whether the note is an open task due before today
+func (n *Note) IsOverdue(now time.Time) bool {
+	days, ok := n.DaysUntilDue(now)
+	return ok && days < 0 && n.IsOpenTask()
+}
+
+// calendarDays returns the number of calendar days from one time to
+// another, ignoring the time of day and daylight saving changes
+func calendarDays(from, to time.Time) int {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(to.Sub(from).Hours() / 24)
+}
@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDaysUntilDue(t *testing.T) {
+	now := time.Date(2025, 10, 16, 23, 30, 0, 0, time.Local)
+	task := NewTask("ship the release", PriorityHigh)
+
+	if _, ok := task.DaysUntilDue(now); ok {
+		t.Error("task without a due date reports one")
+	}
+
+	for due, want := range map[time.Time]int{
+		time.Date(2025, 10, 16, 0, 0, 0, 0, time.Local): 0,
+		time.Date(2025, 10, 17, 0, 0, 0, 0, time.Local): 1,
+		time.Date(2025, 10, 13, 0, 0, 0, 0, time.Local): -3,
+		time.Date(2025, 11, 16, 0, 0, 0, 0, time.Local): 31,
+	} {
+		task.Metadata.DueDate = &due
+		if days, ok := task.DaysUntilDue(now); !ok || days != want {
+			t.Errorf("due %s: DaysUntilDue = %d, want %d", due.Format(DueDateLayout), days, want)
+		}
+	}
+}
+
+func TestIsOverdue(t *testing.T) {
+	now := time.Date(2025, 10, 16, 9, 0, 0, 0, time.Local)
+	yesterday := now.AddDate(0, 0, -1)
+	today := time.Date(2025, 10, 16, 0, 0, 0, 0, time.Local)
+
+	task := NewTask("ship the release", PriorityHigh)
+	task.Metadata.DueDate = &yesterday
+	if !task.IsOverdue(now) {
+		t.Error("open task due yesterday is not overdue")
+	}
+
+	task.Metadata.Status = StatusCompleted
+	if task.IsOverdue(now) {
+		t.Error("completed task is overdue")
+	}
+
+	task.Metadata.Status = StatusToDo
+	task.Metadata.DueDate = &today
+	if task.IsOverdue(now) {
+		t.Error("task due today is overdue")
+	}
+}
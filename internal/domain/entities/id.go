@@ -0,0 +1,115 @@
+package entities
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"strings"
+	"sync"
+	"time"
+)
+
+// crockford is the Crockford base32 alphabet used by note IDs: digits and
+// upper-case letters without I, L, O and U
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// aliasAlphabet is the alphabet of ID aliases. It has no digits and no
+// upper-case letters, so an alias is never mistaken for the prefix of a
+// note ID, old or new.
+const aliasAlphabet = "abcdefghjkmnpqrstuvwxyz"
+
+// AliasLength is the number of characters of an ID alias
+const AliasLength = 6
+
+// idGenerator hands out IDs that increase even within one millisecond
+var idGenerator struct {
+	mu      sync.Mutex
+	last    int64
+	entropy [10]byte
+}
+
+// NewID generates a ULID: 48 bits of Unix time in milliseconds
+// followed by 80 random bits, as 26 Crockford base32 characters. IDs sort by
+// creation time, and IDs generated in the same millisecond increment the
+// random part instead of drawing a new one, so they never collide and still
+// sort in creation order.
+func NewID() string {
+	idGenerator.mu.Lock()
+	defer idGenerator.mu.Unlock()
+
+	ms := time.Now().UnixMilli()
+	if ms <= idGenerator.last {
+		// Same millisecond, or the clock went back: keep the last time
+		ms = idGenerator.last
+		incrementEntropy(&idGenerator.entropy)
+	} else {
+		rand.Read(idGenerator.entropy[:])
+	}
+	idGenerator.last = ms
+
+	var data [16]byte
+	binary.BigEndian.PutUint64(data[:8], uint64(ms)<<16)
+	copy(data[6:], idGenerator.entropy[:])
+
+	return encodeULID(data)
+}
+
+// incrementEntropy adds one to the random part of an ID
+func incrementEntropy(entropy *[10]byte) {
+	for i := len(entropy) - 1; i >= 0; i-- {
+		entropy[i]++
+		if entropy[i] != 0 {
+			return
+		}
+	}
+}
+
+// encodeULID encodes 128 bits as 26 Crockford base32 characters, the first
+// of which carries only 3 bits
+func encodeULID(data [16]byte) string {
+	hi := binary.BigEndian.Uint64(data[:8])
+	lo := binary.BigEndian.Uint64(data[8:])
+
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(out[:])
+}
+
+// IDAlias returns a short alias of a note ID for display, such as "kvbmxe".
+// It is derived from the ID, so notes of every ID format have one and it
+// never changes.
+func IDAlias(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	n := binary.BigEndian.Uint64(sum[:8])
+
+	var alias [AliasLength]byte
+	for i := range alias {
+		alias[i] = aliasAlphabet[n%uint64(len(aliasAlphabet))]
+		n /= uint64(len(aliasAlphabet))
+	}
+
+	return string(alias[:])
+}
+
+// IsIDAlias reports whether ref has the form of an ID alias
+func IsIDAlias(ref string) bool {
+	if len(ref) != AliasLength {
+		return false
+	}
+	for _, c := range ref {
+		if !strings.ContainsRune(aliasAlphabet, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Alias returns the short alias of the note ID
+func (n *Note) Alias() string {
+	return IDAlias(n.ID)
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestNewIDIsUniqueAndSorted(t *testing.T) {
+	const count = 10000
+
+	ids := make([]string, count)
+	seen := make(map[string]bool, count)
+	for i := range ids {
+		id := NewID()
+		if len(id) != 26 || strings.Trim(id, crockford) != "" {
+			t.Fatalf("ID %q is not 26 Crockford base32 characters", id)
+		}
+		if seen[id] {
+			t.Fatalf("ID %s was generated twice", id)
+		}
+		seen[id] = true
+		ids[i] = id
+	}
+
+	// IDs from the same millisecond still sort in creation order
+	if !sort.StringsAreSorted(ids) {
+		t.Error("IDs do not sort in creation order")
+	}
+}
+
+func TestIDAlias(t *testing.T) {
+	for _, id := range []string{"1760601600000000000", "2025-10-16-09:30:00", NewID()} {
+		alias := IDAlias(id)
+		if !IsIDAlias(alias) {
+			t.Errorf("alias %q of %s is not a valid alias", alias, id)
+		}
+		if IDAlias(id) != alias {
+			t.Errorf("alias of %s changed between calls", id)
+		}
+	}
+
+	for _, ref := range []string{"01K7", "176060", "abc", "kvbmxe1", "KVBMXE"} {
+		if IsIDAlias(ref) {
+			t.Errorf("IsIDAlias(%q) is true", ref)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package entities
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -33,6 +34,28 @@ const (
 	StatusCompleted Status = "completed"
 )
 
+// Maturity represents how far an idea has been taken
+type Maturity string
+
+const (
+	MaturityRaw      Maturity = "raw"
+	MaturityExplored Maturity = "explored"
+	MaturityPromoted Maturity = "promoted"
+)
+
+// ParseMaturity parses a maturity stage; an empty string is a raw idea
+func ParseMaturity(s string) (Maturity, error) {
+	switch Maturity(strings.ToLower(strings.TrimSpace(s))) {
+	case "", MaturityRaw:
+		return MaturityRaw, nil
+	case MaturityExplored:
+		return MaturityExplored, nil
+	case MaturityPromoted:
+		return MaturityPromoted, nil
+	}
+	return "", fmt.Errorf("invalid stage %q, expected raw, explored or promoted", s)
+}
+
 // Metadata represents additional fields for different note types
 type Metadata struct {
 	// Task fields
@@ -50,9 +73,25 @@ type Metadata struct {
 	// Reminder fields
 	ReminderTime string `json:"reminder_time,omitempty"`
 
+	// Idea fields; the title of an idea is its content
+	Body     string   `json:"body,omitempty"`
+	Maturity Maturity `json:"maturity,omitempty"`
+
+	// LinkedNoteID is the task an idea was promoted to, or the idea a task
+	// was promoted from
+	LinkedNoteID string `json:"linked_note_id,omitempty"`
+
+	// Recurrence makes a task or reminder repeat once it is completed
+	Recurrence *Recurrence `json:"recurrence,omitempty"`
+
 	// General fields
-	Tags     []string `json:"tags,omitempty"`
-	Category string   `json:"category,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Category    string       `json:"category,omitempty"`
+	Attachments []Attachment `json:"attachments,omitempty"`
+
+	// Hashtags are the tags that were only added because of a #hashtag in
+	// the text; see CollectTags
+	Hashtags []string `json:"hashtags,omitempty"`
 }
 
 // Note represents a note entity in our domain
@@ -70,7 +109,7 @@ type Note struct {
 func NewNote(content string) *Note {
 	now := time.Now()
 	return &Note{
-		ID:        generateID(),
+		ID:        NewID(),
 		Type:      NoteTypeText,
 		Content:   content,
 		CreatedAt: now,
@@ -84,7 +123,7 @@ func NewNote(content string) *Note {
 func NewTask(content string, priority Priority) *Note {
 	now := time.Now()
 	return &Note{
-		ID:        generateID(),
+		ID:        NewID(),
 		Type:      NoteTypeTask,
 		Content:   content,
 		CreatedAt: now,
@@ -101,7 +140,7 @@ func NewTask(content string, priority Priority) *Note {
 func NewContact(content, phone, email string) *Note {
 	now := time.Now()
 	return &Note{
-		ID:        generateID(),
+		ID:        NewID(),
 		Type:      NoteTypeContact,
 		Content:   content,
 		CreatedAt: now,
@@ -118,7 +157,7 @@ func NewContact(content, phone, email string) *Note {
 func NewReminder(content string, reminderTime string, status Status) *Note {
 	now := time.Now()
 	return &Note{
-		ID:        generateID(),
+		ID:        NewID(),
 		Type:      NoteTypeReminder,
 		Content:   content,
 		CreatedAt: now,
@@ -131,11 +170,28 @@ func NewReminder(content string, reminderTime string, status Status) *Note {
 	}
 }
 
+// NewIdea creates a new idea note
+func NewIdea(title, body string, maturity Maturity) *Note {
+	now := time.Now()
+	return &Note{
+		ID:        NewID(),
+		Type:      NoteTypeIdea,
+		Content:   title,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Date:      now.Format("2006-01-02"),
+		Metadata: Metadata{
+			Body:     body,
+			Maturity: maturity,
+		},
+	}
+}
+
 // NewNoteWithDate creates a new note with specific date
 func NewNoteWithDate(content string, date time.Time) *Note {
 	now := time.Now()
 	return &Note{
-		ID:        generateID(),
+		ID:        NewID(),
 		Type:      NoteTypeText,
 		Content:   content,
 		CreatedAt: now,
@@ -163,7 +219,14 @@ func (n *Note) String() string {
 
 	switch n.Type {
 	case NoteTypeTask:
-		return fmt.Sprintf("%s [%s, %s]", base, n.Metadata.Priority, n.Metadata.Status)
+		details := []string{string(n.Metadata.Priority), string(n.Metadata.Status)}
+		if n.Metadata.DueDate != nil {
+			details = append(details, "due "+n.Metadata.DueDate.Format(DueDateLayout))
+		}
+		if n.Metadata.Recurrence != nil {
+			details = append(details, "repeats "+n.Metadata.Recurrence.String())
+		}
+		return fmt.Sprintf("%s [%s]", base, strings.Join(details, ", "))
 	case NoteTypeContact:
 		if n.Metadata.Phone != "" {
 			return fmt.Sprintf("%s [%s]", base, n.Metadata.Phone)
@@ -176,13 +239,17 @@ func (n *Note) String() string {
 		if timeStr == "" {
 			timeStr = "09:00"
 		}
+		if n.Metadata.Recurrence != nil {
+			return fmt.Sprintf("%s [%s, %s, repeats %s]", base, timeStr, n.Metadata.Status, n.Metadata.Recurrence.String())
+		}
 		return fmt.Sprintf("%s [%s, %s]", base, timeStr, n.Metadata.Status)
+	case NoteTypeIdea:
+		maturity := n.Metadata.Maturity
+		if maturity == "" {
+			maturity = MaturityRaw
+		}
+		return fmt.Sprintf("%s [idea, %s]", base, maturity)
 	}
 
 	return base
 }
-
-// generateID generates a simple ID based on timestamp and random
-func generateID() string {
-	return fmt.Sprintf("%d", time.Now().UnixNano())
-}
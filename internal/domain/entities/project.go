@@ -0,0 +1,41 @@
+package entities
+
+import "strings"
+
+// NormalizeProject returns a project name in the form it is stored in: lower
+// case, with empty and padded path segments removed, so " Infra//K8s/ "
+// becomes "infra/k8s"
+func NormalizeProject(name string) string {
+	var segments []string
+	for _, segment := range strings.Split(name, "/") {
+		if segment = strings.ToLower(strings.TrimSpace(segment)); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// ProjectPath returns a project and the projects above it, from the top
+// down: "infra/k8s" gives "infra" and "infra/k8s"
+func ProjectPath(name string) []string {
+	name = NormalizeProject(name)
+	if name == "" {
+		return nil
+	}
+
+	var path []string
+	for i, r := range name {
+		if r == '/' {
+			path = append(path, name[:i])
+		}
+	}
+	return append(path, name)
+}
+
+// InProject reports whether the note belongs to project or to one of its
+// subprojects
+func (n *Note) InProject(project string) bool {
+	project = NormalizeProject(project)
+	category := NormalizeProject(n.Metadata.Category)
+	return project != "" && (category == project || strings.HasPrefix(category, project+"/"))
+}
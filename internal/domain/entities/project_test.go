@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeProject(t *testing.T) {
+	tests := map[string]string{
+		"Infra":          "infra",
+		" Infra//K8s/ ":  "infra/k8s",
+		"/ops / alerts/": "ops/alerts",
+		" / ":            "",
+	}
+
+	for name, want := range tests {
+		if got := NormalizeProject(name); got != want {
+			t.Errorf("NormalizeProject(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestProjectPath(t *testing.T) {
+	got := ProjectPath("Infra/k8s/Ingress")
+	if want := []string{"infra", "infra/k8s", "infra/k8s/ingress"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ProjectPath = %q, want %q", got, want)
+	}
+	if got := ProjectPath(""); got != nil {
+		t.Errorf("ProjectPath of no project = %q, want nil", got)
+	}
+}
+
+func TestInProject(t *testing.T) {
+	note := NewNote("upgrade the cluster")
+	note.Metadata.Category = "infra/k8s"
+
+	for project, want := range map[string]bool{
+		"infra":     true,
+		"Infra/K8s": true,
+		"infra/k8":  false,
+		"inf":       false,
+		"":          false,
+	} {
+		if got := note.InProject(project); got != want {
+			t.Errorf("InProject(%q) = %v, want %v", project, got, want)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package entities
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecurrenceRule represents how often a task or reminder repeats
+type RecurrenceRule string
+
+const (
+	RecurDaily    RecurrenceRule = "daily"
+	RecurWeekdays RecurrenceRule = "weekdays"
+	RecurWeekly   RecurrenceRule = "weekly"
+	RecurMonthly  RecurrenceRule = "monthly"
+	RecurAfter    RecurrenceRule = "after"
+)
+
+// Recurrence is the repeat rule of a task or reminder. Completing an item
+// that has one creates its next occurrence.
+type Recurrence struct {
+	Rule     RecurrenceRule `json:"rule"`
+	Weekdays []string       `json:"weekdays,omitempty"` // Weekly: "mon", "thu"
+	Day      int            `json:"day,omitempty"`      // Monthly: day of the month
+	Days     int            `json:"days,omitempty"`     // After: days after completion
+}
+
+// ParseRecurrence parses a rule written as daily, weekdays, weekly:mon,thu,
+// monthly:15 or after:3d. Weekly and monthly rules without days repeat on
+// the weekday or day of the month of from.
+func ParseRecurrence(s string, from time.Time) (*Recurrence, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	rule, arg, _ := strings.Cut(s, ":")
+	arg = strings.TrimSpace(arg)
+
+	switch RecurrenceRule(strings.TrimSpace(rule)) {
+	case RecurDaily:
+		return &Recurrence{Rule: RecurDaily}, nil
+	case RecurWeekdays:
+		return &Recurrence{Rule: RecurWeekdays}, nil
+	case RecurWeekly:
+		if arg == "" {
+			return &Recurrence{Rule: RecurWeekly, Weekdays: []string{weekdayName(from.Weekday())}}, nil
+		}
+		r := &Recurrence{Rule: RecurWeekly}
+		for _, name := range strings.Split(arg, ",") {
+			day, ok := ParseWeekday(strings.TrimSpace(name))
+			if !ok {
+				return nil, fmt.Errorf("invalid weekday %q in %q", name, s)
+			}
+			if !r.onWeekday(day) {
+				r.Weekdays = append(r.Weekdays, weekdayName(day))
+			}
+		}
+		return r, nil
+	case RecurMonthly:
+		if arg == "" {
+			return &Recurrence{Rule: RecurMonthly, Day: from.Day()}, nil
+		}
+		day, err := strconv.Atoi(strings.TrimRight(arg, "stndrh"))
+		if err != nil || day < 1 || day > 31 {
+			return nil, fmt.Errorf("invalid day of the month %q in %q", arg, s)
+		}
+		return &Recurrence{Rule: RecurMonthly, Day: day}, nil
+	case RecurAfter:
+		days, err := strconv.Atoi(strings.TrimSuffix(arg, "d"))
+		if err != nil || days < 1 {
+			return nil, fmt.Errorf("invalid number of days %q in %q, expected after:3d", arg, s)
+		}
+		return &Recurrence{Rule: RecurAfter, Days: days}, nil
+	}
+
+	return nil, fmt.Errorf("invalid repeat rule %q, expected daily, weekdays, weekly:mon,thu, monthly:15 or after:3d", s)
+}
+
+// String returns the rule in the form ParseRecurrence reads
+func (r *Recurrence) String() string {
+	switch r.Rule {
+	case RecurWeekly:
+		return fmt.Sprintf("%s:%s", r.Rule, strings.Join(r.Weekdays, ","))
+	case RecurMonthly:
+		return fmt.Sprintf("%s:%d", r.Rule, r.Day)
+	case RecurAfter:
+		return fmt.Sprintf("%s:%dd", r.Rule, r.Days)
+	}
+	return string(r.Rule)
+}
+
+// Describe returns the rule in words, such as "weekly on mon, thu"
+func (r *Recurrence) Describe() string {
+	switch r.Rule {
+	case RecurDaily:
+		return "every day"
+	case RecurWeekdays:
+		return "every weekday"
+	case RecurWeekly:
+		return "weekly on " + strings.Join(r.Weekdays, ", ")
+	case RecurMonthly:
+		return fmt.Sprintf("monthly on day %d", r.Day)
+	case RecurAfter:
+		if r.Days == 1 {
+			return "1 day after completion"
+		}
+		return fmt.Sprintf("%d days after completion", r.Days)
+	}
+	return string(r.Rule)
+}
+
+// Next returns the date of the occurrence after one scheduled on scheduled
+// and completed on completed. Fixed schedules continue from the later of
+// the two, so an item completed late does not leave a backlog of past
+// occurrences; after rules count from the completion.
+func (r *Recurrence) Next(scheduled, completed time.Time) time.Time {
+	done := startOfDay(completed)
+	if r.Rule == RecurAfter {
+		return done.AddDate(0, 0, r.Days)
+	}
+
+	after := startOfDay(scheduled)
+	if done.After(after) {
+		after = done
+	}
+
+	switch r.Rule {
+	case RecurMonthly:
+		year, month := after.Year(), after.Month()
+		for {
+			next := dayOfMonth(year, month, r.Day, after.Location())
+			if next.After(after) {
+				return next
+			}
+			month++
+			if month > time.December {
+				year, month = year+1, time.January
+			}
+		}
+	case RecurWeekdays, RecurWeekly:
+		if r.Rule == RecurWeekly && len(r.Weekdays) == 0 {
+			return after.AddDate(0, 0, 7)
+		}
+		next := after.AddDate(0, 0, 1)
+		for !r.repeatsOn(next.Weekday()) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next
+	}
+
+	return after.AddDate(0, 0, 1)
+}
+
+// repeatsOn reports whether a weekday or weekly rule repeats on day
+func (r *Recurrence) repeatsOn(day time.Weekday) bool {
+	if r.Rule == RecurWeekdays {
+		return day != time.Saturday && day != time.Sunday
+	}
+	return r.onWeekday(day)
+}
+
+// onWeekday reports whether day is one of the weekdays of the rule
+func (r *Recurrence) onWeekday(day time.Weekday) bool {
+	for _, name := range r.Weekdays {
+		if name == weekdayName(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScheduledDate returns the day the note is for: the due date of a task
+// that has one, otherwise the date it is filed under
+func (n *Note) ScheduledDate() time.Time {
+	if n.Type == NoteTypeTask && n.Metadata.DueDate != nil {
+		return *n.Metadata.DueDate
+	}
+	if date, err := time.ParseInLocation("2006-01-02", n.Date, time.Local); err == nil {
+		return date
+	}
+	return n.CreatedAt
+}
+
+// NextOccurrence returns a copy of a recurring note for its next date,
+// given the time it was completed, or nil when it does not repeat. The copy
+// is open again and filed under the new date; a task keeps its due date on
+// that date. Attachments and the link to a promoted idea stay with the
+// completed note.
+func (n *Note) NextOccurrence(completed time.Time) *Note {
+	if n.Metadata.Recurrence == nil {
+		return nil
+	}
+
+	date := n.Metadata.Recurrence.Next(n.ScheduledDate(), completed)
+	now := time.Now()
+
+	next := *n
+	next.ID = NewID()
+	next.CreatedAt = now
+	next.UpdatedAt = now
+	next.Date = date.Format("2006-01-02")
+	next.Metadata.Status = StatusToDo
+	next.Metadata.Attachments = nil
+	next.Metadata.LinkedNoteID = ""
+	next.Metadata.Tags = append([]string(nil), n.Metadata.Tags...)
+	next.Metadata.Hashtags = append([]string(nil), n.Metadata.Hashtags...)
+	recurrence := *n.Metadata.Recurrence
+	recurrence.Weekdays = append([]string(nil), recurrence.Weekdays...)
+	next.Metadata.Recurrence = &recurrence
+	if n.Metadata.DueDate != nil {
+		next.Metadata.DueDate = &date
+	}
+
+	return &next
+}
+
+// ParseWeekday parses a weekday name such as "fri" or "friday"
+func ParseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(s)
+	if len(s) < 3 {
+		return 0, false
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.HasPrefix(strings.ToLower(day.String()), s) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// weekdayName returns the short lower case name of a weekday, such as "mon"
+func weekdayName(day time.Weekday) string {
+	return strings.ToLower(day.String()[:3])
+}
+
+// startOfDay returns midnight of the day of t, in local time
+func startOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// dayOfMonth returns the given day of a month, or its last day for months
+// that are too short, so monthly:31 falls on 30 November
+func dayOfMonth(year int, month time.Month, day int, loc *time.Location) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
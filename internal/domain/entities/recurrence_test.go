@@ -0,0 +1,109 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+// day returns midnight of a day in October 2025, which starts on a Wednesday
+func day(month time.Month, d int) time.Time {
+	return time.Date(2025, month, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestParseRecurrence(t *testing.T) {
+	from := day(time.October, 16) // Thursday
+
+	for input, want := range map[string]string{
+		"daily":             "daily",
+		" Weekdays ":        "weekdays",
+		"weekly":            "weekly:thu",
+		"weekly:mon,thu":    "weekly:mon,thu",
+		"weekly:Monday,mon": "weekly:mon",
+		"monthly":           "monthly:16",
+		"monthly:31st":      "monthly:31",
+		"after:3d":          "after:3d",
+		"after:10":          "after:10d",
+	} {
+		r, err := ParseRecurrence(input, from)
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q) failed: %v", input, err)
+			continue
+		}
+		if r.String() != want {
+			t.Errorf("ParseRecurrence(%q) = %q, want %q", input, r.String(), want)
+		}
+	}
+
+	for _, input := range []string{"", "hourly", "weekly:someday", "monthly:32", "monthly:0", "after", "after:0d"} {
+		if _, err := ParseRecurrence(input, from); err == nil {
+			t.Errorf("ParseRecurrence(%q) succeeded, want an error", input)
+		}
+	}
+}
+
+func TestRecurrenceNext(t *testing.T) {
+	tests := []struct {
+		rule      string
+		scheduled time.Time
+		completed time.Time
+		want      time.Time
+	}{
+		{"daily", day(time.October, 16), day(time.October, 16), day(time.October, 17)},
+		{"weekdays", day(time.October, 17), day(time.October, 17), day(time.October, 20)}, // Friday to Monday
+		{"weekly:mon,thu", day(time.October, 13), day(time.October, 13), day(time.October, 16)},
+		{"weekly:mon,thu", day(time.October, 16), day(time.October, 16), day(time.October, 20)},
+		{"monthly:15", day(time.October, 15), day(time.October, 15), day(time.November, 15)},
+		{"monthly:31", day(time.October, 31), day(time.October, 31), day(time.November, 30)},
+		{"after:3d", day(time.October, 10), day(time.October, 16), day(time.October, 19)},
+		// Completed late, fixed rules continue from the completion
+		{"daily", day(time.October, 10), day(time.October, 16), day(time.October, 17)},
+		{"monthly:15", day(time.September, 15), day(time.October, 20), day(time.November, 15)},
+		// Completed early, fixed rules continue from the scheduled date
+		{"weekly:mon", day(time.October, 20), day(time.October, 16), day(time.October, 27)},
+	}
+
+	for _, tt := range tests {
+		r, err := ParseRecurrence(tt.rule, tt.scheduled)
+		if err != nil {
+			t.Fatalf("ParseRecurrence(%q) failed: %v", tt.rule, err)
+		}
+		got := r.Next(tt.scheduled, tt.completed.Add(15*time.Hour))
+		if !got.Equal(tt.want) {
+			t.Errorf("%s scheduled %s completed %s: Next = %s, want %s", tt.rule,
+				tt.scheduled.Format(DueDateLayout), tt.completed.Format(DueDateLayout),
+				got.Format(DueDateLayout), tt.want.Format(DueDateLayout))
+		}
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	task := NewTask("water the plants", PriorityLow)
+	if task.NextOccurrence(time.Now()) != nil {
+		t.Error("task without a repeat rule has a next occurrence")
+	}
+
+	due := day(time.October, 16)
+	task.Date = "2025-10-14"
+	task.Metadata.DueDate = &due
+	task.Metadata.Status = StatusCompleted
+	task.Metadata.Tags = []string{"home"}
+	task.Metadata.LinkedNoteID = "IDEA"
+	task.Metadata.Recurrence = &Recurrence{Rule: RecurWeekly, Weekdays: []string{"thu"}}
+
+	next := task.NextOccurrence(due.Add(10 * time.Hour))
+	if next.ID == task.ID || next.Metadata.Status != StatusToDo {
+		t.Errorf("next occurrence has ID %q and status %q, want a new open task", next.ID, next.Metadata.Status)
+	}
+	if next.Date != "2025-10-23" || !next.Metadata.DueDate.Equal(day(time.October, 23)) {
+		t.Errorf("next occurrence is filed under %s due %s, want 2025-10-23", next.Date, next.Metadata.DueDate.Format(DueDateLayout))
+	}
+	if next.Metadata.Recurrence == task.Metadata.Recurrence || next.Metadata.Recurrence.String() != "weekly:thu" {
+		t.Error("next occurrence does not have its own copy of the repeat rule")
+	}
+	if !next.HasTag("home") {
+		t.Error("next occurrence lost its tags")
+	}
+	if next.Metadata.LinkedNoteID != "" {
+		t.Errorf("next occurrence is linked to %s, want no link", next.Metadata.LinkedNoteID)
+	}
+}
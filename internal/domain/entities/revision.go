@@ -0,0 +1,11 @@
+package entities
+
+import "time"
+
+// Revision is one saved version of a note
+type Revision struct {
+	// Number counts the versions of a note, starting at 1
+	Number  int       `json:"number"`
+	SavedAt time.Time `json:"saved_at"`
+	Note    *Note     `json:"note"`
+}
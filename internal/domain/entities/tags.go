@@ -0,0 +1,126 @@
+package entities
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// hashtagPattern matches a #hashtag at the start of the text or after a
+// character that cannot be part of a word, so "a#b" and URL fragments are
+// not tags
+var hashtagPattern = regexp.MustCompile(`(^|[^\p{L}\p{N}_&/#])#([\p{L}\p{N}_][\p{L}\p{N}_/-]*)`)
+
+// NormalizeTag returns a tag in the form it is stored in: without a leading
+// '#', trimmed and lower case
+func NormalizeTag(tag string) string {
+	tag = strings.TrimSpace(tag)
+	tag = strings.TrimLeft(tag, "#")
+	return strings.ToLower(strings.TrimRight(tag, "-/"))
+}
+
+// ParseTags parses a list of tags separated by commas or spaces, such as
+// "infra, #deploy". Tags are normalized and duplicates removed.
+func ParseTags(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	return appendTags(nil, fields...)
+}
+
+// ParseHashtags returns the #hashtags of a text, normalized and without
+// duplicates. Numbers such as "#42" are left out, since they usually refer
+// to an issue rather than a topic.
+func ParseHashtags(text string) []string {
+	var tags []string
+	for _, match := range hashtagPattern.FindAllStringSubmatch(text, -1) {
+		if isNumber(match[2]) {
+			continue
+		}
+		tags = appendTags(tags, match[2])
+	}
+	return tags
+}
+
+// ReplaceHashtag rewrites the hashtag #from in text to #to, whatever the
+// case it was written in
+func ReplaceHashtag(text, from, to string) string {
+	return hashtagPattern.ReplaceAllStringFunc(text, func(match string) string {
+		i := strings.LastIndexByte(match, '#')
+		if NormalizeTag(match[i+1:]) != from {
+			return match
+		}
+		return match[:i+1] + to + match[i+1+len(strings.TrimRight(match[i+1:], "-/")):]
+	})
+}
+
+// CollectTags adds the hashtags of the content, and of the body of an idea,
+// to the tags of the note, and normalizes them. Tags that only came from
+// hashtags are recorded in Metadata.Hashtags, so they go away again when
+// their hashtag is removed from the text; tags added explicitly stay.
+func (n *Note) CollectTags() {
+	hashtags := appendTags(ParseHashtags(n.Content), ParseHashtags(n.Metadata.Body)...)
+	previous := n.Metadata.Hashtags
+
+	var tags []string
+	for _, tag := range appendTags(nil, n.Metadata.Tags...) {
+		if containsTag(previous, tag) && !containsTag(hashtags, tag) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	// A hashtag repeating an explicit tag does not make it derived
+	var derived []string
+	for _, tag := range hashtags {
+		if containsTag(previous, tag) || !containsTag(tags, tag) {
+			derived = append(derived, tag)
+		}
+	}
+
+	n.Metadata.Tags = appendTags(tags, hashtags...)
+	n.Metadata.Hashtags = derived
+}
+
+// HasTag reports whether the note has the given tag
+func (n *Note) HasTag(tag string) bool {
+	tag = NormalizeTag(tag)
+	for _, t := range n.Metadata.Tags {
+		if NormalizeTag(t) == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// appendTags appends the tags that are not empty and not in tags yet
+func appendTags(tags []string, more ...string) []string {
+	for _, tag := range more {
+		tag = NormalizeTag(tag)
+		if tag == "" || containsTag(tags, tag) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// containsTag reports whether a list of normalized tags holds tag
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// isNumber reports whether s only holds digits
+func isNumber(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHashtags(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"deploy the #Infra change #deploy", []string{"infra", "deploy"}},
+		{"#infra at the start, #infra again", []string{"infra"}},
+		{"fixes #42 in #backend", []string{"backend"}},
+		{"see https://example.com/page#section and a#b", nil},
+		{"nested #work/infra-ops.", []string{"work/infra-ops"}},
+	}
+
+	for _, test := range tests {
+		if got := ParseHashtags(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseHashtags(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	got := ParseTags(" Infra, #deploy  infra,,")
+	if want := []string{"infra", "deploy"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTags = %q, want %q", got, want)
+	}
+}
+
+func TestReplaceHashtag(t *testing.T) {
+	got := ReplaceHashtag("move #Infra to #infrastructure, see a#infra", "infra", "ops")
+	if want := "move #ops to #infrastructure, see a#infra"; got != want {
+		t.Errorf("ReplaceHashtag = %q, want %q", got, want)
+	}
+}
+
+func TestCollectTags(t *testing.T) {
+	note := NewIdea("Cache the #API responses", "ask #ops about it", MaturityRaw)
+	note.Metadata.Tags = []string{"Backend", "api"}
+	note.CollectTags()
+
+	if want := []string{"backend", "api", "ops"}; !reflect.DeepEqual(note.Metadata.Tags, want) {
+		t.Errorf("tags = %q, want %q", note.Metadata.Tags, want)
+	}
+	if !note.HasTag("#OPS") {
+		t.Error("HasTag does not normalize the tag it is given")
+	}
+}
+
+func TestCollectTagsFollowsHashtags(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []string
+		hashtags []string
+		content  string
+		want     []string
+		derived  []string
+	}{
+		{"new hashtag", nil, nil, "call #ops", []string{"ops"}, []string{"ops"}},
+		{"hashtag kept", []string{"ops"}, []string{"ops"}, "call #ops again", []string{"ops"}, []string{"ops"}},
+		{"hashtag removed", []string{"ops", "work"}, []string{"ops"}, "call them", []string{"work"}, nil},
+		{"hashtag renamed", []string{"ops"}, []string{"ops"}, "call #sre", []string{"sre"}, []string{"sre"}},
+		{"explicit tag outlives its hashtag", []string{"Work"}, nil, "at #work", []string{"work"}, nil},
+		{"explicit tag without hashtag", []string{"work"}, nil, "call them", []string{"work"}, nil},
+		{"tags from before hashtags were tracked stay", []string{"ops"}, nil, "call them", []string{"ops"}, nil},
+	}
+
+	for _, tt := range tests {
+		note := NewNote(tt.content)
+		note.Metadata.Tags = tt.tags
+		note.Metadata.Hashtags = tt.hashtags
+		note.CollectTags()
+
+		if !reflect.DeepEqual(note.Metadata.Tags, tt.want) || !reflect.DeepEqual(note.Metadata.Hashtags, tt.derived) {
+			t.Errorf("%s: tags = %q and hashtags = %q, want %q and %q",
+				tt.name, note.Metadata.Tags, note.Metadata.Hashtags, tt.want, tt.derived)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package entities
+
+import "time"
+
+// Tombstone records that a note was deleted, so the deletion can be passed
+// on to other copies of the notes
+type Tombstone struct {
+	ID        string    `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
@@ -0,0 +1,9 @@
+package entities
+
+import "time"
+
+// TrashedNote represents a note that has been moved to the trash
+type TrashedNote struct {
+	Note      *Note     `json:"note"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"io"
+	"jotterxpress/internal/domain/entities"
+)
+
+// AttachmentService defines the interface for files attached to notes
+type AttachmentService interface {
+	// Attach stores content and records it on the note under name
+	Attach(note *entities.Note, name string, content io.Reader) (*entities.Attachment, error)
+
+	// OpenAttachment returns an attachment of a note and its content. ref is
+	// the attachment number as listed, starting at 1, or its name.
+	OpenAttachment(note *entities.Note, ref string) (*entities.Attachment, io.ReadCloser, error)
+
+	// CollectGarbage removes stored content that no note, trashed note or
+	// revision refers to, and returns how many files were removed
+	CollectGarbage() (int, error)
+}
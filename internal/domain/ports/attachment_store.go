@@ -0,0 +1,20 @@
+package ports
+
+import "io"
+
+// AttachmentStore defines the interface for attachment content, addressed by
+// the hex SHA-256 of the content
+type AttachmentStore interface {
+	// Put stores content and returns its SHA-256 and size. Storing content
+	// that is already present keeps a single copy.
+	Put(content io.Reader) (sha256 string, size int64, err error)
+
+	// Open returns the content stored under a SHA-256
+	Open(sha256 string) (io.ReadCloser, error)
+
+	// List returns the SHA-256 of every stored content
+	List() ([]string, error)
+
+	// Remove deletes the content stored under a SHA-256
+	Remove(sha256 string) error
+}
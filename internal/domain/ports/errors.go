@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNoteNotFound is returned when a note with the requested ID does not exist
+	ErrNoteNotFound = errors.New("note not found")
+
+	// ErrCorruptData is returned when stored notes cannot be decoded
+	ErrCorruptData = errors.New("corrupt data")
+)
+
+// AmbiguousIDError is returned when an ID prefix matches more than one note
+type AmbiguousIDError struct {
+	Prefix  string
+	Matches []string
+}
+
+func (e *AmbiguousIDError) Error() string {
+	return fmt.Sprintf("ID prefix %q matches %d notes: %s", e.Prefix, len(e.Matches), strings.Join(e.Matches, ", "))
+}
@@ -0,0 +1,45 @@
+package ports
+
+import "jotterxpress/internal/domain/entities"
+
+// NoteEventKind identifies the kind of change made to a note
+type NoteEventKind string
+
+const (
+	NoteCreated   NoteEventKind = "create"
+	NoteUpdated   NoteEventKind = "update"
+	NoteCompleted NoteEventKind = "complete"
+	NoteDeleted   NoteEventKind = "delete"
+	NoteRestored  NoteEventKind = "restore"
+	NoteReverted  NoteEventKind = "revert"
+	NoteMoved     NoteEventKind = "move"
+	NotePromoted  NoteEventKind = "promote"
+	TrashEmptied  NoteEventKind = "empty-trash"
+)
+
+// NoteEvent describes a change made through the NoteService
+type NoteEvent struct {
+	Kind NoteEventKind
+
+	// NoteID is the ID of the changed note; empty for TrashEmptied
+	NoteID string
+
+	// Note is the note after the change, or before it for NoteDeleted.
+	// It is nil when the note is unknown.
+	Note *entities.Note
+
+	// Count is the number of notes removed for TrashEmptied
+	Count int
+}
+
+// NoteListener is notified after the NoteService has stored a change
+type NoteListener interface {
+	NoteChanged(event NoteEvent)
+}
+
+// NoteGuard is asked before the NoteService stores a change and can refuse
+// it by returning an error. Listeners passed to the NoteService that also
+// implement NoteGuard are asked before every change.
+type NoteGuard interface {
+	BeforeChange(event NoteEvent) error
+}
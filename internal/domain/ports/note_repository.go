@@ -2,13 +2,25 @@ package ports
 
 import (
 	"jotterxpress/internal/domain/entities"
+	"time"
 )
 
 // NoteRepository defines the interface for note persistence
 type NoteRepository interface {
-	// Save saves a note to the repository
+	// Save adds a note or replaces the note with the same ID, also when it
+	// is stored under another date
 	Save(note *entities.Note) error
 
+	// MoveNote stores a note under a new date and removes it from its old
+	// date, so it is never listed twice
+	MoveNote(note *entities.Note, date string) error
+
+	// GetNoteByID retrieves a note by its exact ID
+	GetNoteByID(id string) (*entities.Note, error)
+
+	// FindNoteIDs returns the IDs of all notes whose ID starts with prefix
+	FindNoteIDs(prefix string) ([]string, error)
+
 	// GetNotesByDate retrieves all notes for a specific date
 	GetNotesByDate(date string) ([]*entities.Note, error)
 
@@ -21,6 +33,22 @@ type NoteRepository interface {
 	// GetNotesByMonth retrieves notes for a specific month (format: "2025-10")
 	GetNotesByMonth(monthStr string) ([]*entities.Note, error)
 
-	// DeleteNote deletes a note by ID
+	// GetAllNotes retrieves every note in the repository
+	GetAllNotes() ([]*entities.Note, error)
+
+	// GetRevisions retrieves every saved version of a note, oldest first.
+	// The last revision is the current version.
+	GetRevisions(id string) ([]*entities.Revision, error)
+
+	// DeleteNote moves a note to the trash by ID
 	DeleteNote(id string) error
+
+	// GetTrash retrieves all notes currently in the trash
+	GetTrash() ([]*entities.TrashedNote, error)
+
+	// RestoreNote moves a note from the trash back to its date
+	RestoreNote(id string) (*entities.Note, error)
+
+	// EmptyTrash permanently removes trashed notes deleted before the given time
+	EmptyTrash(before time.Time) (int, error)
 }
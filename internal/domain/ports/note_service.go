@@ -2,6 +2,7 @@ package ports
 
 import (
 	"jotterxpress/internal/domain/entities"
+	"time"
 )
 
 // NoteService defines the interface for note business logic
@@ -12,6 +13,31 @@ type NoteService interface {
 	// SaveNote saves an existing note
 	SaveNote(note *entities.Note) error
 
+	// GetNoteByID retrieves a note by its full ID, a unique ID prefix or its ID alias
+	GetNoteByID(ref string) (*entities.Note, error)
+
+	// GetHistory retrieves every saved version of a note, oldest first
+	GetHistory(ref string) ([]*entities.Revision, error)
+
+	// RevertNote restores the content and metadata of a note from one of its revisions
+	RevertNote(note *entities.Note, number int) error
+
+	// MoveNote reschedules a note to another date (format: "2025-10-31")
+	MoveNote(note *entities.Note, date string) error
+
+	// CompleteNote marks a task or reminder as completed. Completing a
+	// recurring item creates its next occurrence, which is returned; it is
+	// nil otherwise.
+	CompleteNote(note *entities.Note) (*entities.Note, error)
+
+	// GetRecurring retrieves the open tasks and reminders of every date that
+	// have a repeat rule, the earliest scheduled first
+	GetRecurring() ([]*entities.Note, error)
+
+	// PromoteIdea creates a task from an idea and links the two; the idea
+	// becomes promoted
+	PromoteIdea(idea *entities.Note, priority entities.Priority) (*entities.Note, error)
+
 	// GetTodayNotes retrieves all notes for today
 	GetTodayNotes() ([]*entities.Note, error)
 
@@ -21,6 +47,23 @@ type NoteService interface {
 	// GetNotesByMonth retrieves notes for a specific month (format: "2025-10")
 	GetNotesByMonth(monthStr string) ([]*entities.Note, error)
 
+	// GetDueTasks retrieves the open tasks of every date that are due at
+	// most days from today, earliest first. Overdue tasks are included;
+	// days of -1 returns only them.
+	GetDueTasks(days int) ([]*entities.Note, error)
+
+	// DeleteNote moves a note to the trash
+	DeleteNote(id string) error
+
+	// GetTrash retrieves all notes currently in the trash
+	GetTrash() ([]*entities.TrashedNote, error)
+
+	// RestoreNote restores a note from the trash by its ID or ID alias
+	RestoreNote(id string) (*entities.Note, error)
+
+	// EmptyTrash permanently removes trashed notes older than the given age
+	EmptyTrash(olderThan time.Duration) (int, error)
+
 	// ListNotes formats and returns notes for display
 	ListNotes(notes []*entities.Note) string
 }
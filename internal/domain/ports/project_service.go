@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"jotterxpress/internal/domain/entities"
+	"time"
+)
+
+// Project is a project with the progress of its notes. The counts include
+// the notes of its subprojects.
+type Project struct {
+	Name           string
+	Notes          int
+	OpenTasks      int
+	CompletedTasks int
+	LastActivity   time.Time
+}
+
+// ProjectService defines the interface for grouping notes by project
+type ProjectService interface {
+	// GetProjects retrieves every project in use, and the projects above
+	// them, sorted by name
+	GetProjects() ([]Project, error)
+
+	// GetProject retrieves a project and the notes in it or its subprojects,
+	// most recently updated first
+	GetProject(name string) (*Project, []*entities.Note, error)
+}
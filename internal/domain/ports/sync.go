@@ -0,0 +1,72 @@
+package ports
+
+import (
+	"jotterxpress/internal/domain/entities"
+	"time"
+)
+
+// SyncStore defines the interface for a copy of the notes that other
+// machines sync with, such as a shared folder
+type SyncStore interface {
+	// GetNotes retrieves every note in the store
+	GetNotes() ([]*entities.Note, error)
+
+	// GetTombstones retrieves the deletions recorded in the store
+	GetTombstones() ([]*entities.Tombstone, error)
+
+	// SaveNote stores a note as it is, replacing any tombstone of its ID
+	SaveNote(note *entities.Note) error
+
+	// DeleteNote removes a note and records a tombstone in its place
+	DeleteNote(tombstone *entities.Tombstone) error
+}
+
+// SyncResolution says how a sync conflict is resolved
+type SyncResolution string
+
+const (
+	// KeepLocal keeps the local version on both sides
+	KeepLocal SyncResolution = "local"
+
+	// KeepRemote keeps the remote version on both sides
+	KeepRemote SyncResolution = "remote"
+
+	// KeepBoth keeps the version updated last and saves the other as a
+	// conflict copy, a new note on both sides
+	KeepBoth SyncResolution = "both"
+)
+
+// SyncConflict is a note changed on both sides since they were last synced
+type SyncConflict struct {
+	Local  *entities.Note
+	Remote *entities.Note
+}
+
+// ConflictResolver decides how a sync conflict is resolved
+type ConflictResolver func(conflict SyncConflict) SyncResolution
+
+// SyncReport describes what a sync changed
+type SyncReport struct {
+	Pulled        int
+	Pushed        int
+	DeletedLocal  int
+	DeletedRemote int
+	Conflicts     int
+
+	// ConflictCopies are the notes created to keep both sides of a conflict
+	ConflictCopies []*entities.Note
+
+	// Synced holds the UpdatedAt of every note both sides now share, to be
+	// passed as base to the next sync with the same store
+	Synced map[string]time.Time
+}
+
+// SyncService defines the interface for syncing notes with a SyncStore
+type SyncService interface {
+	// Sync merges the notes of the repository and remote by ID. The version
+	// updated last wins, and tombstones on either side delete the note on
+	// the other unless it was updated after the deletion. base holds the
+	// UpdatedAt of every note at the last sync; a note changed on both sides
+	// since then is a conflict and is passed to resolve.
+	Sync(remote SyncStore, base map[string]time.Time, resolve ConflictResolver) (*SyncReport, error)
+}
@@ -0,0 +1,21 @@
+package ports
+
+// TagCount is a tag and the number of notes that have it
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// TagService defines the interface for managing tags across all notes
+type TagService interface {
+	// GetTags retrieves every tag in use, most used first
+	GetTags() ([]TagCount, error)
+
+	// RenameTag renames a tag on every note, including its #hashtags, and
+	// returns how many notes changed
+	RenameTag(from, to string) (int, error)
+
+	// MergeTags replaces several tags with one on every note and returns how
+	// many notes changed
+	MergeTags(from []string, into string) (int, error)
+}